@@ -6,6 +6,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/vmware/cloud-provider-for-cloud-director/pkg/ccm"
 	"github.com/vmware/cloud-provider-for-cloud-director/version"
@@ -26,6 +27,8 @@ import (
 	"time"
 )
 
+var tracingOpts = ccm.NewTracingOptions()
+
 func init() {
 	healthz.InstallHandler(http.DefaultServeMux)
 
@@ -44,11 +47,14 @@ func main() {
 	opts.KubeCloudShared.CloudProvider.Name = ccm.ProviderName
 	opts.Authentication.SkipInClusterLookup = true
 
+	additionalFlags := flag.NamedFlagSets{}
+	tracingOpts.AddFlags(additionalFlags.FlagSet("tracing"))
+
 	command := app.NewCloudControllerManagerCommand(
 		opts,
 		vcdCCMInitializer,
 		app.DefaultInitFuncConstructors,
-		flag.NamedFlagSets{},
+		additionalFlags,
 		wait.NeverStop,
 	)
 
@@ -69,6 +75,11 @@ func main() {
 func vcdCCMInitializer(cfg *config.CompletedConfig) cloudprovider.Interface {
 	cloudConfig := cfg.ComponentConfig.KubeCloudShared.CloudProvider
 
+	// the exporter runs until the process exits
+	if _, err := tracingOpts.SetupTracing(context.Background()); err != nil {
+		klog.Fatalf("Tracing could not be set up: [%v]", err)
+	}
+
 	// initialize cloud provider with the cloud provider name and config file provided
 	cloud, err := cloudprovider.InitCloudProvider(cloudConfig.Name, cloudConfig.CloudConfigFile)
 	if err != nil {
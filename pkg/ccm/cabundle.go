@@ -0,0 +1,61 @@
+//go:build !testing
+// +build !testing
+
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// loadCABundle returns the PEM bundle of the CAs that are trusted for the VCD site from the CA file or the Secret or
+// ConfigMap it references, or nil if no CA bundle is configured.
+func loadCABundle(ctx context.Context, tlsConfig config.TLSConfig) ([]byte, error) {
+	if tlsConfig.CAFile != "" {
+		caBundle, err := os.ReadFile(tlsConfig.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA file [%s]: [%v]", tlsConfig.CAFile, err)
+		}
+		return caBundle, nil
+	}
+
+	caRef := tlsConfig.CARef
+	if caRef == nil {
+		return nil, nil
+	}
+	switch caRef.Kind {
+	case config.CAReferenceKindSecret:
+		secret, err := GetK8SClient().CoreV1().Secrets(caRef.Namespace).Get(ctx, caRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get CA secret [%s/%s]: [%v]", caRef.Namespace, caRef.Name, err)
+		}
+		if caBundle, ok := secret.Data[caRef.Key]; ok {
+			return caBundle, nil
+		}
+	case config.CAReferenceKindConfigMap:
+		configMap, err := GetK8SClient().CoreV1().ConfigMaps(caRef.Namespace).Get(ctx, caRef.Name,
+			metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get CA configmap [%s/%s]: [%v]", caRef.Namespace, caRef.Name, err)
+		}
+		if caBundle, ok := configMap.Data[caRef.Key]; ok {
+			return []byte(caBundle), nil
+		}
+		if caBundle, ok := configMap.BinaryData[caRef.Key]; ok {
+			return caBundle, nil
+		}
+	default:
+		return nil, fmt.Errorf("invalid CA reference kind [%s]", caRef.Kind)
+	}
+	return nil, fmt.Errorf("key [%s] not found in CA %s [%s/%s]", caRef.Key, caRef.Kind, caRef.Namespace,
+		caRef.Name)
+}
@@ -21,6 +21,7 @@ import (
 	_ "k8s.io/client-go/tools/clientcmd"
 	cloudProvider "k8s.io/cloud-provider"
 	"k8s.io/klog"
+	"os"
 	"time"
 )
 
@@ -31,11 +32,18 @@ const (
 
 // VCDCloudProvider - contains all of the interfaces for our cloud provider
 type VCDCloudProvider struct {
-	vcdClient   *vcdsdk.Client
-	lb          cloudProvider.LoadBalancer
-	instances   cloudProvider.Instances
-	instancesV2 cloudProvider.InstancesV2
-	zoneMap     *vcdsdk.ZoneMap
+	vcdClient            *vcdsdk.Client
+	lb                   cloudProvider.LoadBalancer
+	routes               cloudProvider.Routes
+	instances            cloudProvider.Instances
+	instancesV2          cloudProvider.InstancesV2
+	zones                cloudProvider.Zones
+	zoneMap              *vcdsdk.ZoneMap
+	vmInfoCache          *VmInfoCache
+	vmInventory          config.VMInventoryConfig
+	zoneReloadInterval   time.Duration
+	configReloader       *configReloader
+	configReloadInterval time.Duration
 }
 
 var _ cloudProvider.Interface = &VCDCloudProvider{}
@@ -47,10 +55,13 @@ func init() {
 func newVCDCloudProvider(configReader io.Reader) (cloudProvider.Interface, error) {
 	var vcdClient *vcdsdk.Client = nil
 	var cloudConfig *config.CloudConfig = nil
+	var caBundle []byte = nil
+	registerMetrics()
 	cloudConfig, err := config.ParseCloudConfig(configReader)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse config: [%v]", err)
 	}
+	rtp := newRefreshTokenPersister(cloudConfig.VCD.BasicAuthSecret)
 	for {
 		err = config.SetAuthorization(cloudConfig)
 		if err != nil {
@@ -58,6 +69,10 @@ func newVCDCloudProvider(configReader io.Reader) (cloudProvider.Interface, error
 			time.Sleep(10 * time.Second)
 			continue
 		}
+		if refreshToken := rtp.getLatestRefreshToken(); refreshToken != "" {
+			// a previous attempt rotated the refresh token, which may not be in the mounted secret yet
+			cloudConfig.VCD.RefreshToken = refreshToken
+		}
 
 		err = config.ValidateCloudConfig(cloudConfig)
 		if err != nil {
@@ -66,17 +81,51 @@ func newVCDCloudProvider(configReader io.Reader) (cloudProvider.Interface, error
 			continue
 		}
 
-		vcdClient, err = vcdsdk.NewVCDClientFromSecrets(
-			cloudConfig.VCD.Host,
-			cloudConfig.VCD.Org,
-			cloudConfig.VCD.VDC,
-			cloudConfig.VCD.UserOrg,
-			cloudConfig.VCD.User,
-			cloudConfig.VCD.Secret,
-			cloudConfig.VCD.RefreshToken,
-			true,
-			true,
-		)
+		caBundle, err = loadCABundle(context.Background(), cloudConfig.VCD.TLS)
+		if err != nil {
+			klog.Infof("Unable to load CA bundle: [%v]", err)
+			time.Sleep(10 * time.Second)
+			continue
+		}
+		transportConfig, err := getTransportConfig(cloudConfig, caBundle)
+		if err != nil {
+			klog.Infof("Unable to get transport config: [%v]", err)
+			time.Sleep(10 * time.Second)
+			continue
+		}
+		insecure := config.IsInsecureTLS(cloudConfig)
+		if insecure && cloudConfig.VCD.TLS.Insecure == nil {
+			klog.Warningf("Certificate of VCD site [%s] will not be verified since no CA bundle is configured",
+				cloudConfig.VCD.Host)
+		}
+
+		if cloudConfig.VCD.ServiceAccountClientID != "" {
+			vcdClient, err = vcdsdk.NewVCDClientFromServiceAccount(
+				cloudConfig.VCD.Host,
+				cloudConfig.VCD.Org,
+				cloudConfig.VCD.VDC,
+				cloudConfig.VCD.UserOrg,
+				cloudConfig.VCD.ServiceAccountClientID,
+				cloudConfig.VCD.RefreshToken,
+				rtp.persist,
+				insecure,
+				transportConfig,
+				true,
+			)
+		} else {
+			vcdClient, err = vcdsdk.NewVCDClientFromSecretsWithTransport(
+				cloudConfig.VCD.Host,
+				cloudConfig.VCD.Org,
+				cloudConfig.VCD.VDC,
+				cloudConfig.VCD.UserOrg,
+				cloudConfig.VCD.User,
+				cloudConfig.VCD.Secret,
+				cloudConfig.VCD.RefreshToken,
+				insecure,
+				transportConfig,
+				true,
+			)
+		}
 		if err == nil {
 			break
 		}
@@ -99,9 +148,20 @@ func newVCDCloudProvider(configReader io.Reader) (cloudProvider.Interface, error
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GatewayManager: [%v]", err)
 	}
+	var routeAdvertisement *vcdsdk.RouteAdvertisement
+	if cloudConfig.LB.RouteAdvertisement != nil && cloudConfig.LB.RouteAdvertisement.Enabled {
+		routeAdvertisement = &vcdsdk.RouteAdvertisement{
+			Subnets:       cloudConfig.LB.RouteAdvertisement.Subnets,
+			BGPPrefixList: cloudConfig.LB.RouteAdvertisement.BGPPrefixList,
+		}
+	}
 	if !gm.IsNSXTBackedGateway() {
 		klog.Infof("Gateway of network [%s] not backed by NSX-T. Hence LB will not be initialized.",
 			cloudConfig.LB.VDCNetwork)
+	} else if cloudConfig.LB.Mode == config.LBModeNATOnly {
+		klog.Infof("Using NAT-only load balancer on the gateway of network [%s].", cloudConfig.LB.VDCNetwork)
+		lb = newNATLoadBalancer(vcdClient, cloudConfig.LB.VDCNetwork, cloudConfig.VCD.VDC, cloudConfig.LB.VIPSubnet,
+			cloudConfig.ClusterID, routeAdvertisement)
 	} else {
 		var oneArm *vcdsdk.OneArm
 		if cloudConfig.LB.OneArm != nil {
@@ -110,8 +170,20 @@ func newVCDCloudProvider(configReader io.Reader) (cloudProvider.Interface, error
 				EndIP:   cloudConfig.LB.OneArm.EndIP,
 			}
 		}
-		lb = newLoadBalancer(vcdClient, cloudConfig.LB.CertificateAlias, oneArm, cloudConfig.LB.VDCNetwork, cloudConfig.VCD.VDC,
-			cloudConfig.LB.VIPSubnet, cloudConfig.ClusterID, cloudConfig.LB.EnableVirtualServiceSharedIP)
+		lb = newLoadBalancer(vcdClient, gm, cloudConfig.LB.CertificateAlias, oneArm, cloudConfig.LB.VDCNetwork, cloudConfig.VCD.VDC,
+			cloudConfig.LB.VIPSubnet, cloudConfig.ClusterID, cloudConfig.LB.EnableVirtualServiceSharedIP, routeAdvertisement)
+	}
+
+	// setup routes only if requested and the gateway is NSX-T
+	var routes cloudProvider.Routes = nil
+	if cloudConfig.Routes.Enabled {
+		if gm.IsNSXTBackedGateway() {
+			routes = newRoutes(vcdClient, cloudConfig.LB.VDCNetwork, cloudConfig.VCD.VDC, cloudConfig.LB.VIPSubnet,
+				cloudConfig.ClusterID)
+		} else {
+			klog.Infof("Gateway of network [%s] not backed by NSX-T. Hence routes will not be initialized.",
+				cloudConfig.LB.VDCNetwork)
+		}
 	}
 
 	// TODO: upgrade all CAPVCD RDEs here
@@ -142,22 +214,63 @@ func newVCDCloudProvider(configReader io.Reader) (cloudProvider.Interface, error
 
 	var zm *vcdsdk.ZoneMap = nil
 	if cloudConfig.VCD.IsZoneEnabledCluster {
-		if zm, err = vcdsdk.NewZoneMap(vcdsdk.ZoneMapConfigFilePath); err != nil {
+		if cloudConfig.Zones.Source == config.ZoneSourceVDCGroup {
+			if zm, err = vcdsdk.NewZoneMapFromVDCGroup(vcdClient, cloudConfig.Zones.VDCGroup,
+				cloudConfig.Zones.ZoneNames); err != nil {
+				return nil, fmt.Errorf("unable to create new zone map from VDC group [%s]: [%v]",
+					cloudConfig.Zones.VDCGroup, err)
+			}
+		} else if zm, err = vcdsdk.NewZoneMap(vcdsdk.ZoneMapConfigFilePath); err != nil {
 			return nil, fmt.Errorf("unable to create new zone map from configmap file [%s]: [%v]",
 				vcdsdk.ZoneMapConfigFilePath, err)
 		}
 	}
 
-	// cache for VM Info with an refresh of elements needed after 1 minute
-	vmInfoCache := newVmInfoCache(vcdClient, cloudConfig.VAppName, time.Minute, zm)
+	region, err := config.GetRegion(cloudConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get region: [%v]", err)
+	}
+	klog.Infof("Using region [%s] for nodes", region)
+
+	// cache for VM Info with an refresh of elements needed after 1 minute, or after two inventory refreshes if longer
+	vmInfoCacheExpiry := time.Minute
+	inventoryExpiry := 2 * time.Duration(cloudConfig.VMInventory.RefreshIntervalSeconds) * time.Second
+	if cloudConfig.VMInventory.Enabled && inventoryExpiry > vmInfoCacheExpiry {
+		vmInfoCacheExpiry = inventoryExpiry
+	}
+	var nar *natAddressResolver = nil
+	if cloudConfig.NodeAddresses.ExternalIPFromNAT {
+		nar = newNATAddressResolver(vcdClient, cloudConfig.LB.VDCNetwork, cloudConfig.VCD.VDC, cloudConfig.LB.VIPSubnet,
+			time.Minute)
+	}
+	vmInfoCache := newVmInfoCache(vcdClient, cloudConfig.VAppName, vmInfoCacheExpiry, zm, cloudConfig.NodeLabels,
+		cloudConfig.NodeAddresses, cloudConfig.VMDiscovery, nar)
+
+	// the cloud config can only be re-read if it was passed as a file
+	var cr *configReloader = nil
+	if cloudConfig.ConfigReload.Enabled {
+		if configFile, ok := configReader.(*os.File); ok {
+			cr = newConfigReloader(vcdClient, cpiRdeManager, configFile.Name(), config.BasicAuthSecretDir,
+				cloudConfig, caBundle, rtp)
+		} else {
+			klog.Infof("Cloud config was not passed as a file. Hence config and credentials will not be reloaded.")
+		}
+	}
 
 	// TODO: Do we need to record anything from instances from errors/events aspect?
 	return &VCDCloudProvider{
-		vcdClient:   vcdClient,
-		lb:          lb,
-		instances:   newInstances(vmInfoCache),
-		instancesV2: newInstancesV2(vmInfoCache, zm),
-		zoneMap:     zm,
+		vcdClient:            vcdClient,
+		lb:                   lb,
+		routes:               routes,
+		instances:            newInstances(vmInfoCache),
+		instancesV2:          newInstancesV2(vmInfoCache, region),
+		zones:                newZones(vmInfoCache, region),
+		zoneMap:              zm,
+		vmInfoCache:          vmInfoCache,
+		vmInventory:          cloudConfig.VMInventory,
+		zoneReloadInterval:   time.Duration(cloudConfig.Zones.ReloadIntervalSeconds) * time.Second,
+		configReloader:       cr,
+		configReloadInterval: time.Duration(cloudConfig.ConfigReload.IntervalSeconds) * time.Second,
 	}, nil
 }
 
@@ -169,6 +282,17 @@ func (vcdCP *VCDCloudProvider) Initialize(clientBuilder cloudProvider.Controller
 	sharedInformer.Start(nil)
 	sharedInformer.WaitForCacheSync(nil)
 
+	if vcdCP.configReloader != nil {
+		vcdCP.configReloader.startConfigReloader(vcdCP.configReloadInterval, stop)
+	}
+	if vcdCP.zoneMap != nil {
+		vcdCP.zoneMap.StartZoneMapReloader(vcdCP.zoneReloadInterval, stop)
+	}
+	if vcdCP.vmInventory.Enabled {
+		vcdCP.vmInfoCache.startInventoryRefresher(
+			time.Duration(vcdCP.vmInventory.RefreshIntervalSeconds)*time.Second, stop)
+	}
+
 	return
 }
 
@@ -188,11 +312,6 @@ func (vcdCP *VCDCloudProvider) LoadBalancer() (cloudProvider.LoadBalancer, bool)
 	return vcdCP.lb, true
 }
 
-// Zones returns a zones interface. Also returns true if the interface is supported, false otherwise.
-func (vcdCP *VCDCloudProvider) Zones() (cloudProvider.Zones, bool) {
-	return nil, false
-}
-
 // Clusters returns a clusters interface.  Also returns true if the interface is supported, false otherwise.
 func (vcdCP *VCDCloudProvider) Clusters() (cloudProvider.Clusters, bool) {
 	return nil, false
@@ -200,7 +319,12 @@ func (vcdCP *VCDCloudProvider) Clusters() (cloudProvider.Clusters, bool) {
 
 // Routes returns a routes interface along with whether the interface is supported.
 func (vcdCP *VCDCloudProvider) Routes() (cloudProvider.Routes, bool) {
-	return nil, false
+	if vcdCP.routes == nil {
+		// routes will be nil if not enabled in the config or if the organization network is not backed by NSX-T
+		klog.Infof("route controller will be disabled")
+		return nil, false
+	}
+	return vcdCP.routes, true
 }
 
 // HasClusterID provides an opportunity for cloud-provider-specific code to process DNS settings for pods.
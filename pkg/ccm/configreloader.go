@@ -0,0 +1,170 @@
+//go:build !testing
+// +build !testing
+
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/config"
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/cpisdk"
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdsdk"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+// configReloader re-reads the cloud config file, the basic-auth secret and the CA bundle, and re-authenticates the vcd
+// client when the credentials or trusted CAs are rotated. Other changes to the cloud config are only reported since
+// they need a restart.
+type configReloader struct {
+	vcdClient      *vcdsdk.Client
+	cpiRdeManager  *cpisdk.CPIRDEManager
+	configFilePath string
+	secretDir      string
+	// lastConfig and lastCABundle were last read; they are not retried until they change again
+	lastConfig   *config.CloudConfig
+	lastCABundle []byte
+	// refreshTokenPersister is set if the client authenticates as a service account
+	refreshTokenPersister *refreshTokenPersister
+}
+
+func newConfigReloader(vcdClient *vcdsdk.Client, cpiRdeManager *cpisdk.CPIRDEManager, configFilePath string,
+	secretDir string, cloudConfig *config.CloudConfig, caBundle []byte,
+	refreshTokenPersister *refreshTokenPersister) *configReloader {
+	return &configReloader{
+		vcdClient:             vcdClient,
+		cpiRdeManager:         cpiRdeManager,
+		configFilePath:        configFilePath,
+		secretDir:             secretDir,
+		lastConfig:            cloudConfig,
+		lastCABundle:          caBundle,
+		refreshTokenPersister: refreshTokenPersister,
+	}
+}
+
+func (cr *configReloader) readConfig() (*config.CloudConfig, error) {
+	configFileReader, err := os.Open(cr.configFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open cloud config file [%s]: [%v]", cr.configFilePath, err)
+	}
+	defer func() {
+		if err := configFileReader.Close(); err != nil {
+			klog.Infof("unable to close cloud config file [%s]: [%v]", cr.configFilePath, err)
+		}
+	}()
+
+	cloudConfig, err := config.ParseCloudConfig(configFileReader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse cloud config file [%s]: [%v]", cr.configFilePath, err)
+	}
+	if err = config.ValidateCloudConfig(cloudConfig); err != nil {
+		return nil, fmt.Errorf("invalid cloud config file [%s]: [%v]", cr.configFilePath, err)
+	}
+	return cloudConfig, nil
+}
+
+// getRefreshToken returns the refresh token with which the vcd client should re-authenticate. Refresh tokens of
+// service accounts can only be used once, so unless a new token was put into the secret, the token that the client
+// rotated last is used rather than the one that was read before.
+func (cr *configReloader) getRefreshToken(cloudConfig *config.CloudConfig, previousRefreshToken string) string {
+	refreshToken := cloudConfig.VCD.RefreshToken
+	if cloudConfig.VCD.ServiceAccountClientID == "" || refreshToken != previousRefreshToken {
+		return refreshToken
+	}
+	if session := cr.vcdClient.Session(); session != nil && session.VCDAuthConfig != nil &&
+		session.VCDAuthConfig.ServiceAccountClientID == cloudConfig.VCD.ServiceAccountClientID &&
+		session.VCDAuthConfig.RefreshToken != "" {
+		return session.VCDAuthConfig.RefreshToken
+	}
+	if cr.refreshTokenPersister != nil {
+		if latestRefreshToken := cr.refreshTokenPersister.getLatestRefreshToken(); latestRefreshToken != "" {
+			return latestRefreshToken
+		}
+	}
+	return refreshToken
+}
+
+// reload re-authenticates the vcd client if the credentials in the basic-auth secret or the CA bundle changed since
+// they were last read. The client keeps its session if the new credentials or CAs cannot be verified, and the result
+// is recorded in the RDE.
+func (cr *configReloader) reload() {
+	cloudConfig, err := cr.readConfig()
+	if err != nil {
+		klog.Errorf("unable to reload cloud config; previous config will be used: [%v]", err)
+		return
+	}
+	if !config.IsEqualExceptAuthentication(cr.lastConfig, cloudConfig) {
+		klog.Warningf("cloud config file [%s] changed; changes other than credentials, TLS, transport "+
+			"and rate limits need a restart to take effect", cr.configFilePath)
+	}
+
+	ctx := context.Background()
+	clusterID := cloudConfig.ClusterID
+	if err = config.SetAuthorizationFromDir(cloudConfig, cr.secretDir); err != nil {
+		cloudConfig.VCD.User, cloudConfig.VCD.UserOrg = cr.lastConfig.VCD.User, cr.lastConfig.VCD.UserOrg
+		cloudConfig.VCD.Secret, cloudConfig.VCD.RefreshToken = cr.lastConfig.VCD.Secret, cr.lastConfig.VCD.RefreshToken
+		cloudConfig.VCD.ServiceAccountClientID = cr.lastConfig.VCD.ServiceAccountClientID
+		klog.Errorf("unable to read credentials from secret [%s]; previous credentials will be used: [%v]",
+			cr.secretDir, err)
+	}
+	if cr.refreshTokenPersister != nil && cr.refreshTokenPersister.isPersisted(cloudConfig.VCD.RefreshToken) {
+		// the mounted secret holds a refresh token that was rotated by the client, and may be outdated
+		cloudConfig.VCD.RefreshToken = cr.lastConfig.VCD.RefreshToken
+	}
+	caBundle, err := loadCABundle(ctx, cloudConfig.VCD.TLS)
+	if err != nil {
+		klog.Errorf("unable to reload CA bundle; previous CA bundle will be used: [%v]", err)
+		cloudConfig.VCD.TLS, caBundle = cr.lastConfig.VCD.TLS, cr.lastCABundle
+	}
+
+	if config.HasSameCredentials(cr.lastConfig, cloudConfig) && bytes.Equal(cr.lastCABundle, caBundle) &&
+		config.HasSameConnectionSettings(cr.lastConfig, cloudConfig) {
+		cr.lastConfig = cloudConfig
+		return
+	}
+	previousRefreshToken := cr.lastConfig.VCD.RefreshToken
+	cr.lastConfig, cr.lastCABundle = cloudConfig, caBundle
+
+	klog.Infof("credentials in secret [%s] or connection settings changed; re-authenticating vcd client",
+		cr.secretDir)
+	transportConfig, err := getTransportConfig(cloudConfig, caBundle)
+	if err == nil {
+		err = cr.vcdClient.UpdateCredentials(cloudConfig.VCD.UserOrg, cloudConfig.VCD.User, cloudConfig.VCD.Secret,
+			cr.getRefreshToken(cloudConfig, previousRefreshToken), cloudConfig.VCD.ServiceAccountClientID,
+			config.IsInsecureTLS(cloudConfig), transportConfig)
+	}
+	if err != nil {
+		klog.Errorf("unable to re-authenticate with rotated credentials; previous session will be used: [%v]", err)
+		if rdeErr := cr.cpiRdeManager.AddToErrorSet(ctx, cpisdk.ClientAuthenticationError, clusterID,
+			fmt.Sprintf("unable to re-authenticate with rotated credentials: [%v]", err)); rdeErr != nil {
+			klog.Errorf("error adding CPI error [%s] to RDE: [%v]", cpisdk.ClientAuthenticationError, rdeErr)
+		}
+		return
+	}
+
+	if err = cr.cpiRdeManager.AddToEventSet(ctx, cpisdk.ClientAuthenticated, clusterID,
+		"successfully re-authenticated into vcdclient with rotated credentials"); err != nil {
+		klog.Errorf("error adding CPI event [%s] to RDE: [%v]", cpisdk.ClientAuthenticated, err)
+	}
+	if err = cr.cpiRdeManager.RDEManager.RemoveErrorByNameOrIdFromErrorSet(ctx, vcdsdk.ComponentCPI,
+		cpisdk.ClientAuthenticationError, clusterID, ""); err != nil {
+		klog.Errorf("failed to remove CPI error [%s] from ErrorSet in RDE [%s], [%v]",
+			cpisdk.ClientAuthenticationError, clusterID, err)
+	}
+}
+
+// startConfigReloader reloads the cloud config and credentials every reloadInterval until stopCh is closed.
+func (cr *configReloader) startConfigReloader(reloadInterval time.Duration, stopCh <-chan struct{}) {
+	klog.Infof("reloading cloud config [%s], credentials [%s] and CA bundle every [%v]", cr.configFilePath,
+		cr.secretDir, reloadInterval)
+	go wait.Until(cr.reload, reloadInterval, stopCh)
+}
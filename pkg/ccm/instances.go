@@ -8,7 +8,6 @@ package ccm
 import (
 	"context"
 	"fmt"
-	"github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdsdk"
 	"github.com/vmware/go-vcloud-director/v2/govcd"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -176,28 +175,12 @@ func (i *instances) InstanceShutdownByProviderID(ctx context.Context, providerID
 		return false, fmt.Errorf("unable to find instance type from vm uuid [%s]: [%v]", vmUUID, err)
 	}
 
-	if vmInfo.vm == nil {
-		return false, fmt.Errorf("vm struct in vmInfo nil for vm uuid [%s]: [%v]", vmUUID, err)
-	}
-
-	status, err := vmInfo.vm.GetStatus()
+	shutdown, status, err := i.vmInfoCache.getShutdownStatus(vmInfo)
 	if err != nil {
-		vdcManager, vdcManagerErr := vcdsdk.NewVDCManager(i.vmInfoCache.client, i.vmInfoCache.client.ClusterOrgName,
-			i.vmInfoCache.client.ClusterOVDCIdentifier)
-		if vdcManagerErr != nil {
-			return false, fmt.Errorf("error creating VDC manager object: [%v]", err)
-		}
-		if vdcManager.IsVmNotAvailable(err) {
-			return true, nil
-		}
-
-		return false, fmt.Errorf("unable to get status of vm uuid [%s], name [%s]: [%v]",
-			vmUUID, vmInfo.Name, err)
-	}
-	if status == "POWERED_ON" {
-		return false, nil
+		return false, err
 	}
 
-	klog.Infof("instances.InstanceShutdownByProviderID() for provider ID [%s] is true", providerID)
-	return true, nil
+	klog.Infof("instances.InstanceShutdownByProviderID() for provider ID [%s] with status [%s] is [%v]",
+		providerID, status, shutdown)
+	return shutdown, nil
 }
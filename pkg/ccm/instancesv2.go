@@ -3,7 +3,6 @@ package ccm
 import (
 	"context"
 	"fmt"
-	"github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdsdk"
 	"github.com/vmware/go-vcloud-director/v2/govcd"
 	v1 "k8s.io/api/core/v1"
 	cloudprovider "k8s.io/cloud-provider"
@@ -12,17 +11,17 @@ import (
 
 type instancesV2 struct {
 	vmInfoCache *VmInfoCache
-	zoneMap     *vcdsdk.ZoneMap
+	region      string
 }
 
 func (vcdCP *VCDCloudProvider) InstancesV2() (cloudprovider.InstancesV2, bool) {
 	return vcdCP.instancesV2, true
 }
 
-func newInstancesV2(vmInfoCache *VmInfoCache, zoneMap *vcdsdk.ZoneMap) cloudprovider.InstancesV2 {
+func newInstancesV2(vmInfoCache *VmInfoCache, region string) cloudprovider.InstancesV2 {
 	return instancesV2{
 		vmInfoCache: vmInfoCache,
-		zoneMap:     zoneMap,
+		region:      region,
 	}
 }
 
@@ -76,30 +75,20 @@ func (i instancesV2) InstanceShutdown(ctx context.Context, node *v1.Node) (bool,
 		return false, fmt.Errorf("unable to find instance type from vm uuid [%s]: [%v]", vmUUID, err)
 	}
 
-	if vmInfo.vm == nil {
-		return false, fmt.Errorf("vm struct in vmInfo nil for vm uuid [%s]: [%v]", vmUUID, err)
-	}
-
-	status, err := vmInfo.vm.GetStatus()
+	shutdown, status, err := i.vmInfoCache.getShutdownStatus(vmInfo)
 	if err != nil {
-		vdcManager, vdcManagerErr := vcdsdk.NewVDCManager(i.vmInfoCache.client, i.vmInfoCache.client.ClusterOrgName,
-			i.vmInfoCache.client.ClusterOVDCIdentifier)
-		if vdcManagerErr != nil {
-			return false, fmt.Errorf("error creating VDC manager object: [%v]", err)
-		}
-		if vdcManager.IsVmNotAvailable(err) {
-			return true, nil
-		}
-
-		return false, fmt.Errorf("unable to get status of vm uuid [%s], name [%s]: [%v]",
-			vmUUID, vmInfo.Name, err)
+		return false, err
 	}
-	if status == "POWERED_ON" {
-		return false, nil
+	if status != "" {
+		if err = updateNodeAnnotation(ctx, node, nodeAnnotationVMStatus, status); err != nil {
+			// the annotation is informational; it will be retried at the next status check
+			klog.Errorf("unable to update VM status annotation of node [%s]: [%v]", node.Name, err)
+		}
 	}
 
-	klog.Infof("instances.InstanceShutdownByProviderID() for provider ID [%s] is true", providerID)
-	return true, nil
+	klog.Infof("instances.InstanceShutdownByProviderID() for provider ID [%s] with status [%s] is [%v]",
+		providerID, status, shutdown)
+	return shutdown, nil
 }
 
 // InstanceMetadata returns the instance's metadata. The values returned in InstanceMetadata are
@@ -128,20 +117,20 @@ func (i instancesV2) InstanceMetadata(ctx context.Context, node *v1.Node) (*clou
 		}
 	}
 
-	zone := ""
-	if i.zoneMap != nil {
-		zone, _ = i.zoneMap.VdcToZoneMap[vmInfo.OVDC]
-	}
-
 	instanceMetadata := &cloudprovider.InstanceMetadata{
 		ProviderID:    getProviderIDFromUUID(vmInfo.UUID),
 		InstanceType:  vmInfo.Type,
 		NodeAddresses: vmInfo.Addresses,
-		Zone:          zone,
-		Region:        "",
+		Zone:          vmInfo.Zone,
+		Region:        i.region,
 	}
 
 	klog.Infof("reporting instanceV2 Metadata for vm [%s] as [%#v]", vmInfo.Name, instanceMetadata)
 
+	if err = updateNodeLabels(ctx, node, vmInfo.Labels); err != nil {
+		// labels are informational; they will be retried the next time metadata is reported
+		klog.Errorf("unable to update labels of node [%s]: [%v]", node.Name, err)
+	}
+
 	return instanceMetadata, nil
 }
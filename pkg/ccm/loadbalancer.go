@@ -10,9 +10,12 @@ package ccm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/vmware/cloud-provider-for-cloud-director/pkg/cpisdk"
 	"github.com/vmware/cloud-provider-for-cloud-director/pkg/util"
@@ -23,6 +26,9 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 	cloudProvider "k8s.io/cloud-provider"
 	"k8s.io/klog"
 )
@@ -37,9 +43,13 @@ const (
 
 // LBManager -
 type LBManager struct {
+	// gatewayManager is reused across operations; it is created again after an operation fails because a VCD entity
+	// was not found
 	gatewayManager               *vcdsdk.GatewayManager
+	gatewayManagerLock           sync.Mutex
 	vcdClient                    *vcdsdk.Client
 	kubeClient                   *kubernetes.Clientset
+	eventRecorder                record.EventRecorder
 	namespace                    string
 	CertificateAlias             string
 	OneArm                       *vcdsdk.OneArm
@@ -48,14 +58,19 @@ type LBManager struct {
 	ipamSubnet                   string
 	clusterID                    string
 	EnableVirtualServiceSharedIP bool
+	RouteAdvertisement           *vcdsdk.RouteAdvertisement
 }
 
-func newLoadBalancer(vcdClient *vcdsdk.Client, certAlias string, oneArm *vcdsdk.OneArm,
-	ovdcNetworkName string, ovdcIdentifier string, ipamSubnet string, clusterID string, enableVirtualServiceSharedIP bool) cloudProvider.LoadBalancer {
+func newLoadBalancer(vcdClient *vcdsdk.Client, gatewayManager *vcdsdk.GatewayManager, certAlias string, oneArm *vcdsdk.OneArm,
+	ovdcNetworkName string, ovdcIdentifier string, ipamSubnet string, clusterID string, enableVirtualServiceSharedIP bool,
+	routeAdvertisement *vcdsdk.RouteAdvertisement) cloudProvider.LoadBalancer {
 
+	kubeClient := GetK8SClient()
 	return &LBManager{
 		vcdClient:                    vcdClient,
-		kubeClient:                   GetK8SClient(),
+		gatewayManager:               gatewayManager,
+		kubeClient:                   kubeClient,
+		eventRecorder:                newEventRecorder(kubeClient),
 		namespace:                    "default",
 		CertificateAlias:             certAlias,
 		OneArm:                       oneArm,
@@ -64,12 +79,21 @@ func newLoadBalancer(vcdClient *vcdsdk.Client, certAlias string, oneArm *vcdsdk.
 		ipamSubnet:                   ipamSubnet,
 		clusterID:                    clusterID,
 		EnableVirtualServiceSharedIP: enableVirtualServiceSharedIP,
+		RouteAdvertisement:           routeAdvertisement,
 	}
 }
 
+// newEventRecorder returns a recorder of the events of the load balancers of Services.
+func newEventRecorder(kubeClient *kubernetes.Clientset) record.EventRecorder {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	return eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: release.CloudControllerManagerName})
+}
+
 // TODO: Should we add errors from this method to errorSet as it gives a few hard error returns?
-func (lb *LBManager) addLBResourcesToRDE(ctx context.Context, resourcesAllocated *util.AllocatedResourcesMap, externalIP string) error {
-	rdeManager := vcdsdk.NewRDEManager(lb.vcdClient, lb.clusterID, release.CloudControllerManagerName, release.Version)
+func addLBResourcesToRDE(ctx context.Context, vcdClient *vcdsdk.Client, clusterID string,
+	resourcesAllocated *util.AllocatedResourcesMap, externalIP string) error {
+	rdeManager := vcdsdk.NewRDEManager(vcdClient, clusterID, release.CloudControllerManagerName, release.Version)
 	for _, key := range []string{vcdsdk.VcdResourceDNATRule, vcdsdk.VcdResourceLoadBalancerPool, vcdsdk.VcdResourceAppPortProfile, vcdsdk.VcdResourceVirtualService} {
 		if values := resourcesAllocated.Get(key); values != nil {
 			for _, value := range values {
@@ -89,7 +113,7 @@ func (lb *LBManager) addLBResourcesToRDE(ctx context.Context, resourcesAllocated
 				if err != nil {
 					return fmt.Errorf(
 						"failed to add resource [%s] of type [%s] to VCDResourceSet of RDE [%s]: [%v]",
-						value.Name, key, lb.clusterID, err)
+						value.Name, key, clusterID, err)
 				}
 			}
 		}
@@ -98,8 +122,9 @@ func (lb *LBManager) addLBResourcesToRDE(ctx context.Context, resourcesAllocated
 }
 
 // TODO: Should we add errors from this method to errorSet as it gives a few hard error returns?
-func (lb *LBManager) removeLBResourcesFromRDE(ctx context.Context, resourcesDeallocated *util.AllocatedResourcesMap) error {
-	rdeManager := vcdsdk.NewRDEManager(lb.vcdClient, lb.clusterID, release.CloudControllerManagerName, release.Version)
+func removeLBResourcesFromRDE(ctx context.Context, vcdClient *vcdsdk.Client, clusterID string,
+	resourcesDeallocated *util.AllocatedResourcesMap) error {
+	rdeManager := vcdsdk.NewRDEManager(vcdClient, clusterID, release.CloudControllerManagerName, release.Version)
 	for _, key := range []string{vcdsdk.VcdResourceDNATRule, vcdsdk.VcdResourceVirtualService,
 		vcdsdk.VcdResourceLoadBalancerPool, vcdsdk.VcdResourceAppPortProfile} {
 		if values := resourcesDeallocated.Get(key); values != nil {
@@ -108,7 +133,7 @@ func (lb *LBManager) removeLBResourcesFromRDE(ctx context.Context, resourcesDeal
 				if err != nil {
 					return fmt.Errorf(
 						"failed to add resource [%s] of type [%s] to VCDResourceSet of RDE [%s]: [%v]",
-						value.Name, key, lb.clusterID, err)
+						value.Name, key, clusterID, err)
 				}
 			}
 		}
@@ -116,6 +141,76 @@ func (lb *LBManager) removeLBResourcesFromRDE(ctx context.Context, resourcesDeal
 	return nil
 }
 
+func (lb *LBManager) addLBResourcesToRDE(ctx context.Context, resourcesAllocated *util.AllocatedResourcesMap, externalIP string) error {
+	return addLBResourcesToRDE(ctx, lb.vcdClient, lb.clusterID, resourcesAllocated, externalIP)
+}
+
+func (lb *LBManager) removeLBResourcesFromRDE(ctx context.Context, resourcesDeallocated *util.AllocatedResourcesMap) error {
+	return removeLBResourcesFromRDE(ctx, lb.vcdClient, lb.clusterID, resourcesDeallocated)
+}
+
+// logRetriableLBError logs err of an operation on the load balancer of service if it is expected to resolve when the
+// service controller retries the operation, such as a busy gateway. Such errors are still recorded in the RDE.
+func logRetriableLBError(service *v1.Service, operation string, err error) {
+	if !vcdsdk.IsRetriableError(err) {
+		return
+	}
+	klog.Infof("[%s] of load balancer of service [%s/%s] failed with retriable error of kind [%s]; it will be requeued: [%v]",
+		operation, service.Namespace, service.Name, vcdsdk.GetVCDErrorKind(err), err)
+}
+
+// lbErrorName returns the name under which err of an operation on a load balancer is recorded in the RDE, where
+// errorName is the name of other errors of the operation. Timeouts of VCD tasks are named separately since the task may
+// still complete in VCD.
+func lbErrorName(errorName string, err error) string {
+	if vcdsdk.GetVCDErrorKind(err) == vcdsdk.VCDErrorTimeout {
+		return cpisdk.LoadbalancerTaskTimeout
+	}
+	return errorName
+}
+
+// recordTaskTimeout records a warning event on service with eventRecorder if err of an operation on its load balancer
+// is a timeout of a VCD task.
+func recordTaskTimeout(eventRecorder record.EventRecorder, service *v1.Service, operation string, err error) {
+	var taskTimeoutError *vcdsdk.TaskTimeoutError
+	if eventRecorder == nil || !errors.As(err, &taskTimeoutError) {
+		return
+	}
+	eventRecorder.Eventf(service, v1.EventTypeWarning, cpisdk.LoadbalancerTaskTimeout,
+		"[%s] of load balancer timed out waiting for VCD task [%s] of operation [%s] after [%v] at progress [%d%%]",
+		operation, taskTimeoutError.TaskHREF, taskTimeoutError.Operation,
+		taskTimeoutError.Elapsed.Round(time.Second), taskTimeoutError.Progress)
+}
+
+// getGatewayManager returns the GatewayManager of the network of the load balancers, which is shared by the
+// operations on all load balancers.
+func (lb *LBManager) getGatewayManager(ctx context.Context) (*vcdsdk.GatewayManager, error) {
+	lb.gatewayManagerLock.Lock()
+	defer lb.gatewayManagerLock.Unlock()
+	if lb.gatewayManager != nil {
+		return lb.gatewayManager, nil
+	}
+
+	gm, err := vcdsdk.NewGatewayManager(ctx, lb.vcdClient, lb.ovdcNetworkName, lb.ipamSubnet, lb.ovdcIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	lb.gatewayManager = gm
+	return gm, nil
+}
+
+// invalidateLookups drops the GatewayManager and the cached lookups of VCD entities if err of an operation is that
+// a VCD entity was not found, since the network, gateway or org that was looked up may have been replaced.
+func (lb *LBManager) invalidateLookups(err error) {
+	if !vcdsdk.IsNotFoundError(err) {
+		return
+	}
+	lb.gatewayManagerLock.Lock()
+	lb.gatewayManager = nil
+	lb.gatewayManagerLock.Unlock()
+	lb.vcdClient.InvalidateLookups()
+}
+
 func (lb *LBManager) getNodeIPs(ctx context.Context) ([]string, error) {
 	nodes, err := lb.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -137,11 +232,24 @@ func (lb *LBManager) getNodeIPs(ctx context.Context) ([]string, error) {
 func (lb *LBManager) EnsureLoadBalancer(ctx context.Context, clusterName string,
 	service *v1.Service, nodes []*v1.Node) (lbs *v1.LoadBalancerStatus, err error) {
 
+	ctx, span := startServiceSpan(ctx, "LBManager.EnsureLoadBalancer", service)
+	startTime := time.Now()
+	defer func() {
+		observeLoadBalancerOperation(lbOperationProvision, service, startTime, err)
+		recordTaskTimeout(lb.eventRecorder, service, lbOperationProvision, err)
+		lb.invalidateLookups(err)
+		endSpan(span, err)
+	}()
+
 	if err = lb.vcdClient.RefreshBearerToken(); err != nil {
 		return nil, fmt.Errorf("error while obtaining access token: [%v]", err)
 	}
 	nodeIPs := lb.getWorkerNodeInternalIps(nodes)
-	return lb.createLoadBalancer(ctx, service, nodeIPs)
+	lbs, err = lb.createLoadBalancer(ctx, service, nodeIPs)
+	if err == nil && lbs != nil && len(lbs.Ingress) > 0 {
+		loadBalancerVIPs.setServiceVIP(service, lbs.Ingress[0].IP)
+	}
+	return lbs, err
 }
 
 func (lb *LBManager) getNodeInternalIps(nodes []*v1.Node) []string {
@@ -174,6 +282,10 @@ func (lb *LBManager) getServicePortMap(service *v1.Service) (map[string]int32, m
 }
 
 func (lb *LBManager) getWorkerNodeInternalIps(nodes []*v1.Node) []string {
+	return getWorkerNodeInternalIps(nodes)
+}
+
+func getWorkerNodeInternalIps(nodes []*v1.Node) []string {
 	var workerNodeInternalIps []string
 	for _, node := range nodes {
 		nodeLabelMap := node.ObjectMeta.Labels
@@ -201,6 +313,15 @@ func (lb *LBManager) getWorkerNodeInternalIps(nodes []*v1.Node) []string {
 func (lb *LBManager) UpdateLoadBalancer(ctx context.Context, clusterName string,
 	service *v1.Service, nodes []*v1.Node) (err error) {
 
+	ctx, span := startServiceSpan(ctx, "LBManager.UpdateLoadBalancer", service)
+	startTime := time.Now()
+	defer func() {
+		observeLoadBalancerOperation(lbOperationUpdate, service, startTime, err)
+		recordTaskTimeout(lb.eventRecorder, service, lbOperationUpdate, err)
+		lb.invalidateLookups(err)
+		endSpan(span, err)
+	}()
+
 	if err = lb.vcdClient.RefreshBearerToken(); err != nil {
 		return fmt.Errorf("error while obtaining access token: [%v]", err)
 	}
@@ -224,15 +345,16 @@ func (lb *LBManager) UpdateLoadBalancer(ctx context.Context, clusterName string,
 		lbPoolName := fmt.Sprintf("%s-%s", lbPoolNamePrefix, portName)
 		virtualServiceName := fmt.Sprintf("%s-%s", virtualServiceNamePrefix, portName)
 		externalPort := typeToExternalPort[portName]
-		gm, err := vcdsdk.NewGatewayManager(ctx, lb.vcdClient, lb.ovdcNetworkName, lb.ipamSubnet, lb.ovdcIdentifier)
+		gm, err := lb.getGatewayManager(ctx)
 		if err != nil {
 			return fmt.Errorf("error while creating GatewayManager: [%v]", err)
 		}
 		klog.Infof("Updating pool [%s] with port [%s:%d]", lbPoolName, portName, internalPort)
 		protocol, _ := nameToProtocol[portName]
 		resourcesAllocated := &util.AllocatedResourcesMap{}
-		vip, err := gm.UpdateLoadBalancer(ctx, lbPoolName, virtualServiceName, nodeIps, userSpecifiedLBIP, internalPort,
-			externalPort, lb.OneArm, lb.EnableVirtualServiceSharedIP, protocol, resourcesAllocated)
+		vip, err := gm.UpdateLoadBalancer(ctx, lbPoolNamePrefix, virtualServiceNamePrefix, portName, nodeIps,
+			userSpecifiedLBIP, internalPort, externalPort, lb.OneArm, lb.EnableVirtualServiceSharedIP, protocol,
+			resourcesAllocated)
 		// TODO: Should we record this error as well?
 		if rdeErr := lb.addLBResourcesToRDE(ctx, resourcesAllocated, vip); rdeErr != nil {
 			return fmt.Errorf("failed to add load balancer resources to RDE [%s]: [%v]", lb.clusterID, err)
@@ -248,11 +370,13 @@ func (lb *LBManager) UpdateLoadBalancer(ctx context.Context, clusterName string,
 		}
 
 		if err != nil {
-			addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, cpisdk.UpdateLoadbalancerError, vsSummary.Id, vsSummary.Name, err.Error())
+			logRetriableLBError(service, lbOperationUpdate, err)
+			errorName := lbErrorName(cpisdk.UpdateLoadbalancerError, err)
+			addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, errorName, vsSummary.Id, vsSummary.Name, err.Error())
 			if addToErrorSetErr != nil {
-				klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", cpisdk.UpdateLoadbalancerError, lb.clusterID, addToErrorSetErr)
+				klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", errorName, lb.clusterID, addToErrorSetErr)
 			}
-			return fmt.Errorf("unable to update pool [%s] with port [%s:%d]: [%v]", lbPoolName, portName,
+			return fmt.Errorf("unable to update pool [%s] with port [%s:%d]: [%w]", lbPoolName, portName,
 				internalPort, err)
 		}
 
@@ -280,12 +404,25 @@ func (lb *LBManager) UpdateLoadBalancer(ctx context.Context, clusterName string,
 // Implementations must treat the *v1.Service parameter as read-only and not modify it.
 // Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
 func (lb *LBManager) EnsureLoadBalancerDeleted(ctx context.Context, clusterName string,
-	service *v1.Service) error {
+	service *v1.Service) (err error) {
+
+	ctx, span := startServiceSpan(ctx, "LBManager.EnsureLoadBalancerDeleted", service)
+	startTime := time.Now()
+	defer func() {
+		observeLoadBalancerOperation(lbOperationDelete, service, startTime, err)
+		recordTaskTimeout(lb.eventRecorder, service, lbOperationDelete, err)
+		lb.invalidateLookups(err)
+		endSpan(span, err)
+	}()
 
-	if err := lb.vcdClient.RefreshBearerToken(); err != nil {
+	if err = lb.vcdClient.RefreshBearerToken(); err != nil {
 		return fmt.Errorf("error while obtaining access token: [%v]", err)
 	}
-	return lb.deleteLoadBalancer(ctx, service)
+	if err = lb.deleteLoadBalancer(ctx, service); err != nil {
+		return err
+	}
+	loadBalancerVIPs.setServiceVIP(service, "")
+	return nil
 }
 
 func (lb *LBManager) getLoadBalancer(ctx context.Context,
@@ -293,7 +430,7 @@ func (lb *LBManager) getLoadBalancer(ctx context.Context,
 
 	virtualServiceNamePrefix := lb.getLoadBalancerPrefix(ctx, service)
 	virtualIP := ""
-	gm, err := vcdsdk.NewGatewayManager(ctx, lb.vcdClient, lb.ovdcNetworkName, lb.ipamSubnet, lb.ovdcIdentifier)
+	gm, err := lb.getGatewayManager(ctx)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error while creating GatewayManager: [%v]", err)
 	}
@@ -384,7 +521,10 @@ func (lb *LBManager) GetLoadBalancer(ctx context.Context, clusterName string,
 // getTrimmedClusterID: this is a mitigation to not overflow VCD name length limits. There is a clearer
 // fix needed in the future. Cover all cluster prefixes.
 func (lb *LBManager) getTrimmedClusterID() string {
-	clusterID := lb.clusterID
+	return getTrimmedClusterID(lb.clusterID)
+}
+
+func getTrimmedClusterID(clusterID string) string {
 	for _, prefix := range []string{
 		"urn:vcloud:entity:vmware:",
 		"urn:vcloud:entity:cse:nativeCluster:",
@@ -410,7 +550,11 @@ func (lb *LBManager) getVirtualServicePrefix(_ context.Context, service *v1.Serv
 // cluster Id, which allows CPI to uniquely mark an IP Allocation (from an Ip Space) being owned
 // by a particular service running on a specific cluster under a specific namespace
 func (lb *LBManager) getLoadBalancerIpClaimMarker(_ context.Context, service *v1.Service) string {
-	return fmt.Sprintf("cluster-%s-namespace-%s-service-%s", lb.getTrimmedClusterID(), service.Namespace, service.Name)
+	return getLoadBalancerIpClaimMarker(lb.clusterID, service)
+}
+
+func getLoadBalancerIpClaimMarker(clusterID string, service *v1.Service) string {
+	return fmt.Sprintf("cluster-%s-namespace-%s-service-%s", getTrimmedClusterID(clusterID), service.Namespace, service.Name)
 }
 
 // GetLoadBalancerName returns the name of the load balancer. Implementations must treat the
@@ -438,7 +582,7 @@ func (lb *LBManager) deleteLoadBalancer(ctx context.Context, service *v1.Service
 	}
 	klog.Infof("Deleting loadbalancer for ports [%#v]\n", portDetailsList)
 
-	gm, err := vcdsdk.NewGatewayManager(ctx, lb.vcdClient, lb.ovdcNetworkName, lb.ipamSubnet, lb.ovdcIdentifier)
+	gm, err := lb.getGatewayManager(ctx)
 	if err != nil {
 		return fmt.Errorf("error while creating GatewayManager: [%v]", err)
 	}
@@ -453,11 +597,13 @@ func (lb *LBManager) deleteLoadBalancer(ctx context.Context, service *v1.Service
 		lb.vcdClient, lb.clusterID, release.CloudControllerManagerName, release.Version))
 
 	if err != nil {
-		addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, cpisdk.DeleteLoadbalancerError, "", virtualServiceName, err.Error())
+		logRetriableLBError(service, lbOperationDelete, err)
+		errorName := lbErrorName(cpisdk.DeleteLoadbalancerError, err)
+		addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, errorName, "", virtualServiceName, err.Error())
 		if addToErrorSetErr != nil {
-			klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", cpisdk.DeleteLoadbalancerError, lb.clusterID, addToErrorSetErr)
+			klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", errorName, lb.clusterID, addToErrorSetErr)
 		}
-		return fmt.Errorf("unable to delete load balancer for virtual-service [%s] and lb pool [%s]: [%v]",
+		return fmt.Errorf("unable to delete load balancer for virtual-service [%s] and lb pool [%s]: [%w]",
 			virtualServiceName, lbPoolNamePrefix, err)
 	}
 
@@ -468,6 +614,7 @@ func (lb *LBManager) deleteLoadBalancer(ctx context.Context, service *v1.Service
 		}
 		klog.Errorf("failed to remove virtual IP [%s] from the RDE [%s]: [%v]", vip, lb.clusterID, err)
 	}
+	withdrawLoadBalancerVIP(ctx, gm, cpiRdeManager, lb.RouteAdvertisement, vip)
 
 	err = cpiRdeManager.RDEManager.RemoveErrorByNameOrIdFromErrorSet(ctx, vcdsdk.ComponentCPI, cpisdk.RemoveVIPFromRdeError, lb.clusterID, "")
 	if err != nil {
@@ -552,7 +699,7 @@ func (lb *LBManager) createLoadBalancer(ctx context.Context, service *v1.Service
 	if removeErr != nil {
 		klog.Errorf("error adding CPI error [%s] to the RDE [%s], [%v]", cpisdk.GetLoadbalancerError, lb.clusterID, removeErr)
 	}
-	gm, err := vcdsdk.NewGatewayManager(ctx, lb.vcdClient, lb.ovdcNetworkName, lb.ipamSubnet, lb.ovdcIdentifier)
+	gm, err := lb.getGatewayManager(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error while creating GatewayManager: [%v]", err)
 	}
@@ -582,8 +729,9 @@ func (lb *LBManager) createLoadBalancer(ctx context.Context, service *v1.Service
 			protocol, _ := nameToProtocol[portName]
 			klog.Infof("Updating pool [%s] with port [%s:%d:%d]", lbPoolName, portName, internalPort, externalPort)
 			resourcesAllocated := &util.AllocatedResourcesMap{}
-			vip, err := gm.UpdateLoadBalancer(ctx, lbPoolName, virtualServiceName, nodeIPs, userSpecifiedLBIP, internalPort,
-				externalPort, lb.OneArm, lb.EnableVirtualServiceSharedIP, protocol, resourcesAllocated)
+			vip, err := gm.UpdateLoadBalancer(ctx, lbPoolNamePrefix, virtualServiceNamePrefix, portName, nodeIPs,
+				userSpecifiedLBIP, internalPort, externalPort, lb.OneArm, lb.EnableVirtualServiceSharedIP, protocol,
+				resourcesAllocated)
 			if rdeErr := lb.addLBResourcesToRDE(ctx, resourcesAllocated, vip); rdeErr != nil {
 				return nil, fmt.Errorf("failed to update RDE [%s] with load balancer resources: [%v]", lb.clusterID, err)
 			}
@@ -597,11 +745,12 @@ func (lb *LBManager) createLoadBalancer(ctx context.Context, service *v1.Service
 			}
 
 			if err != nil {
-				addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, cpisdk.UpdateLoadbalancerError, vsSummary.Id, vsSummary.Name, err.Error())
+				errorName := lbErrorName(cpisdk.UpdateLoadbalancerError, err)
+				addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, errorName, vsSummary.Id, vsSummary.Name, err.Error())
 				if addToErrorSetErr != nil {
-					klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", cpisdk.UpdateLoadbalancerError, lb.clusterID, addToErrorSetErr)
+					klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", errorName, lb.clusterID, addToErrorSetErr)
 				}
-				return nil, fmt.Errorf("unable to update load balancer [%s] with port [%s:%d:%d] and load balancer IP [%s]: [%v]", lbPoolName, portName,
+				return nil, fmt.Errorf("unable to update load balancer [%s] with port [%s:%d:%d] and load balancer IP [%s]: [%w]", lbPoolName, portName,
 					internalPort, externalPort, userSpecifiedLBIP, err)
 			}
 
@@ -630,6 +779,11 @@ func (lb *LBManager) createLoadBalancer(ctx context.Context, service *v1.Service
 			}
 			return nil, fmt.Errorf("unexpected error while querying for loadbalancer after updating load balancer: [%v]", err)
 		}
+		if lbStatus != nil && len(lbStatus.Ingress) > 0 {
+			if err = advertiseLoadBalancerVIP(ctx, gm, cpiRdeManager, lb.RouteAdvertisement, lbStatus.Ingress[0].IP); err != nil {
+				return nil, err
+			}
+		}
 		return lbStatus, nil
 	}
 
@@ -694,11 +848,13 @@ func (lb *LBManager) createLoadBalancer(ctx context.Context, service *v1.Service
 		return nil, fmt.Errorf("unable to add load balancer pool resources to RDE [%s]: [%v]", lb.clusterID, err)
 	}
 	if err != nil {
-		addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, cpisdk.CreateLoadbalancerError, "", virtualServiceNamePrefix, err.Error())
+		logRetriableLBError(service, lbOperationProvision, err)
+		errorName := lbErrorName(cpisdk.CreateLoadbalancerError, err)
+		addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, errorName, "", virtualServiceNamePrefix, err.Error())
 		if addToErrorSetErr != nil {
-			klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", cpisdk.CreateLoadbalancerError, lb.clusterID, addToErrorSetErr)
+			klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", errorName, lb.clusterID, addToErrorSetErr)
 		}
-		return nil, fmt.Errorf("unable to create loadbalancer for ports [%#v]: [%v]", portDetailsList, err)
+		return nil, fmt.Errorf("unable to create loadbalancer for ports [%#v]: [%w]", portDetailsList, err)
 	}
 
 	if lbIP != "" {
@@ -710,6 +866,9 @@ func (lb *LBManager) createLoadBalancer(ctx context.Context, service *v1.Service
 			}
 			klog.Errorf("error when adding virtual IP to RDE: [%v]", err)
 		}
+		if err = advertiseLoadBalancerVIP(ctx, gm, cpiRdeManager, lb.RouteAdvertisement, lbIP); err != nil {
+			return nil, err
+		}
 	}
 
 	err = cpiRdeManager.AddToEventSetWithNameAndId(ctx, cpisdk.CreatedLoadbalancer, "", virtualServiceNamePrefix, fmt.Sprintf("Created loadbalancer successfully for [%s] with external IP: [%s]", lb.clusterID, lbIP))
@@ -760,7 +919,7 @@ to search for a virtual service of %s name.
 func (lb *LBManager) verifyVCDResourcesForApplicationLB(ctx context.Context, virtualServiceNamePrefix string,
 	lbPoolNamePrefix string, portDetailsList []vcdsdk.PortDetails, oneArm *vcdsdk.OneArm) (bool, error) {
 
-	gatewayMgr, err := vcdsdk.NewGatewayManager(ctx, lb.vcdClient, lb.ovdcNetworkName, lb.ipamSubnet, lb.ovdcIdentifier)
+	gatewayMgr, err := lb.getGatewayManager(ctx)
 	if err != nil {
 		return false, fmt.Errorf("error creating new gateway manager [%v]", err)
 	}
@@ -806,14 +965,13 @@ func (lb *LBManager) verifyVCDResourcesForApplicationLB(ctx context.Context, vir
 				return true, nil
 			}
 			appPortProfileName := vcdsdk.GetAppPortProfileName(dnatRuleName)
-			org, err := lb.vcdClient.VCDClient.GetOrgByName(lb.vcdClient.ClusterOrgName)
+			org, err := lb.vcdClient.GetOrgByName(lb.vcdClient.ClusterOrgName)
 			if err != nil {
 				return false, fmt.Errorf("unable to find org [%s] by name: [%v]", lb.vcdClient.ClusterOrgName, err)
 			}
 			appPortProfile, err := org.GetNsxtAppPortProfileByName(appPortProfileName, types.ApplicationPortProfileScopeTenant)
-			// We are doing a string check here because it returns a formatted error instead of govcd.ErrorEntityNotFound
-			if err != nil && !strings.Contains(err.Error(), "[ENF] entity not found") {
-				return false, fmt.Errorf("unable to get app port profile [%s]: [%v]", appPortProfileName, err)
+			if err != nil && !vcdsdk.IsNotFoundError(err) {
+				return false, fmt.Errorf("unable to get app port profile [%s]: [%w]", appPortProfileName, err)
 			}
 			if appPortProfile != nil {
 				return true, nil
@@ -0,0 +1,130 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdsdk"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	metricsNamespace = "cloudprovider"
+	metricsSubsystem = "vcd"
+
+	lbOperationProvision = "provision"
+	lbOperationUpdate    = "update"
+	lbOperationDelete    = "delete"
+)
+
+var (
+	vmInfoCacheLookups = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      metricsSubsystem,
+			Name:           "vm_info_cache_lookups_total",
+			Help:           "Number of VM info cache lookups by index and result (hit, negative_hit or miss).",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"index", "result"},
+	)
+
+	loadBalancerOperationDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      metricsSubsystem,
+			Name:           "load_balancer_operation_duration_seconds",
+			Help:           "Duration of load balancer provisioning, update and delete operations by result.",
+			Buckets:        metrics.ExponentialBuckets(1, 2, 10),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"operation", "result"},
+	)
+
+	loadBalancerOperationErrors = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      metricsSubsystem,
+			Name:           "load_balancer_operation_errors_total",
+			Help:           "Number of failed load balancer operations by operation and Service.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"operation", "namespace", "service"},
+	)
+
+	loadBalancerVIPsInUse = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      metricsSubsystem,
+			Name:           "load_balancer_vips_in_use",
+			Help:           "Number of distinct VIPs of the load balancers of the Services of the cluster.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	loadBalancerVIPs = newVIPTracker()
+
+	registerMetricsOnce sync.Once
+)
+
+// registerMetrics registers the metrics of the CPI with the registry that is served by the cloud controller manager.
+func registerMetrics() {
+	registerMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(vmInfoCacheLookups)
+		legacyregistry.MustRegister(loadBalancerOperationDuration)
+		legacyregistry.MustRegister(loadBalancerOperationErrors)
+		legacyregistry.MustRegister(loadBalancerVIPsInUse)
+		vcdsdk.RegisterMetrics()
+	})
+}
+
+// observeLoadBalancerOperation records the duration of a load balancer operation on service that started at
+// startTime, and counts it as an error of the Service if err is set.
+func observeLoadBalancerOperation(operation string, service *v1.Service, startTime time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+		loadBalancerOperationErrors.WithLabelValues(operation, service.Namespace, service.Name).Inc()
+	}
+	loadBalancerOperationDuration.WithLabelValues(operation, result).Observe(time.Since(startTime).Seconds())
+}
+
+// vipTracker tracks the VIPs of the load balancers of Services, several of which may share a VIP.
+type vipTracker struct {
+	lock        sync.Mutex
+	serviceVIPs map[string]string
+}
+
+func newVIPTracker() *vipTracker {
+	return &vipTracker{
+		serviceVIPs: make(map[string]string),
+	}
+}
+
+// setServiceVIP records vip as the VIP of the load balancer of service, or removes the load balancer if vip is empty,
+// and updates the gauge of the VIPs in use.
+func (vt *vipTracker) setServiceVIP(service *v1.Service, vip string) {
+	vt.lock.Lock()
+	defer vt.lock.Unlock()
+
+	serviceKey := fmt.Sprintf("%s/%s", service.Namespace, service.Name)
+	if vip == "" {
+		delete(vt.serviceVIPs, serviceKey)
+	} else {
+		vt.serviceVIPs[serviceKey] = vip
+	}
+
+	vips := make(map[string]bool)
+	for _, serviceVIP := range vt.serviceVIPs {
+		vips[serviceVIP] = true
+	}
+	loadBalancerVIPsInUse.Set(float64(len(vips)))
+}
@@ -0,0 +1,299 @@
+//go:build !testing
+// +build !testing
+
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/cpisdk"
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/util"
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdsdk"
+	"github.com/vmware/cloud-provider-for-cloud-director/release"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	cloudProvider "k8s.io/cloud-provider"
+	"k8s.io/klog"
+)
+
+// NATLBManager implements LoadBalancer services using only DNAT rules and app port profiles on an NSX-T
+// gateway. It is used when the gateway has no NSX Advanced Load Balancer. The VIP of a service is forwarded
+// to the NodePort of one worker node, and the rules are moved to another node if that node disappears.
+type NATLBManager struct {
+	vcdClient       *vcdsdk.Client
+	ovdcNetworkName string
+	ovdcIdentifier  string
+	ipamSubnet      string
+	clusterID       string
+	eventRecorder   record.EventRecorder
+	// RouteAdvertisement is nil if VIPs are not advertised
+	RouteAdvertisement *vcdsdk.RouteAdvertisement
+}
+
+func newNATLoadBalancer(vcdClient *vcdsdk.Client, ovdcNetworkName string, ovdcIdentifier string,
+	ipamSubnet string, clusterID string, routeAdvertisement *vcdsdk.RouteAdvertisement) cloudProvider.LoadBalancer {
+
+	return &NATLBManager{
+		vcdClient:          vcdClient,
+		ovdcNetworkName:    ovdcNetworkName,
+		ovdcIdentifier:     ovdcIdentifier,
+		ipamSubnet:         ipamSubnet,
+		clusterID:          clusterID,
+		eventRecorder:      newEventRecorder(GetK8SClient()),
+		RouteAdvertisement: routeAdvertisement,
+	}
+}
+
+// getDNATRuleNamePrefix returns the prefix of the names of the DNAT rules of the load balancer of service. The
+// namespace and name of the service are separated by underscores, which Kubernetes names cannot contain, so that
+// services with the same name in different namespaces, or whose name starts with the name of another service, get
+// different rule names.
+func (lb *NATLBManager) getDNATRuleNamePrefix(_ context.Context, service *v1.Service) string {
+	return fmt.Sprintf("ingress-nat-%s_%s_%s", getTrimmedClusterID(lb.clusterID), service.Namespace, service.Name)
+}
+
+func getNATPortDetailsList(service *v1.Service) []vcdsdk.PortDetails {
+	portDetailsList := make([]vcdsdk.PortDetails, len(service.Spec.Ports))
+	for idx, port := range service.Spec.Ports {
+		portDetailsList[idx] = vcdsdk.PortDetails{
+			PortSuffix:   port.Name,
+			ExternalPort: port.Port,
+			InternalPort: port.NodePort,
+			Protocol:     strings.ToUpper(string(port.Protocol)),
+		}
+	}
+	return portDetailsList
+}
+
+// GetLoadBalancer returns whether the specified load balancer exists, and
+// if so, what its status is.
+// Implementations must treat the *v1.Service parameter as read-only and not modify it.
+// Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
+func (lb *NATLBManager) GetLoadBalancer(ctx context.Context, clusterName string,
+	service *v1.Service) (status *v1.LoadBalancerStatus, exists bool, err error) {
+
+	if err = lb.vcdClient.RefreshBearerToken(); err != nil {
+		return nil, false, fmt.Errorf("error while obtaining access token: [%v]", err)
+	}
+
+	dnatRuleNamePrefix := lb.getDNATRuleNamePrefix(ctx, service)
+	gm, err := vcdsdk.NewGatewayManager(ctx, lb.vcdClient, lb.ovdcNetworkName, lb.ipamSubnet, lb.ovdcIdentifier)
+	if err != nil {
+		return nil, false, fmt.Errorf("error while creating GatewayManager: [%v]", err)
+	}
+
+	cpiRdeManager := cpisdk.NewCPIRDEManager(vcdsdk.NewRDEManager(
+		lb.vcdClient, lb.clusterID, release.CloudControllerManagerName, release.Version))
+
+	vip, portNameToIP, err := gm.GetNATLoadBalancer(ctx, dnatRuleNamePrefix, getNATPortDetailsList(service))
+	if err != nil {
+		addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, cpisdk.GetLoadbalancerError, "", dnatRuleNamePrefix, err.Error())
+		if addToErrorSetErr != nil {
+			klog.Errorf("unable to add CPI error [%s] to RDE [%s], [%v]", cpisdk.GetLoadbalancerError, lb.clusterID, addToErrorSetErr)
+		}
+		return nil, false, fmt.Errorf("unable to get NAT load balancer [%s]: [%v]", dnatRuleNamePrefix, err)
+	}
+	removeErr := cpiRdeManager.RDEManager.RemoveErrorByNameOrIdFromErrorSet(ctx, vcdsdk.ComponentCPI, cpisdk.GetLoadbalancerError, "", dnatRuleNamePrefix)
+	if removeErr != nil {
+		klog.Errorf("there was an error removing CPI error [%s] from RDE [%s], [%v]", cpisdk.GetLoadbalancerError, lb.clusterID, removeErr)
+	}
+
+	if vip == "" {
+		return nil, false, nil
+	}
+	for _, ip := range portNameToIP {
+		if ip == "" {
+			// a partially created load balancer is reported as existing without a status so that it is reconciled
+			return nil, true, nil
+		}
+	}
+
+	return &v1.LoadBalancerStatus{
+		Ingress: []v1.LoadBalancerIngress{
+			{
+				IP: vip,
+			},
+		},
+	}, true, nil
+}
+
+// GetLoadBalancerName returns the name of the load balancer. Implementations must treat the
+// *v1.Service parameter as read-only and not modify it.
+func (lb *NATLBManager) GetLoadBalancerName(ctx context.Context, clusterName string, service *v1.Service) string {
+	return lb.getDNATRuleNamePrefix(ctx, service)
+}
+
+// EnsureLoadBalancer creates a new load balancer 'name', or updates the existing one.
+// Returns the status of the balancer. Implementations must treat the *v1.Service and *v1.Node
+// parameters as read-only and not modify them.
+// Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
+func (lb *NATLBManager) EnsureLoadBalancer(ctx context.Context, clusterName string,
+	service *v1.Service, nodes []*v1.Node) (*v1.LoadBalancerStatus, error) {
+
+	if err := lb.vcdClient.RefreshBearerToken(); err != nil {
+		return nil, fmt.Errorf("error while obtaining access token: [%v]", err)
+	}
+
+	vip, err := lb.ensureNATLoadBalancer(ctx, service, nodes, cpisdk.CreateLoadbalancerError, cpisdk.CreatedLoadbalancer)
+	if err != nil {
+		recordTaskTimeout(lb.eventRecorder, service, lbOperationProvision, err)
+		return nil, err
+	}
+
+	return &v1.LoadBalancerStatus{
+		Ingress: []v1.LoadBalancerIngress{
+			{
+				IP: vip,
+			},
+		},
+	}, nil
+}
+
+// UpdateLoadBalancer updates hosts under the specified load balancer. If the node to which the DNAT rules
+// forward is no longer part of nodes, the rules are moved to another node.
+// Implementations must treat the *v1.Service and *v1.Node
+// parameters as read-only and not modify them.
+// Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
+func (lb *NATLBManager) UpdateLoadBalancer(ctx context.Context, clusterName string,
+	service *v1.Service, nodes []*v1.Node) error {
+
+	if err := lb.vcdClient.RefreshBearerToken(); err != nil {
+		return fmt.Errorf("error while obtaining access token: [%v]", err)
+	}
+
+	_, err := lb.ensureNATLoadBalancer(ctx, service, nodes, cpisdk.UpdateLoadbalancerError, cpisdk.UpdatedLoadbalancer)
+	recordTaskTimeout(lb.eventRecorder, service, lbOperationUpdate, err)
+	return err
+}
+
+func (lb *NATLBManager) ensureNATLoadBalancer(ctx context.Context, service *v1.Service, nodes []*v1.Node,
+	rdeErrorName string, rdeEventName string) (string, error) {
+
+	dnatRuleNamePrefix := lb.getDNATRuleNamePrefix(ctx, service)
+	lbIpClaimMarker := getLoadBalancerIpClaimMarker(lb.clusterID, service)
+	nodeIPs := getWorkerNodeInternalIps(nodes)
+	userSpecifiedLBIP := getUserSpecifiedLoadBalancerIP(service)
+	portDetailsList := getNATPortDetailsList(service)
+	klog.Infof("Ensuring NAT load balancer [%s] for ports [%#v] with nodes [%v] and loadBalancerIP [%s]",
+		dnatRuleNamePrefix, portDetailsList, nodeIPs, userSpecifiedLBIP)
+
+	gm, err := vcdsdk.NewGatewayManager(ctx, lb.vcdClient, lb.ovdcNetworkName, lb.ipamSubnet, lb.ovdcIdentifier)
+	if err != nil {
+		return "", fmt.Errorf("error while creating GatewayManager: [%v]", err)
+	}
+
+	cpiRdeManager := cpisdk.NewCPIRDEManager(vcdsdk.NewRDEManager(
+		lb.vcdClient, lb.clusterID, release.CloudControllerManagerName, release.Version))
+
+	resourcesAllocated := &util.AllocatedResourcesMap{}
+	resourcesDeallocated := &util.AllocatedResourcesMap{}
+	vip, err := gm.EnsureNATLoadBalancer(ctx, dnatRuleNamePrefix, lbIpClaimMarker, nodeIPs, portDetailsList,
+		userSpecifiedLBIP, resourcesAllocated, resourcesDeallocated)
+	if rdeErr := addLBResourcesToRDE(ctx, lb.vcdClient, lb.clusterID, resourcesAllocated, vip); rdeErr != nil {
+		klog.Errorf("failed to add NAT load balancer resources to RDE [%s]: [%v]", lb.clusterID, rdeErr)
+	}
+	if rdeErr := removeLBResourcesFromRDE(ctx, lb.vcdClient, lb.clusterID, resourcesDeallocated); rdeErr != nil {
+		klog.Errorf("failed to remove NAT load balancer resources from RDE [%s]: [%v]", lb.clusterID, rdeErr)
+	}
+	if err != nil {
+		errorName := lbErrorName(rdeErrorName, err)
+		addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, errorName, "", dnatRuleNamePrefix, err.Error())
+		if addToErrorSetErr != nil {
+			klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", errorName, lb.clusterID, addToErrorSetErr)
+		}
+		return "", fmt.Errorf("unable to ensure NAT load balancer [%s] for ports [%#v]: [%w]",
+			dnatRuleNamePrefix, portDetailsList, err)
+	}
+
+	if err = cpiRdeManager.AddVirtualIpToRDE(ctx, vip); err != nil {
+		addToErrorSetErr := cpiRdeManager.AddToErrorSet(ctx, cpisdk.AddVIPToRdeError, lb.clusterID, err.Error())
+		if addToErrorSetErr != nil {
+			klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", cpisdk.AddVIPToRdeError, lb.clusterID, addToErrorSetErr)
+		}
+		klog.Errorf("error when adding virtual IP to RDE: [%v]", err)
+	}
+	if err = advertiseLoadBalancerVIP(ctx, gm, cpiRdeManager, lb.RouteAdvertisement, vip); err != nil {
+		return "", err
+	}
+
+	err = cpiRdeManager.AddToEventSetWithNameAndId(ctx, rdeEventName, "", dnatRuleNamePrefix,
+		fmt.Sprintf("NAT load balancer [%s] reconciled successfully with external IP [%s]", dnatRuleNamePrefix, vip))
+	if err != nil {
+		klog.Errorf("error adding CPI event [%s] to RDE: [%v]", rdeEventName, err)
+	}
+
+	err = cpiRdeManager.RDEManager.RemoveErrorByNameOrIdFromErrorSet(ctx, vcdsdk.ComponentCPI, rdeErrorName, "", dnatRuleNamePrefix)
+	if err != nil {
+		klog.Errorf("there was an error removing CPI error [%s] from RDE [%s], [%v]", rdeErrorName, lb.clusterID, err)
+	}
+
+	klog.Infof("Reconciled NAT load balancer [%s] with external IP [%s]", dnatRuleNamePrefix, vip)
+	return vip, nil
+}
+
+// EnsureLoadBalancerDeleted deletes the specified load balancer if it
+// exists, returning nil if the load balancer specified either didn't exist or
+// was successfully deleted.
+// Implementations must treat the *v1.Service parameter as read-only and not modify it.
+// Parameter 'clusterName' is the name of the cluster as presented to kube-controller-manager
+func (lb *NATLBManager) EnsureLoadBalancerDeleted(ctx context.Context, clusterName string,
+	service *v1.Service) error {
+
+	if err := lb.vcdClient.RefreshBearerToken(); err != nil {
+		return fmt.Errorf("error while obtaining access token: [%v]", err)
+	}
+
+	dnatRuleNamePrefix := lb.getDNATRuleNamePrefix(ctx, service)
+	lbIpClaimMarker := getLoadBalancerIpClaimMarker(lb.clusterID, service)
+	klog.Infof("Deleting NAT load balancer [%s]", dnatRuleNamePrefix)
+
+	gm, err := vcdsdk.NewGatewayManager(ctx, lb.vcdClient, lb.ovdcNetworkName, lb.ipamSubnet, lb.ovdcIdentifier)
+	if err != nil {
+		return fmt.Errorf("error while creating GatewayManager: [%v]", err)
+	}
+
+	resourcesDeallocated := &util.AllocatedResourcesMap{}
+	vip, err := gm.DeleteNATLoadBalancer(ctx, dnatRuleNamePrefix, lbIpClaimMarker, getNATPortDetailsList(service),
+		resourcesDeallocated)
+	if rdeErr := removeLBResourcesFromRDE(ctx, lb.vcdClient, lb.clusterID, resourcesDeallocated); rdeErr != nil {
+		klog.Errorf("failed to remove NAT load balancer resources from RDE [%s]: [%v]", lb.clusterID, rdeErr)
+		return fmt.Errorf("failed to remove NAT load balancer resources from RDE [%s]: [%v]", lb.clusterID, rdeErr)
+	}
+
+	cpiRdeManager := cpisdk.NewCPIRDEManager(vcdsdk.NewRDEManager(
+		lb.vcdClient, lb.clusterID, release.CloudControllerManagerName, release.Version))
+	if err != nil {
+		recordTaskTimeout(lb.eventRecorder, service, lbOperationDelete, err)
+		errorName := lbErrorName(cpisdk.DeleteLoadbalancerError, err)
+		addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, errorName, "", dnatRuleNamePrefix, err.Error())
+		if addToErrorSetErr != nil {
+			klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", errorName, lb.clusterID, addToErrorSetErr)
+		}
+		return fmt.Errorf("unable to delete NAT load balancer [%s]: [%v]", dnatRuleNamePrefix, err)
+	}
+
+	if err = cpiRdeManager.RemoveVirtualIpFromRDE(ctx, vip); err != nil {
+		klog.Errorf("failed to remove virtual IP [%s] from the RDE [%s]: [%v]", vip, lb.clusterID, err)
+	}
+	withdrawLoadBalancerVIP(ctx, gm, cpiRdeManager, lb.RouteAdvertisement, vip)
+
+	err = cpiRdeManager.AddToEventSetWithNameAndId(ctx, cpisdk.DeletedLoadbalancer, "", dnatRuleNamePrefix,
+		fmt.Sprintf("Successfully deleted NAT load balancer [%s], deleted external IP [%s]", dnatRuleNamePrefix, vip))
+	if err != nil {
+		klog.Errorf("error adding CPI event [%s] to RDE: [%v]", cpisdk.DeletedLoadbalancer, err)
+	}
+
+	err = cpiRdeManager.RDEManager.RemoveErrorByNameOrIdFromErrorSet(ctx, vcdsdk.ComponentCPI, cpisdk.DeleteLoadbalancerError, "", dnatRuleNamePrefix)
+	if err != nil {
+		klog.Errorf("there was an error removing CPI error [%s] from RDE [%s], [%v]", cpisdk.DeleteLoadbalancerError, lb.clusterID, err)
+	}
+
+	return nil
+}
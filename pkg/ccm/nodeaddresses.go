@@ -0,0 +1,138 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/config"
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdsdk"
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	v1 "k8s.io/api/core/v1"
+	v1helper "k8s.io/cloud-provider/node/helpers"
+	"k8s.io/klog"
+)
+
+// natAddressResolver resolves the external IPs of nodes from the one-to-one NAT rules of the edge gateway. The
+// rules are listed at most once per expiry for all nodes.
+type natAddressResolver struct {
+	lock            sync.Mutex
+	client          *vcdsdk.Client
+	ovdcNetworkName string
+	ovdcIdentifier  string
+	ipamSubnet      string
+	expiry          time.Duration
+	externalIPs     map[string]string
+	timeStamp       time.Time
+}
+
+func newNATAddressResolver(client *vcdsdk.Client, ovdcNetworkName string, ovdcIdentifier string, ipamSubnet string,
+	expiry time.Duration) *natAddressResolver {
+	return &natAddressResolver{
+		client:          client,
+		ovdcNetworkName: ovdcNetworkName,
+		ovdcIdentifier:  ovdcIdentifier,
+		ipamSubnet:      ipamSubnet,
+		expiry:          expiry,
+	}
+}
+
+func (nar *natAddressResolver) refresh() error {
+	if err := nar.client.RefreshBearerToken(); err != nil {
+		return fmt.Errorf("error while obtaining access token: [%v]", err)
+	}
+	gm, err := vcdsdk.NewGatewayManager(context.Background(), nar.client, nar.ovdcNetworkName, nar.ipamSubnet,
+		nar.ovdcIdentifier)
+	if err != nil {
+		return fmt.Errorf("error while creating GatewayManager: [%v]", err)
+	}
+	externalIPs, err := gm.ListNATExternalIPsByInternalIP(context.Background())
+	if err != nil {
+		return fmt.Errorf("unable to list NAT rules of gateway of network [%s]: [%v]", nar.ovdcNetworkName, err)
+	}
+	nar.externalIPs = externalIPs
+	nar.timeStamp = time.Now()
+	return nil
+}
+
+// getExternalIP returns the external IP of the NAT rules for internalIP, or an empty string if there is none.
+func (nar *natAddressResolver) getExternalIP(internalIP string) string {
+	nar.lock.Lock()
+	defer nar.lock.Unlock()
+
+	if time.Since(nar.timeStamp) >= nar.expiry {
+		if err := nar.refresh(); err != nil {
+			// keep using the previous rules, if any, until the next attempt
+			klog.Errorf("unable to refresh external IPs from NAT rules: [%v]", err)
+			nar.timeStamp = time.Now()
+		}
+	}
+	return nar.externalIPs[internalIP]
+}
+
+// getNodeAddressType returns the address type configured for the network of the NIC, or an empty string if the
+// network is not mapped.
+func (vmic *VmInfoCache) getNodeAddressType(network string) string {
+	if addressType, ok := vmic.nodeAddresses.Networks[network]; ok {
+		return addressType
+	}
+	return ""
+}
+
+// getNodeAddresses returns the addresses of the VM for its node. The addresses of each NIC are reported with the
+// type configured for its network. NICs on unmapped networks are reported as before: the primary NIC is an
+// InternalIP and every NIC is an ExternalIP.
+func (vmic *VmInfoCache) getNodeAddresses(vm *govcd.VM) []v1.NodeAddress {
+	vmAddresses := make([]v1.NodeAddress, 0)
+	addAddress := func(addressType v1.NodeAddressType, address string) {
+		if address == "" {
+			return
+		}
+		v1helper.AddToNodeAddresses(&vmAddresses, v1.NodeAddress{
+			Type:    addressType,
+			Address: address,
+		})
+	}
+
+	for _, nicAddresses := range vcdsdk.GetVMNICAddresses(vm.VM) {
+		addressType := vmic.getNodeAddressType(nicAddresses.Network)
+		for _, ip := range nicAddresses.IPs {
+			switch addressType {
+			case config.NodeAddressTypeSkip:
+				continue
+			case config.NodeAddressTypeInternalIP:
+				addAddress(v1.NodeInternalIP, ip)
+			case config.NodeAddressTypeExternalIP:
+				addAddress(v1.NodeExternalIP, ip)
+			default:
+				if nicAddresses.Primary {
+					addAddress(v1.NodeInternalIP, ip)
+				}
+				addAddress(v1.NodeExternalIP, ip)
+			}
+			if addressType != config.NodeAddressTypeExternalIP && vmic.natAddressResolver != nil {
+				addAddress(v1.NodeExternalIP, vmic.natAddressResolver.getExternalIP(ip))
+			}
+		}
+		if addressType != config.NodeAddressTypeSkip {
+			addAddress(v1.NodeExternalIP, nicAddresses.ExternalIP)
+		}
+	}
+
+	addAddress(v1.NodeHostName, vm.VM.Name)
+	guestHostname := vcdsdk.GetVMGuestHostname(vm.VM)
+	if vmic.nodeAddresses.InternalDNSSuffix != "" {
+		addAddress(v1.NodeInternalDNS, fmt.Sprintf("%s.%s", guestHostname, vmic.nodeAddresses.InternalDNSSuffix))
+	}
+	if vmic.nodeAddresses.ExternalDNSSuffix != "" {
+		addAddress(v1.NodeExternalDNS, fmt.Sprintf("%s.%s", guestHostname, vmic.nodeAddresses.ExternalDNSSuffix))
+	}
+
+	return vmAddresses
+}
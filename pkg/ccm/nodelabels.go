@@ -0,0 +1,78 @@
+//go:build !testing
+// +build !testing
+
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+)
+
+// updateNodeLabels sets the labels of the node that are managed by the CCM to labels. Managed labels that are no
+// longer reported, e.g. because a policy was removed from the VM, are removed from the node.
+// The cloud-provider library of this release does not apply additional labels from InstanceMetadata, hence the
+// node is patched directly.
+func updateNodeLabels(ctx context.Context, node *v1.Node, labels map[string]string) error {
+	labelsDiff := util.GetManagedLabelsDiff(node.Labels, labels, []string{
+		nodeLabelOVDC, nodeLabelPlacementPolicy, nodeLabelSizingPolicy, nodeLabelStorageProfile, nodeLabelVAppName,
+		nodeMetadataLabelPrefix,
+	})
+	if len(labelsDiff) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": labelsDiff,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to marshal labels patch for node [%s]: [%v]", node.Name, err)
+	}
+
+	if _, err = GetK8SClient().CoreV1().Nodes().Patch(ctx, node.Name, k8stypes.StrategicMergePatchType, patch,
+		metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("unable to patch labels of node [%s]: [%v]", node.Name, err)
+	}
+	klog.Infof("updated labels of node [%s] with [%v]", node.Name, labelsDiff)
+
+	return nil
+}
+
+// updateNodeAnnotation sets the annotation key of the node to value if it differs.
+func updateNodeAnnotation(ctx context.Context, node *v1.Node, key string, value string) error {
+	if existingValue, ok := node.Annotations[key]; ok && existingValue == value {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				key: value,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to marshal annotation patch for node [%s]: [%v]", node.Name, err)
+	}
+
+	if _, err = GetK8SClient().CoreV1().Nodes().Patch(ctx, node.Name, k8stypes.StrategicMergePatchType, patch,
+		metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("unable to patch annotation [%s] of node [%s]: [%v]", key, node.Name, err)
+	}
+	klog.Infof("updated annotation [%s] of node [%s] to [%s]", key, node.Name, value)
+
+	return nil
+}
@@ -0,0 +1,118 @@
+//go:build !testing
+// +build !testing
+
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/cpisdk"
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdsdk"
+	"k8s.io/klog"
+)
+
+// advertiseLoadBalancerVIP advertises the prefix of vip through the gateway if route advertisement is enabled. A
+// prefix that was added by the CCM is recorded in the RDE so that it is only ever withdrawn by the CCM.
+func advertiseLoadBalancerVIP(ctx context.Context, gm *vcdsdk.GatewayManager, cpiRdeManager *cpisdk.CPIRDEManager,
+	routeAdvertisement *vcdsdk.RouteAdvertisement, vip string) error {
+	if routeAdvertisement == nil || vip == "" {
+		return nil
+	}
+
+	clusterID := cpiRdeManager.RDEManager.ClusterID
+	prefix, added, err := gm.AdvertiseLoadBalancerVIP(ctx, vip, routeAdvertisement)
+	if added {
+		if rdeErr := cpiRdeManager.RDEManager.AddToVCDResourceSet(ctx, vcdsdk.ComponentCPI,
+			vcdsdk.VcdResourceAdvertisedPrefix, prefix, "", map[string]interface{}{"gateway": gm.GatewayRef.Name}); rdeErr != nil {
+			klog.Errorf("unable to record advertised prefix [%s] in RDE [%s]; it will not be withdrawn: [%v]",
+				prefix, clusterID, rdeErr)
+			if err == nil {
+				err = fmt.Errorf("unable to record advertised prefix [%s] in RDE [%s]: [%v]", prefix, clusterID, rdeErr)
+			}
+		}
+	}
+	if err != nil {
+		addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, cpisdk.RouteAdvertisementError, "", vip, err.Error())
+		if addToErrorSetErr != nil {
+			klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", cpisdk.RouteAdvertisementError, clusterID, addToErrorSetErr)
+		}
+		return fmt.Errorf("unable to advertise VIP [%s]: [%v]", vip, err)
+	}
+
+	err = cpiRdeManager.RDEManager.RemoveErrorByNameOrIdFromErrorSet(ctx, vcdsdk.ComponentCPI, cpisdk.RouteAdvertisementError, "", vip)
+	if err != nil {
+		klog.Errorf("there was an error removing CPI error [%s] from RDE [%s], [%v]", cpisdk.RouteAdvertisementError, clusterID, err)
+	}
+	return nil
+}
+
+// isAdvertisedPrefixRecorded returns true if prefix was added to the route advertisement by the CCM, as recorded
+// in the RDE.
+func isAdvertisedPrefixRecorded(ctx context.Context, cpiRdeManager *cpisdk.CPIRDEManager, prefix string) (bool, error) {
+	advertisedPrefixes, err := cpiRdeManager.RDEManager.GetVCDResources(ctx, vcdsdk.ComponentCPI,
+		vcdsdk.VcdResourceAdvertisedPrefix)
+	if err != nil {
+		return false, err
+	}
+	for _, advertisedPrefix := range advertisedPrefixes {
+		if advertisedPrefix.Name == prefix {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// withdrawLoadBalancerVIP withdraws the prefix of a released vip from the gateway if the CCM added it, as recorded
+// in the RDE, and no IP in use on the gateway is left in it. Prefixes that were already advertised, e.g. by the
+// administrator or for another cluster, are never withdrawn. Errors are recorded in the RDE and logged as the load
+// balancer itself is already gone.
+func withdrawLoadBalancerVIP(ctx context.Context, gm *vcdsdk.GatewayManager, cpiRdeManager *cpisdk.CPIRDEManager,
+	routeAdvertisement *vcdsdk.RouteAdvertisement, vip string) {
+	if routeAdvertisement == nil || vip == "" {
+		return
+	}
+
+	clusterID := cpiRdeManager.RDEManager.ClusterID
+	if clusterID == "" || strings.HasPrefix(clusterID, vcdsdk.NoRdePrefix) {
+		klog.Infof("ClusterID [%s] is empty or generated; not withdrawing prefix of VIP [%s]", clusterID, vip)
+		return
+	}
+
+	prefix, err := vcdsdk.GetVIPAdvertisedPrefix(vip, routeAdvertisement.Subnets)
+	recorded := false
+	if err == nil {
+		recorded, err = isAdvertisedPrefixRecorded(ctx, cpiRdeManager, prefix)
+	}
+	if err == nil && !recorded {
+		klog.Infof("prefix [%s] of VIP [%s] was not added by the CCM; not withdrawing it", prefix, vip)
+		return
+	}
+	withdrawn := false
+	if err == nil {
+		withdrawn, err = gm.WithdrawLoadBalancerVIP(ctx, vip, routeAdvertisement)
+	}
+	if err == nil && withdrawn {
+		err = cpiRdeManager.RDEManager.RemoveFromVCDResourceSet(ctx, vcdsdk.ComponentCPI,
+			vcdsdk.VcdResourceAdvertisedPrefix, prefix)
+	}
+	if err != nil {
+		addToErrorSetErr := cpiRdeManager.AddToErrorSetWithNameAndId(ctx, cpisdk.RouteAdvertisementError, "", vip, err.Error())
+		if addToErrorSetErr != nil {
+			klog.Errorf("error adding CPI error [%s] to RDE: [%s], [%v]", cpisdk.RouteAdvertisementError, clusterID, addToErrorSetErr)
+		}
+		klog.Errorf("unable to withdraw prefix of VIP [%s]: [%v]", vip, err)
+		return
+	}
+
+	err = cpiRdeManager.RDEManager.RemoveErrorByNameOrIdFromErrorSet(ctx, vcdsdk.ComponentCPI, cpisdk.RouteAdvertisementError, "", vip)
+	if err != nil {
+		klog.Errorf("there was an error removing CPI error [%s] from RDE [%s], [%v]", cpisdk.RouteAdvertisementError, clusterID, err)
+	}
+}
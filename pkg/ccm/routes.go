@@ -0,0 +1,137 @@
+//go:build !testing
+// +build !testing
+
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdsdk"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	cloudProvider "k8s.io/cloud-provider"
+	"k8s.io/klog"
+)
+
+// RoutesManager implements cloudProvider.Routes with static routes on the NSX-T edge gateway of the cluster
+// network. Each route forwards the pod CIDR of a node to the InternalIP of the node.
+type RoutesManager struct {
+	vcdClient       *vcdsdk.Client
+	kubeClient      *kubernetes.Clientset
+	ovdcNetworkName string
+	ovdcIdentifier  string
+	ipamSubnet      string
+	clusterID       string
+}
+
+var _ cloudProvider.Routes = &RoutesManager{}
+
+func newRoutes(vcdClient *vcdsdk.Client, ovdcNetworkName string, ovdcIdentifier string, ipamSubnet string,
+	clusterID string) cloudProvider.Routes {
+	return &RoutesManager{
+		vcdClient:       vcdClient,
+		kubeClient:      GetK8SClient(),
+		ovdcNetworkName: ovdcNetworkName,
+		ovdcIdentifier:  ovdcIdentifier,
+		ipamSubnet:      ipamSubnet,
+		clusterID:       clusterID,
+	}
+}
+
+func (rm *RoutesManager) getGatewayManager(ctx context.Context) (*vcdsdk.GatewayManager, error) {
+	if err := rm.vcdClient.RefreshBearerToken(); err != nil {
+		return nil, fmt.Errorf("error while obtaining access token: [%v]", err)
+	}
+
+	gm, err := vcdsdk.NewGatewayManager(ctx, rm.vcdClient, rm.ovdcNetworkName, rm.ipamSubnet, rm.ovdcIdentifier)
+	if err != nil {
+		return nil, fmt.Errorf("error while creating GatewayManager: [%v]", err)
+	}
+	return gm, nil
+}
+
+// getNodeInternalIP returns the first InternalIP of the node.
+func (rm *RoutesManager) getNodeInternalIP(ctx context.Context, nodeName types.NodeName) (string, error) {
+	node, err := rm.kubeClient.CoreV1().Nodes().Get(ctx, string(nodeName), metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to get node [%s]: [%v]", nodeName, err)
+	}
+	for _, address := range node.Status.Addresses {
+		if address.Type == v1.NodeInternalIP {
+			return address.Address, nil
+		}
+	}
+	return "", fmt.Errorf("node [%s] has no address of type [%s]", nodeName, v1.NodeInternalIP)
+}
+
+// ListRoutes lists all managed routes that belong to the specified clusterName
+func (rm *RoutesManager) ListRoutes(ctx context.Context, clusterName string) ([]*cloudProvider.Route, error) {
+	gm, err := rm.getGatewayManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	staticRouteRefs, err := gm.ListClusterStaticRoutes(ctx, rm.clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list static routes of cluster [%s]: [%v]", rm.clusterID, err)
+	}
+
+	routes := make([]*cloudProvider.Route, len(staticRouteRefs))
+	for idx, staticRouteRef := range staticRouteRefs {
+		routes[idx] = &cloudProvider.Route{
+			Name:            staticRouteRef.Name,
+			TargetNode:      types.NodeName(staticRouteRef.NodeName),
+			DestinationCIDR: staticRouteRef.NetworkCidr,
+		}
+	}
+	return routes, nil
+}
+
+// CreateRoute creates the described managed route
+// route.Name will be ignored, although the cloud-provider may use nameHint
+// to create a more user-meaningful name.
+func (rm *RoutesManager) CreateRoute(ctx context.Context, clusterName string, nameHint string,
+	route *cloudProvider.Route) error {
+	nodeIP, err := rm.getNodeInternalIP(ctx, route.TargetNode)
+	if err != nil {
+		return fmt.Errorf("unable to get next hop for route to [%s]: [%v]", route.DestinationCIDR, err)
+	}
+
+	gm, err := rm.getGatewayManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Creating route for [%s] via node [%s] with IP [%s]", route.DestinationCIDR, route.TargetNode, nodeIP)
+	if err = gm.CreateClusterStaticRoute(ctx, rm.clusterID, getTrimmedClusterID(rm.clusterID),
+		string(route.TargetNode), route.DestinationCIDR, nodeIP); err != nil {
+		return fmt.Errorf("unable to create route for [%s] via node [%s]: [%v]", route.DestinationCIDR,
+			route.TargetNode, err)
+	}
+	return nil
+}
+
+// DeleteRoute deletes the specified managed route
+// Route should be as returned by ListRoutes
+func (rm *RoutesManager) DeleteRoute(ctx context.Context, clusterName string, route *cloudProvider.Route) error {
+	gm, err := rm.getGatewayManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Deleting route for [%s] via node [%s]", route.DestinationCIDR, route.TargetNode)
+	if err = gm.DeleteClusterStaticRoute(ctx, rm.clusterID, string(route.TargetNode),
+		route.DestinationCIDR); err != nil {
+		return fmt.Errorf("unable to delete route for [%s] via node [%s]: [%v]", route.DestinationCIDR,
+			route.TargetNode, err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+//go:build !testing
+// +build !testing
+
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+)
+
+// refreshTokenHistorySize is the number of rotated refresh tokens that are remembered
+const refreshTokenHistorySize = 16
+
+// refreshTokenPersister writes the rotated refresh tokens of a service account to the basic-auth secret so that the
+// CCM can authenticate after a restart. It remembers the tokens it wrote, since the mounted secret is updated with a
+// delay and may still hold a token that was already used.
+type refreshTokenPersister struct {
+	secretRef     config.SecretReference
+	lock          sync.Mutex
+	refreshTokens []string // the latest token is last
+}
+
+func newRefreshTokenPersister(secretRef config.SecretReference) *refreshTokenPersister {
+	return &refreshTokenPersister{
+		secretRef:     secretRef,
+		refreshTokens: make([]string, 0),
+	}
+}
+
+// persist writes refreshToken to the refreshToken key of the basic-auth secret.
+func (rtp *refreshTokenPersister) persist(refreshToken string) error {
+	rtp.lock.Lock()
+	defer rtp.lock.Unlock()
+
+	rtp.refreshTokens = append(rtp.refreshTokens, refreshToken)
+	if len(rtp.refreshTokens) > refreshTokenHistorySize {
+		rtp.refreshTokens = rtp.refreshTokens[len(rtp.refreshTokens)-refreshTokenHistorySize:]
+	}
+
+	// secret data is base64 encoded when marshalled
+	patch, err := json.Marshal(map[string]interface{}{
+		"data": map[string][]byte{
+			"refreshToken": []byte(refreshToken),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create patch of secret [%s/%s]: [%v]", rtp.secretRef.Namespace,
+			rtp.secretRef.Name, err)
+	}
+	if _, err = GetK8SClient().CoreV1().Secrets(rtp.secretRef.Namespace).Patch(context.Background(),
+		rtp.secretRef.Name, k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("unable to patch refresh token of secret [%s/%s]: [%v]", rtp.secretRef.Namespace,
+			rtp.secretRef.Name, err)
+	}
+
+	klog.Infof("Persisted rotated refresh token to secret [%s/%s]", rtp.secretRef.Namespace, rtp.secretRef.Name)
+	return nil
+}
+
+// getLatestRefreshToken returns the last refresh token that was persisted, or an empty string if there is none.
+func (rtp *refreshTokenPersister) getLatestRefreshToken() string {
+	rtp.lock.Lock()
+	defer rtp.lock.Unlock()
+
+	if len(rtp.refreshTokens) == 0 {
+		return ""
+	}
+	return rtp.refreshTokens[len(rtp.refreshTokens)-1]
+}
+
+// isPersisted returns true if refreshToken was rotated by the CCM, and is hence already used or in use.
+func (rtp *refreshTokenPersister) isPersisted(refreshToken string) bool {
+	rtp.lock.Lock()
+	defer rtp.lock.Unlock()
+
+	for _, persistedRefreshToken := range rtp.refreshTokens {
+		if persistedRefreshToken == refreshToken {
+			return true
+		}
+	}
+	return false
+}
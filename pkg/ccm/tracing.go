@@ -0,0 +1,121 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"github.com/vmware/cloud-provider-for-cloud-director/release"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+const (
+	tracerName = "github.com/vmware/cloud-provider-for-cloud-director/pkg/ccm"
+
+	defaultTracingServiceName  = "vmware-cloud-director-ccm"
+	defaultTracingSamplingRate = 1.0
+)
+
+// TracingOptions configures the export of the traces of the CPI to an OpenTelemetry collector. Tracing is disabled
+// if no endpoint is set.
+type TracingOptions struct {
+	OTLPEndpoint string
+	OTLPInsecure bool
+	ServiceName  string
+	SamplingRate float64
+}
+
+func NewTracingOptions() *TracingOptions {
+	return &TracingOptions{
+		ServiceName:  defaultTracingServiceName,
+		SamplingRate: defaultTracingSamplingRate,
+	}
+}
+
+// AddFlags adds the tracing flags to fs.
+func (opts *TracingOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&opts.OTLPEndpoint, "tracing-otlp-endpoint", opts.OTLPEndpoint,
+		"host:port of the OTLP gRPC endpoint to which traces are exported; tracing is disabled if empty")
+	fs.BoolVar(&opts.OTLPInsecure, "tracing-otlp-insecure", opts.OTLPInsecure,
+		"export traces to the OTLP endpoint without TLS")
+	fs.StringVar(&opts.ServiceName, "tracing-service-name", opts.ServiceName,
+		"service name of the exported traces")
+	fs.Float64Var(&opts.SamplingRate, "tracing-sampling-rate", opts.SamplingRate,
+		"fraction of the load balancer operations that are traced, between 0 and 1")
+}
+
+// Validate returns an error if the tracing options are invalid.
+func (opts *TracingOptions) Validate() error {
+	if opts.SamplingRate < 0 || opts.SamplingRate > 1 {
+		return fmt.Errorf("tracing sampling rate [%v] should be between 0 and 1", opts.SamplingRate)
+	}
+	return nil
+}
+
+// SetupTracing sets up the global tracer provider to export the traces of the CPI to the OTLP endpoint, and returns
+// a function that flushes and stops the export. Nothing is set up if no endpoint is configured.
+func (opts *TracingOptions) SetupTracing(ctx context.Context) (func(context.Context) error, error) {
+	if opts.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	driverOpts := []otlpgrpc.Option{otlpgrpc.WithEndpoint(opts.OTLPEndpoint)}
+	if opts.OTLPInsecure {
+		driverOpts = append(driverOpts, otlpgrpc.WithInsecure())
+	}
+	exporter, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(driverOpts...))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create OTLP exporter for endpoint [%s]: [%v]", opts.OTLPEndpoint, err)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SamplingRate))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.ServiceNameKey.String(opts.ServiceName),
+			semconv.ServiceVersionKey.String(release.Version),
+		)),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	klog.Infof("Exporting traces to OTLP endpoint [%s] with sampling rate [%v]", opts.OTLPEndpoint,
+		opts.SamplingRate)
+	return tracerProvider.Shutdown, nil
+}
+
+// startServiceSpan starts the span of an operation of the CPI on the load balancer of service.
+func startServiceSpan(ctx context.Context, spanName string, service *v1.Service) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, spanName, trace.WithAttributes(
+		attribute.String("k8s.namespace.name", service.Namespace),
+		attribute.String("k8s.service.name", service.Name),
+	))
+}
+
+// endSpan ends span and records err as its status.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
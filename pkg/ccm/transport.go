@@ -0,0 +1,40 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/config"
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdsdk"
+)
+
+// getTransportConfig returns the transport config of the VCD clients from the cloud config, trusting the CAs of
+// caBundle or the system CAs if caBundle is empty.
+func getTransportConfig(cloudConfig *config.CloudConfig, caBundle []byte) (*vcdsdk.TransportConfig, error) {
+	transport := cloudConfig.VCD.Transport
+	transportConfig := &vcdsdk.TransportConfig{
+		ProxyURL:              transport.ProxyURL,
+		NoProxy:               transport.NoProxy,
+		DialTimeout:           time.Duration(transport.DialTimeoutSeconds) * time.Second,
+		TLSHandshakeTimeout:   time.Duration(transport.TLSHandshakeTimeoutSeconds) * time.Second,
+		ResponseHeaderTimeout: time.Duration(transport.ResponseTimeoutSeconds) * time.Second,
+		MaxIdleConns:          transport.MaxIdleConns,
+
+		QPS:                            cloudConfig.VCD.RateLimit.QPS,
+		Burst:                          cloudConfig.VCD.RateLimit.Burst,
+		MaxConcurrentGatewayOperations: cloudConfig.VCD.RateLimit.MaxConcurrentGatewayOperations,
+	}
+	if len(caBundle) > 0 {
+		rootCAs, err := vcdsdk.NewCertPoolFromPEM(caBundle)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse CA bundle: [%v]", err)
+		}
+		transportConfig.RootCAs = rootCAs
+	}
+	return transportConfig, nil
+}
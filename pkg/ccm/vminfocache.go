@@ -7,14 +7,30 @@ package ccm
 
 import (
 	"fmt"
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/config"
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/util"
 	"github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdsdk"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog"
 	"sync"
 	"time"
 
 	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
 	v1 "k8s.io/api/core/v1"
-	v1helper "k8s.io/cloud-provider/node/helpers"
+)
+
+const (
+	nodeLabelOVDC            = "cloud-director.vmware.com/ovdc"
+	nodeLabelPlacementPolicy = "cloud-director.vmware.com/placement-policy"
+	nodeLabelSizingPolicy    = "cloud-director.vmware.com/sizing-policy"
+	nodeLabelStorageProfile  = "cloud-director.vmware.com/storage-profile"
+	nodeLabelVAppName        = "cloud-director.vmware.com/vapp"
+	// nodeMetadataLabelPrefix is followed by the key of the VCD metadata entry
+	nodeMetadataLabelPrefix = "metadata.cloud-director.vmware.com/"
+
+	// nodeAnnotationVMStatus is the VCD status of the VM of the node at the last shutdown check
+	nodeAnnotationVMStatus = "cloud-director.vmware.com/vm-status"
 )
 
 type VmInfo struct {
@@ -25,33 +41,190 @@ type VmInfo struct {
 	Name      string
 	Type      string
 	Addresses []v1.NodeAddress
+	Labels    map[string]string
 	TimeStamp time.Time
+	// fingerprint of the VM record from which the VM was last fetched by the inventory refresher
+	fingerprint string
+	// fetchedAt is when the VM was last fetched from VCD, as opposed to reused by the inventory refresher
+	fetchedAt time.Time
 }
 
-// VmInfoCache caches VM details. Ideally we need an LRU cache with ttl-based expiry. But since we have O(1k) nodes
-// per cluster, we can ignore limits and expiry.
+const (
+	// vmInfoCacheNegativeExpiry is the time for which a VM that was not found is not searched again
+	vmInfoCacheNegativeExpiry = 10 * time.Second
+	// vmInfoCacheMaxSize bounds each index of the cache
+	vmInfoCacheMaxSize = 10000
+	// vmInfoMaxReuseAge is the time after which the inventory refresher fetches a VM even if its record did not
+	// change, since metadata, secondary NICs and compute policy names are not part of the VM record
+	vmInfoMaxReuseAge = 10 * time.Minute
+
+	vmInfoCacheNameIndex = "name"
+	vmInfoCacheUUIDIndex = "uuid"
+)
+
+// VmInfoCache caches VM details in separate indexes by name and by UUID. Entries expire after the expiry of the
+// cache, and VMs that are not found are cached for a short time so that deleted nodes are not searched repeatedly.
 type VmInfoCache struct {
-	rwLock          sync.RWMutex
-	expiry          time.Duration
-	nameMap         map[string]*VmInfo
-	uuidMap         map[string]*VmInfo
+	nameIndex       *util.TTLCache
+	uuidIndex       *util.TTLCache
 	client          *vcdsdk.Client
 	clusterVAppName string
 	zm              *vcdsdk.ZoneMap
+	// computePolicyNames maps compute policy URNs to names; they are dropped after vmInfoMaxReuseAge so that renamed
+	// policies are picked up along with the VMs that are fetched again
+	computePolicyNames       map[string]string
+	computePolicyNamesExpiry time.Time
+	computePolicyLock        sync.Mutex
+	nodeLabels               config.NodeLabelsConfig
+	nodeAddresses            config.NodeAddressesConfig
+	vmDiscovery              config.VMDiscoveryConfig
+	// natAddressResolver is nil unless external IPs are obtained from NAT rules
+	natAddressResolver *natAddressResolver
 }
 
-func newVmInfoCache(client *vcdsdk.Client, clusterVAppName string, expiry time.Duration, zm *vcdsdk.ZoneMap) *VmInfoCache {
+func newVmInfoCache(client *vcdsdk.Client, clusterVAppName string, expiry time.Duration, zm *vcdsdk.ZoneMap,
+	nodeLabels config.NodeLabelsConfig, nodeAddresses config.NodeAddressesConfig, vmDiscovery config.VMDiscoveryConfig,
+	natAddressResolver *natAddressResolver) *VmInfoCache {
+	registerMetrics()
 	return &VmInfoCache{
-		expiry:          expiry,
-		nameMap:         make(map[string]*VmInfo),
-		uuidMap:         make(map[string]*VmInfo),
-		client:          client,
-		clusterVAppName: clusterVAppName,
-		zm:              zm,
+		nameIndex:          util.NewTTLCache(expiry, vmInfoCacheNegativeExpiry, vmInfoCacheMaxSize),
+		uuidIndex:          util.NewTTLCache(expiry, vmInfoCacheNegativeExpiry, vmInfoCacheMaxSize),
+		client:             client,
+		clusterVAppName:    clusterVAppName,
+		zm:                 zm,
+		computePolicyNames: make(map[string]string),
+		nodeLabels:         nodeLabels,
+		nodeAddresses:      nodeAddresses,
+		vmDiscovery:        vmDiscovery,
+		natAddressResolver: natAddressResolver,
 	}
 }
 
-func (vmic *VmInfoCache) vmToVMInfo(vm *govcd.VM, ovdcIdentifier string, captureTime time.Time) (*VmInfo, error) {
+// getComputePolicyName returns the name of the compute policy referred to by computePolicyRef, or an empty string
+// if it cannot be obtained.
+func (vmic *VmInfoCache) getComputePolicyName(computePolicyRef *types.Reference, vmName string) string {
+	computePolicyID := vcdsdk.GetComputePolicyRefID(computePolicyRef)
+	if computePolicyID == "" {
+		return ""
+	}
+
+	vmic.computePolicyLock.Lock()
+	defer vmic.computePolicyLock.Unlock()
+	if now := time.Now(); now.After(vmic.computePolicyNamesExpiry) {
+		vmic.computePolicyNames = make(map[string]string)
+		vmic.computePolicyNamesExpiry = now.Add(vmInfoMaxReuseAge)
+	}
+	if computePolicyName, ok := vmic.computePolicyNames[computePolicyID]; ok {
+		return computePolicyName
+	}
+
+	computePolicyName := computePolicyRef.Name
+	if computePolicyName == "" {
+		orgManager := vcdsdk.OrgManager{
+			Client:  vmic.client,
+			OrgName: vmic.client.ClusterOrgName,
+		}
+		var err error
+		if computePolicyName, err = orgManager.GetComputePolicyNameFromID(computePolicyID); err != nil {
+			klog.Infof("unable to get name of compute policy [%s] of VM [%s]: [%v]", computePolicyID, vmName, err)
+			return ""
+		}
+	}
+	vmic.computePolicyNames[computePolicyID] = computePolicyName
+	return computePolicyName
+}
+
+// getInstanceType returns the name of the sizing policy of the VM, sanitized since it becomes the value of the
+// instance type label of the node. If the VM has no sizing policy other than the system default one, or its name has
+// no valid label characters, an instance type of the form <vcpu>c-<memGiB>g is synthesized from the VM spec.
+func (vmic *VmInfoCache) getInstanceType(vm *govcd.VM) string {
+	if vm.VM.ComputePolicy != nil {
+		sizingPolicyName := vmic.getComputePolicyName(vm.VM.ComputePolicy.VmSizingPolicy, vm.VM.Name)
+		if sizingPolicyName != "" && sizingPolicyName != vcdsdk.SystemDefaultComputePolicyName {
+			if instanceType := util.SanitizeLabelValue(sizingPolicyName); instanceType != "" {
+				return instanceType
+			}
+		}
+	}
+
+	return vcdsdk.GetVMSynthesizedInstanceType(vm.VM)
+}
+
+// getVAppName returns the name of the vApp of the VM, which is vAppName if it is already known. It is looked up from
+// the parent link of the VM otherwise, since VMs found by metadata or in another zone are not in the cluster vApp.
+func getVAppName(vm *govcd.VM, vAppName string) string {
+	if vAppName != "" {
+		return vAppName
+	}
+	for _, link := range vm.VM.Link {
+		if link != nil && link.Type == types.MimeVApp && link.Rel == "up" && link.Name != "" {
+			return link.Name
+		}
+	}
+	vApp, err := vm.GetParentVApp()
+	if err != nil {
+		klog.Infof("unable to get vApp of VM [%s]; vApp label will not be set: [%v]", vm.VM.Name, err)
+		return ""
+	}
+	return vApp.VApp.Name
+}
+
+// getLabels returns the node labels for the VCD facts about the VM that are enabled in the config. vAppName is the
+// name of the vApp of the VM if it is known, and is looked up otherwise.
+func (vmic *VmInfoCache) getLabels(vm *govcd.VM, ovdcIdentifier string, vAppName string) map[string]string {
+	labels := make(map[string]string)
+	addLabel := func(key string, value string) {
+		if sanitizedValue := util.SanitizeLabelValue(value); sanitizedValue != "" {
+			labels[key] = sanitizedValue
+		}
+	}
+
+	for _, label := range vmic.nodeLabels.Labels {
+		switch label {
+		case config.NodeLabelOVDC:
+			addLabel(nodeLabelOVDC, ovdcIdentifier)
+		case config.NodeLabelPlacementPolicy:
+			if vm.VM.ComputePolicy != nil {
+				addLabel(nodeLabelPlacementPolicy,
+					vmic.getComputePolicyName(vm.VM.ComputePolicy.VmPlacementPolicy, vm.VM.Name))
+			}
+		case config.NodeLabelSizingPolicy:
+			if vm.VM.ComputePolicy != nil {
+				addLabel(nodeLabelSizingPolicy,
+					vmic.getComputePolicyName(vm.VM.ComputePolicy.VmSizingPolicy, vm.VM.Name))
+			}
+		case config.NodeLabelStorageProfile:
+			if vm.VM.StorageProfile != nil {
+				addLabel(nodeLabelStorageProfile, vm.VM.StorageProfile.Name)
+			}
+		case config.NodeLabelVAppName:
+			addLabel(nodeLabelVAppName, getVAppName(vm, vAppName))
+		}
+	}
+
+	if len(vmic.nodeLabels.MetadataKeys) == 0 {
+		return labels
+	}
+	metadata, err := vm.GetMetadata()
+	if err != nil {
+		klog.Infof("unable to get metadata of VM [%s]; metadata labels will not be updated: [%v]", vm.VM.Name, err)
+		return labels
+	}
+	metadataKeys := util.NewSet(vmic.nodeLabels.MetadataKeys)
+	for _, entry := range metadata.MetadataEntry {
+		if entry == nil || entry.TypedValue == nil || !metadataKeys.Contains(entry.Key) {
+			continue
+		}
+		if labelName := util.SanitizeLabelValue(entry.Key); labelName != "" {
+			addLabel(nodeMetadataLabelPrefix+labelName, entry.TypedValue.Value)
+		}
+	}
+
+	return labels
+}
+
+func (vmic *VmInfoCache) vmToVMInfo(vm *govcd.VM, ovdcIdentifier string, vAppName string,
+	captureTime time.Time) (*VmInfo, error) {
 
 	if vm == nil {
 		return nil, fmt.Errorf("vm parameter should not be nil")
@@ -63,33 +236,14 @@ func (vmic *VmInfoCache) vmToVMInfo(vm *govcd.VM, ovdcIdentifier string, capture
 	vmInfo := &VmInfo{
 		vm:        vm,
 		OVDC:      ovdcIdentifier,
+		Zone:      vmic.zm.GetZoneForOVDC(ovdcIdentifier),
 		UUID:      vm.VM.ID,
 		Name:      vm.VM.Name,
-		Type:      "",
+		Type:      vmic.getInstanceType(vm),
+		Addresses: vmic.getNodeAddresses(vm),
+		Labels:    vmic.getLabels(vm, ovdcIdentifier, vAppName),
 		TimeStamp: captureTime,
-	}
-
-	if vm.VM.NetworkConnectionSection != nil {
-		vmAddresses := make([]v1.NodeAddress, 0)
-		for _, netConn := range vm.VM.NetworkConnectionSection.NetworkConnection {
-			if netConn.NetworkConnectionIndex == vm.VM.NetworkConnectionSection.PrimaryNetworkConnectionIndex {
-				v1helper.AddToNodeAddresses(&vmAddresses,
-					v1.NodeAddress{
-						Type:    v1.NodeInternalIP,
-						Address: netConn.IPAddress,
-					})
-			}
-			v1helper.AddToNodeAddresses(&vmAddresses,
-				v1.NodeAddress{
-					Type:    v1.NodeExternalIP,
-					Address: netConn.IPAddress,
-				},
-				v1.NodeAddress{
-					Type:    v1.NodeHostName,
-					Address: vm.VM.Name,
-				})
-		}
-		vmInfo.Addresses = vmAddresses
+		fetchedAt: captureTime,
 	}
 
 	return vmInfo, nil
@@ -100,93 +254,180 @@ func (vmic *VmInfoCache) SearchVMAcrossVDCs(vmName string, vmId string) (*govcd.
 		return nil, "", fmt.Errorf("error while obtaining access token: [%v]", err)
 	}
 
-	var ovdcIdentifierList []string = nil
-	var isMultiZoneCluster bool
-	if vmic.zm != nil {
-		for key, _ := range vmic.zm.VdcToZoneMap {
-			ovdcIdentifierList = append(ovdcIdentifierList, key)
-		}
-		isMultiZoneCluster = true
-	} else {
-		ovdcIdentifierList = []string{
-			vmic.client.ClusterOVDCIdentifier,
+	orgManager := vcdsdk.OrgManager{
+		Client:  vmic.client,
+		OrgName: vmic.client.ClusterOrgName,
+	}
+
+	if vmic.vmDiscovery.Mode == config.VMDiscoveryModeMetadata {
+		vm, ovdcIdentifier, err := orgManager.SearchVMByMetadata(vmName, vmId, vmic.vmDiscovery.MetadataKey,
+			vmic.vmDiscovery.MetadataValue)
+		if err != govcd.ErrorEntityNotFound {
+			return vm, ovdcIdentifier, err
 		}
-		isMultiZoneCluster = false
+		klog.Infof("VM [%s, %s] with metadata [%s=%s] not found, hence searching the cluster vApp [%s]", vmName,
+			vmId, vmic.vmDiscovery.MetadataKey, vmic.vmDiscovery.MetadataValue, vmic.clusterVAppName)
+	}
+
+	// in multi-zone clusters, the cluster has a vApp in each of the VDCs of the zone map
+	return orgManager.SearchVMAcrossVDCs(vmName, vmic.clusterVAppName, vmId, vmic.zm != nil)
+}
+
+// refreshInventory lists all VMs of the cluster with a single query and replaces the cached VMs with them. In
+// metadata discovery mode, the VMs with the metadata entry of the cluster are listed as well. Only VMs that are new,
+// whose record changed since the last refresh or that were fetched more than vmInfoMaxReuseAge ago are fetched; the
+// others are reused with their zone looked up again in case the zone map changed.
+func (vmic *VmInfoCache) refreshInventory() error {
+	if err := vmic.client.RefreshBearerToken(); err != nil {
+		return fmt.Errorf("error while obtaining access token: [%v]", err)
 	}
+	session := vmic.client.Session()
 
 	orgManager := vcdsdk.OrgManager{
 		Client:  vmic.client,
 		OrgName: vmic.client.ClusterOrgName,
 	}
+	vmRecordList, err := orgManager.ListClusterVMRecords(vmic.clusterVAppName, vmic.zm != nil)
+	if err != nil {
+		return fmt.Errorf("unable to list VMs of cluster [%s]: [%v]", vmic.clusterVAppName, err)
+	}
+	if vmic.vmDiscovery.Mode == config.VMDiscoveryModeMetadata {
+		metadataVMRecordList, err := orgManager.ListVMRecordsByMetadata(vmic.vmDiscovery.MetadataKey,
+			vmic.vmDiscovery.MetadataValue)
+		if err != nil {
+			return fmt.Errorf("unable to list VMs with metadata [%s=%s]: [%v]", vmic.vmDiscovery.MetadataKey,
+				vmic.vmDiscovery.MetadataValue, err)
+		}
+		vmRecordList = vcdsdk.MergeVMRecordLists(metadataVMRecordList, vmRecordList)
+	}
 
-	return orgManager.SearchVMAcrossVDCs(vmName, vmic.clusterVAppName, vmId, isMultiZoneCluster)
-}
+	cachedVMInfos := vmic.uuidIndex.Values()
+	now := time.Now()
+	nameValues := make(map[string]interface{}, len(vmRecordList))
+	uuidValues := make(map[string]interface{}, len(vmRecordList))
+	for _, vmRecord := range vmRecordList {
+		fingerprint := vcdsdk.GetVMRecordFingerprint(vmRecord)
 
-func (vmic *VmInfoCache) GetByName(vmName string) (*VmInfo, error) {
-	vmic.rwLock.Lock()
-	defer vmic.rwLock.Unlock()
+		var vmInfo *VmInfo
+		if cachedValue, ok := cachedVMInfos[vmRecord.ID]; ok && cachedValue.(*VmInfo).fingerprint == fingerprint &&
+			now.Sub(cachedValue.(*VmInfo).fetchedAt) < vmInfoMaxReuseAge {
+			// copy since readers may hold the cached value
+			refreshedVMInfo := *cachedValue.(*VmInfo)
+			refreshedVMInfo.TimeStamp = now
+			refreshedVMInfo.Zone = vmic.zm.GetZoneForOVDC(refreshedVMInfo.OVDC)
+			if vmic.natAddressResolver != nil {
+				// NAT rules are not part of the VM record
+				refreshedVMInfo.Addresses = vmic.getNodeAddresses(refreshedVMInfo.vm)
+			}
+			vmInfo = &refreshedVMInfo
+		} else {
+			vm, err := session.VCDClient.Client.GetVMByHref(vmRecord.HREF)
+			if err != nil {
+				klog.Infof("unable to get VM [%s] by HREF [%s]; it will be looked up on demand: [%v]",
+					vmRecord.Name, vmRecord.HREF, err)
+				continue
+			}
+			if vmInfo, err = vmic.vmToVMInfo(vm, vmRecord.VdcName, vmRecord.ContainerName, now); err != nil {
+				klog.Infof("unable to convert VM [%s] to vmInfo; it will be looked up on demand: [%v]",
+					vmRecord.Name, err)
+				continue
+			}
+			vmInfo.fingerprint = fingerprint
+		}
 
-	now := time.Now()
-	if vmInfo, ok := vmic.nameMap[vmName]; ok {
-		if now.Sub(vmInfo.TimeStamp) < vmic.expiry {
-			klog.Infof("now is [%v], info captured at [%v], hence reusing cached value", now, vmInfo.TimeStamp)
-			return vmInfo, nil
+		nameValues[vmInfo.Name] = vmInfo
+		uuidValues[vmInfo.UUID] = vmInfo
+	}
+
+	vmic.nameIndex.Replace(nameValues)
+	vmic.uuidIndex.Replace(uuidValues)
+
+	klog.V(3).Infof("refreshed inventory of [%d] VMs of cluster [%s]", len(uuidValues), vmic.clusterVAppName)
+	return nil
+}
+
+// startInventoryRefresher refreshes the inventory of cluster VMs every refreshInterval until stopCh is closed. The
+// expiry of the cache should be longer than refreshInterval so that VMs do not expire between refreshes.
+func (vmic *VmInfoCache) startInventoryRefresher(refreshInterval time.Duration, stopCh <-chan struct{}) {
+	klog.Infof("refreshing inventory of VMs of cluster [%s] every [%v]", vmic.clusterVAppName, refreshInterval)
+	go wait.Until(func() {
+		if err := vmic.refreshInventory(); err != nil {
+			klog.Errorf("unable to refresh VM inventory; VMs will be looked up on demand: [%v]", err)
 		}
+	}, refreshInterval, stopCh)
+}
 
-		// don't use old values
-		delete(vmic.nameMap, vmName)
+// lookup returns the VM cached in index under key. A VM that is cached as not found is reported as
+// govcd.ErrorEntityNotFound. Otherwise, on a miss, the VM is searched with search and cached in both indexes.
+func (vmic *VmInfoCache) lookup(index *util.TTLCache, indexName string, key string,
+	search func() (*govcd.VM, string, error)) (*VmInfo, error) {
+	cachedValue, result := index.Get(key)
+	vmInfoCacheLookups.WithLabelValues(indexName, string(result)).Inc()
+	switch result {
+	case util.CacheHit:
+		return cachedValue.(*VmInfo), nil
+	case util.CacheNegativeHit:
+		return nil, govcd.ErrorEntityNotFound
 	}
 
-	vm, ovdcIdentifier, err := vmic.SearchVMAcrossVDCs(vmName, "")
+	vm, ovdcIdentifier, err := search()
 	if err != nil {
 		if err == govcd.ErrorEntityNotFound {
+			index.SetNegative(key)
 			return nil, err
 		}
 		return nil, fmt.Errorf("unable to find VM [%s] in org [%s] for cluster [%s]: [%v]",
-			vmName, vmic.client.ClusterOrgName, vmic.clusterVAppName, err)
+			key, vmic.client.ClusterOrgName, vmic.clusterVAppName, err)
 	}
 
-	vmInfo, err := vmic.vmToVMInfo(vm, ovdcIdentifier, time.Now())
+	vmInfo, err := vmic.vmToVMInfo(vm, ovdcIdentifier, "", time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("unable to convert vm struct [%v] to vmInfo: [%v]", vm, err)
 	}
 
-	vmic.nameMap[vmName] = vmInfo
-	vmic.uuidMap[vm.VM.ID] = vmInfo
+	vmic.nameIndex.Set(vmInfo.Name, vmInfo)
+	vmic.uuidIndex.Set(vmInfo.UUID, vmInfo)
 
 	return vmInfo, nil
 }
 
-func (vmic *VmInfoCache) GetByUUID(vmUUID string) (*VmInfo, error) {
-	vmic.rwLock.Lock()
-	defer vmic.rwLock.Unlock()
+func (vmic *VmInfoCache) GetByName(vmName string) (*VmInfo, error) {
+	return vmic.lookup(vmic.nameIndex, vmInfoCacheNameIndex, vmName, func() (*govcd.VM, string, error) {
+		return vmic.SearchVMAcrossVDCs(vmName, "")
+	})
+}
 
-	now := time.Now()
-	if vmInfo, ok := vmic.uuidMap[vmUUID]; ok {
-		if now.Sub(vmInfo.TimeStamp) < vmic.expiry {
-			klog.Infof("now is [%v], info captured at [%v], hence reusing cached value", now, vmInfo.TimeStamp)
-			return vmInfo, nil
-		}
+func (vmic *VmInfoCache) GetByUUID(vmUUID string) (*VmInfo, error) {
+	return vmic.lookup(vmic.uuidIndex, vmInfoCacheUUIDIndex, vmUUID, func() (*govcd.VM, string, error) {
+		return vmic.SearchVMAcrossVDCs("", vmUUID)
+	})
+}
 
-		// don't use old values
-		delete(vmic.nameMap, vmUUID)
+// getShutdownStatus returns whether the VM is shut down along with its current VCD status. A VM that is no longer
+// available is shut down.
+func (vmic *VmInfoCache) getShutdownStatus(vmInfo *VmInfo) (bool, string, error) {
+	if vmInfo.vm == nil {
+		return false, "", fmt.Errorf("vm struct in vmInfo nil for vm uuid [%s]", vmInfo.UUID)
 	}
-	vm, ovdcIdentifier, err := vmic.SearchVMAcrossVDCs("", vmUUID)
+
+	status, err := vmInfo.vm.GetStatus()
 	if err != nil {
-		if err == govcd.ErrorEntityNotFound {
-			return nil, err
+		vdcManager, vdcManagerErr := vcdsdk.NewVDCManager(vmic.client, vmic.client.ClusterOrgName,
+			vmic.client.ClusterOVDCIdentifier)
+		if vdcManagerErr != nil {
+			return false, "", fmt.Errorf("error creating VDC manager object: [%v]", err)
+		}
+		if vdcManager.IsVmNotAvailable(err) {
+			return true, "", nil
 		}
-		return nil, fmt.Errorf("unable to find VM [%s] in org [%s] for cluster [%s]: [%v]",
-			vmUUID, vmic.client.ClusterOrgName, vmic.clusterVAppName, err)
-	}
 
-	vmInfo, err := vmic.vmToVMInfo(vm, ovdcIdentifier, time.Now())
-	if err != nil {
-		return nil, fmt.Errorf("unable to convert vm struct [%v] to vmInfo: [%v]", vm, err)
+		return false, "", fmt.Errorf("unable to get status of vm uuid [%s], name [%s]: [%v]",
+			vmInfo.UUID, vmInfo.Name, err)
 	}
 
-	vmic.nameMap[vmUUID] = vmInfo
-	vmic.uuidMap[vm.VM.ID] = vmInfo
-
-	return vmInfo, nil
+	shutdown, ok := vcdsdk.IsVMStatusShutdown(status)
+	if !ok {
+		klog.Infof("vm [%s] has unknown status [%s], hence it is not reported as shut down", vmInfo.Name, status)
+	}
+	return shutdown, status, nil
 }
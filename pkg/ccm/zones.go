@@ -0,0 +1,119 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccm
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"k8s.io/apimachinery/pkg/types"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog"
+)
+
+type zones struct {
+	vmInfoCache *VmInfoCache
+	region      string
+}
+
+// Zones returns a zones interface. Also returns true if the interface is supported, false otherwise.
+func (vcdCP *VCDCloudProvider) Zones() (cloudprovider.Zones, bool) {
+	return vcdCP.zones, true
+}
+
+func newZones(vmInfoCache *VmInfoCache, region string) cloudprovider.Zones {
+	return &zones{
+		vmInfoCache: vmInfoCache,
+		region:      region,
+	}
+}
+
+func (z *zones) vmInfoToZone(vmInfo *VmInfo) cloudprovider.Zone {
+	return cloudprovider.Zone{
+		FailureDomain: vmInfo.Zone,
+		Region:        z.region,
+	}
+}
+
+// getClusterOVDCName returns the name of the OVDC of the cluster. The OVDC of the cluster may be configured by ID, while
+// zones are mapped from OVDC names. The identifier is returned if the OVDC cannot be found.
+func (z *zones) getClusterOVDCName() string {
+	client := z.vmInfoCache.client
+	vdc, err := client.GetVDCByNameOrId(client.ClusterOrgName, client.ClusterOVDCIdentifier)
+	if err != nil {
+		klog.Errorf("unable to get OVDC [%s] of org [%s]: [%v]", client.ClusterOVDCIdentifier,
+			client.ClusterOrgName, err)
+		return client.ClusterOVDCIdentifier
+	}
+	if vdc.Vdc == nil || vdc.Vdc.Name == "" {
+		return client.ClusterOVDCIdentifier
+	}
+	return vdc.Vdc.Name
+}
+
+// GetZone returns the Zone containing the current failure zone and locality region that the program is running in.
+// The CCM runs with host networking, so the host name is the name of the node it runs on. If that VM cannot be
+// found, the zone of the OVDC of the cluster is returned.
+func (z *zones) GetZone(ctx context.Context) (cloudprovider.Zone, error) {
+	nodeName := os.Getenv("NODE_NAME")
+	if nodeName == "" {
+		hostName, err := os.Hostname()
+		if err != nil {
+			return cloudprovider.Zone{}, fmt.Errorf("unable to get host name: [%v]", err)
+		}
+		nodeName = hostName
+	}
+	klog.Infof("zones.GetZone called for node [%s]", nodeName)
+
+	vmInfo, err := z.vmInfoCache.GetByName(nodeName)
+	if err != nil {
+		ovdcName := z.getClusterOVDCName()
+		klog.Infof("unable to find VM [%s]; using zone of OVDC [%s]: [%v]", nodeName, ovdcName, err)
+		return cloudprovider.Zone{
+			FailureDomain: z.vmInfoCache.zm.GetZoneForOVDC(ovdcName),
+			Region:        z.region,
+		}, nil
+	}
+
+	return z.vmInfoToZone(vmInfo), nil
+}
+
+// GetZoneByProviderID returns the Zone containing the current zone and locality region of the node specified by providerID
+// This method is particularly used in the context of external cloud providers where node initialization must be done
+// outside the kubelets.
+func (z *zones) GetZoneByProviderID(ctx context.Context, providerID string) (cloudprovider.Zone, error) {
+	klog.Infof("zones.GetZoneByProviderID called with [%s]", providerID)
+
+	vmUUID := getUUIDFromProviderID(providerID)
+	vmInfo, err := z.vmInfoCache.GetByUUID(vmUUID)
+	if err != nil {
+		if err == govcd.ErrorEntityNotFound {
+			return cloudprovider.Zone{}, cloudprovider.InstanceNotFound
+		}
+		return cloudprovider.Zone{}, fmt.Errorf("unable to find zone of vm uuid [%s]: [%v]", vmUUID, err)
+	}
+
+	return z.vmInfoToZone(vmInfo), nil
+}
+
+// GetZoneByNodeName returns the Zone containing the current zone and locality region of the node specified by node name
+// This method is particularly used in the context of external cloud providers where node initialization must be done
+// outside the kubelets.
+func (z *zones) GetZoneByNodeName(ctx context.Context, nodeName types.NodeName) (cloudprovider.Zone, error) {
+	klog.Infof("zones.GetZoneByNodeName called with [%s]", nodeName)
+
+	vmInfo, err := z.vmInfoCache.GetByName(string(nodeName))
+	if err != nil {
+		if err == govcd.ErrorEntityNotFound {
+			return cloudprovider.Zone{}, cloudprovider.InstanceNotFound
+		}
+		return cloudprovider.Zone{}, fmt.Errorf("unable to find zone of vm [%s]: [%v]", nodeName, err)
+	}
+
+	return z.vmInfoToZone(vmInfo), nil
+}
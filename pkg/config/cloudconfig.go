@@ -11,7 +11,11 @@ import (
 	"gopkg.in/yaml.v2"
 	"io"
 	"k8s.io/klog"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 )
 
@@ -22,6 +26,16 @@ type VCDConfig struct {
 	Org                  string `yaml:"org"`
 	UserOrg              string // this defaults to Org or a prefix of User
 	IsZoneEnabledCluster bool   `yaml:"isZoneEnabledCluster"`
+	// Region is reported as the topology.kubernetes.io/region of all nodes. If empty, it is derived as per RegionSource.
+	Region string `yaml:"region,omitempty"`
+	// RegionSource is one of RegionSourceOrg (default) or RegionSourceSite
+	RegionSource string `yaml:"regionSource,omitempty"`
+	// TLS configures the verification of the certificate of the VCD site
+	TLS TLSConfig `yaml:"tls,omitempty"`
+	// Transport configures the proxy, timeouts and connections to the VCD site
+	Transport TransportConfig `yaml:"transport,omitempty"`
+	// RateLimit configures the rate of requests to the VCD site and the concurrent modifications of a gateway
+	RateLimit RateLimitConfig `yaml:"rateLimit,omitempty"`
 
 	// It is allowed to pass the following variables using the config. However,
 	// that is unsafe security practice. However, there can be user scenarios and
@@ -34,8 +48,96 @@ type VCDConfig struct {
 	User         string
 	Secret       string
 	RefreshToken string
+	// ServiceAccountClientID is obtained from the clientId file of the secret. If it is set, the RefreshToken is the
+	// one of the service account with this client ID, and it is rotated on every authentication.
+	ServiceAccountClientID string
+
+	// BasicAuthSecret is the secret mounted to /etc/kubernetes/vcloud/basic-auth, to which rotated refresh tokens of
+	// service accounts are written
+	BasicAuthSecret SecretReference `yaml:"basicAuthSecret,omitempty"`
+}
+
+const (
+	// DefaultBasicAuthSecretName is the name of the secret with the credentials of the VCD user by default
+	DefaultBasicAuthSecretName = "vcloud-basic-auth"
+	// DefaultBasicAuthSecretNamespace is the namespace of the secret with the credentials of the VCD user by default
+	DefaultBasicAuthSecretNamespace = "kube-system"
+)
+
+// SecretReference :
+type SecretReference struct {
+	Namespace string `yaml:"namespace,omitempty"`
+	Name      string `yaml:"name,omitempty"`
 }
 
+const (
+	CAReferenceKindSecret    = "Secret"
+	CAReferenceKindConfigMap = "ConfigMap"
+
+	// DefaultCAReferenceNamespace is the namespace of the CA bundle Secret or ConfigMap by default
+	DefaultCAReferenceNamespace = "kube-system"
+	// DefaultCAReferenceKey is the key of the CA bundle in the Secret or ConfigMap by default
+	DefaultCAReferenceKey = "ca.crt"
+)
+
+// CAReference :
+type CAReference struct {
+	// Kind is CAReferenceKindSecret or CAReferenceKindConfigMap
+	Kind      string `yaml:"kind"`
+	Namespace string `yaml:"namespace,omitempty"`
+	Name      string `yaml:"name"`
+	Key       string `yaml:"key,omitempty"`
+}
+
+// TLSConfig :
+type TLSConfig struct {
+	// Insecure skips the verification of the certificate of the VCD site. It is true if unset and no CA bundle is
+	// configured, for compatibility with earlier releases.
+	Insecure *bool `yaml:"insecure,omitempty"`
+	// CAFile is the path of a PEM bundle of the CAs that are trusted for the VCD site
+	CAFile string `yaml:"caFile,omitempty"`
+	// CARef is a Secret or ConfigMap with a PEM bundle of the CAs that are trusted for the VCD site
+	CARef *CAReference `yaml:"caRef,omitempty"`
+}
+
+// TransportConfig : zero values use the defaults of the VCD clients
+type TransportConfig struct {
+	// ProxyURL is the http, https or socks5 proxy through which the VCD site is reached. The HTTPS_PROXY and NO_PROXY
+	// environment variables are used if empty.
+	ProxyURL string `yaml:"proxyURL,omitempty"`
+	// NoProxy are the hosts, domains, IPs and CIDRs that are reached without the proxy of ProxyURL
+	NoProxy []string `yaml:"noProxy,omitempty"`
+	// DialTimeoutSeconds is the timeout to establish a connection
+	DialTimeoutSeconds int `yaml:"dialTimeoutSeconds,omitempty"`
+	// TLSHandshakeTimeoutSeconds is the timeout of the TLS handshake
+	TLSHandshakeTimeoutSeconds int `yaml:"tlsHandshakeTimeoutSeconds,omitempty"`
+	// ResponseTimeoutSeconds is the timeout to receive the response headers of a request; there is none if unset
+	ResponseTimeoutSeconds int `yaml:"responseTimeoutSeconds,omitempty"`
+	// MaxIdleConns is the number of idle connections to the VCD site that are kept for reuse
+	MaxIdleConns int `yaml:"maxIdleConns,omitempty"`
+}
+
+// RateLimitConfig : zero values use the defaults of the VCD clients
+type RateLimitConfig struct {
+	// QPS is the sustained rate of requests per second to the VCD site
+	QPS float32 `yaml:"qps,omitempty"`
+	// Burst is the number of requests that can be made at once above QPS
+	Burst int `yaml:"burst,omitempty"`
+	// MaxConcurrentGatewayOperations is the number of load balancer, NAT and routing operations that modify an edge
+	// gateway at the same time
+	MaxConcurrentGatewayOperations int `yaml:"maxConcurrentGatewayOperations,omitempty"`
+}
+
+// BasicAuthSecretDir is the directory at which the secret with the credentials of the VCD user is mounted
+const BasicAuthSecretDir = "/etc/kubernetes/vcloud/basic-auth"
+
+const (
+	// RegionSourceOrg uses the org name as the region
+	RegionSourceOrg = "org"
+	// RegionSourceSite uses the host name of the VCD site as the region
+	RegionSourceSite = "site"
+)
+
 // Ports :
 type Ports struct {
 	HTTP  int32 `yaml:"http" default:"80"`
@@ -48,22 +150,149 @@ type OneArm struct {
 	EndIP   string `yaml:"endIP"`
 }
 
+// RouteAdvertisementConfig :
+type RouteAdvertisementConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Subnets containing VIPs are advertised as a whole; defaults to vipSubnet. Other VIPs are advertised as host prefixes.
+	Subnets []string `yaml:"subnets,omitempty"`
+	// BGPPrefixList is the name of the edge gateway prefix list that is kept in sync with the advertised prefixes
+	BGPPrefixList string `yaml:"bgpPrefixList,omitempty"`
+}
+
+const (
+	// LBModeAvi uses NSX Advanced Load Balancer (Avi) virtual services and pools for LoadBalancer services
+	LBModeAvi = "avi"
+	// LBModeNATOnly uses only DNAT rules that forward VIP:port to the NodePort of one of the nodes
+	LBModeNATOnly = "natOnly"
+)
+
 // LBConfig :
 type LBConfig struct {
+	// Mode is one of LBModeAvi (default) or LBModeNATOnly
+	Mode                         string  `yaml:"mode,omitempty"`
 	OneArm                       *OneArm `yaml:"oneArm,omitempty"`
 	Ports                        Ports   `yaml:"ports"`
 	CertificateAlias             string  `yaml:"certAlias"`
 	VDCNetwork                   string  `yaml:"network"`
 	VIPSubnet                    string  `yaml:"vipSubnet"`
 	EnableVirtualServiceSharedIP bool    `yaml:"enableVirtualServiceSharedIP"`
+	// RouteAdvertisement advertises the prefixes of VIPs through the edge gateway for routed org networks
+	RouteAdvertisement *RouteAdvertisementConfig `yaml:"routeAdvertisement,omitempty"`
+}
+
+const (
+	NodeLabelOVDC            = "ovdc"
+	NodeLabelPlacementPolicy = "placementPolicy"
+	NodeLabelSizingPolicy    = "sizingPolicy"
+	NodeLabelStorageProfile  = "storageProfile"
+	NodeLabelVAppName        = "vAppName"
+)
+
+// NodeLabelsConfig :
+type NodeLabelsConfig struct {
+	// Labels are the VCD facts about node VMs that are published as node labels; see the NodeLabel constants
+	Labels []string `yaml:"labels,omitempty"`
+	// MetadataKeys are the VCD metadata keys of node VMs that are published as node labels
+	MetadataKeys []string `yaml:"metadataKeys,omitempty"`
+}
+
+const (
+	NodeAddressTypeInternalIP = "InternalIP"
+	NodeAddressTypeExternalIP = "ExternalIP"
+	NodeAddressTypeSkip       = "Skip"
+)
+
+// NodeAddressesConfig :
+type NodeAddressesConfig struct {
+	// Networks maps VCD network names to the type of the addresses of the NICs on them; see the NodeAddressType
+	// constants. The primary NIC of a VM on an unmapped network is an InternalIP, and every NIC is an ExternalIP.
+	Networks map[string]string `yaml:"networks,omitempty"`
+	// InternalDNSSuffix is appended to the guest hostname to form the InternalDNS address of nodes
+	InternalDNSSuffix string `yaml:"internalDNSSuffix,omitempty"`
+	// ExternalDNSSuffix is appended to the guest hostname to form the ExternalDNS address of nodes
+	ExternalDNSSuffix string `yaml:"externalDNSSuffix,omitempty"`
+	// ExternalIPFromNAT adds the external address of one-to-one SNAT/DNAT rules of the edge gateway as ExternalIP
+	ExternalIPFromNAT bool `yaml:"externalIPFromNAT,omitempty"`
+}
+
+// RoutesConfig :
+type RoutesConfig struct {
+	// Enabled programs static routes for the pod CIDRs of nodes on the edge gateway of the loadbalancer network
+	Enabled bool `yaml:"enabled"`
+}
+
+const (
+	ZoneSourceFile     = "file"
+	ZoneSourceVDCGroup = "vdcGroup"
+
+	// DefaultZoneReloadIntervalSeconds is the interval at which zones are reloaded by default
+	DefaultZoneReloadIntervalSeconds = 60
+)
+
+// ZonesConfig :
+type ZonesConfig struct {
+	// Source is file to read the zones of a zone-enabled cluster from the zones configmap, or vdcGroup to use the
+	// VDCs of a VDC group as zones
+	Source string `yaml:"source,omitempty"`
+	// VDCGroup is the name of the VDC group whose VDCs are the zones
+	VDCGroup string `yaml:"vdcGroup,omitempty"`
+	// ZoneNames maps the names of VDCs of the VDC group to zone names; unmapped VDCs are their own zone
+	ZoneNames map[string]string `yaml:"zoneNames,omitempty"`
+	// ReloadIntervalSeconds is the interval at which zones are reloaded from their source
+	ReloadIntervalSeconds int `yaml:"reloadIntervalSeconds,omitempty"`
+}
+
+const (
+	VMDiscoveryModeVAppName = "vAppName"
+	VMDiscoveryModeMetadata = "metadata"
+)
+
+// VMDiscoveryConfig :
+type VMDiscoveryConfig struct {
+	// Mode is vAppName to find cluster VMs by their vApp, or metadata to find them by a VCD metadata entry first
+	Mode string `yaml:"mode,omitempty"`
+	// MetadataKey is the key of the VCD metadata entry of cluster VMs
+	MetadataKey string `yaml:"metadataKey,omitempty"`
+	// MetadataValue is the value of the VCD metadata entry of cluster VMs; the cluster ID by default
+	MetadataValue string `yaml:"metadataValue,omitempty"`
+}
+
+// DefaultVMInventoryRefreshIntervalSeconds is the interval at which the VMs of the cluster are listed by default
+const DefaultVMInventoryRefreshIntervalSeconds = 30
+
+// VMInventoryConfig :
+type VMInventoryConfig struct {
+	// Enabled lists all VMs of the cluster vApps periodically so that node lookups are served from the inventory
+	Enabled bool `yaml:"enabled"`
+	// RefreshIntervalSeconds is the interval between two listings of the VMs of the cluster
+	RefreshIntervalSeconds int `yaml:"refreshIntervalSeconds,omitempty"`
+}
+
+// DefaultConfigReloadIntervalSeconds is the interval at which the cloud config and credentials are re-read by default
+const DefaultConfigReloadIntervalSeconds = 30
+
+// ConfigReloadConfig :
+type ConfigReloadConfig struct {
+	// Enabled re-reads the cloud config and the basic-auth secret periodically and re-authenticates with rotated
+	// credentials without a restart
+	Enabled bool `yaml:"enabled"`
+	// IntervalSeconds is the interval between two reads of the cloud config and the basic-auth secret
+	IntervalSeconds int `yaml:"intervalSeconds,omitempty"`
 }
 
 // CloudConfig contains the config that will be read from the secret
 type CloudConfig struct {
-	VCD       VCDConfig `yaml:"vcd"`
-	LB        LBConfig  `yaml:"loadbalancer"`
-	ClusterID string    `yaml:"clusterid"`
-	VAppName  string    `yaml:"vAppName"`
+	VCD           VCDConfig           `yaml:"vcd"`
+	LB            LBConfig            `yaml:"loadbalancer"`
+	Routes        RoutesConfig        `yaml:"routes,omitempty"`
+	NodeLabels    NodeLabelsConfig    `yaml:"nodeLabels,omitempty"`
+	NodeAddresses NodeAddressesConfig `yaml:"nodeAddresses,omitempty"`
+	VMDiscovery   VMDiscoveryConfig   `yaml:"vmDiscovery,omitempty"`
+	Zones         ZonesConfig         `yaml:"zones,omitempty"`
+	VMInventory   VMInventoryConfig   `yaml:"vmInventory,omitempty"`
+	ConfigReload  ConfigReloadConfig  `yaml:"configReload,omitempty"`
+	ClusterID     string              `yaml:"clusterid"`
+	VAppName      string              `yaml:"vAppName"`
 }
 
 // ParseCloudConfig : parses config and env to fill in the CloudConfig struct
@@ -71,8 +300,15 @@ func ParseCloudConfig(configReader io.Reader) (*CloudConfig, error) {
 	var err error
 	config := &CloudConfig{
 		LB: LBConfig{
+			Mode:                         LBModeAvi,
 			EnableVirtualServiceSharedIP: false,
 		},
+		VMInventory: VMInventoryConfig{
+			Enabled: true,
+		},
+		ConfigReload: ConfigReloadConfig{
+			Enabled: true,
+		},
 	}
 
 	decoder := yaml.NewDecoder(configReader)
@@ -82,6 +318,38 @@ func ParseCloudConfig(configReader io.Reader) (*CloudConfig, error) {
 		return nil, fmt.Errorf("unable to decode yaml file: [%v]", err)
 	}
 	config.VCD.Host = strings.TrimRight(config.VCD.Host, "/")
+	if config.VCD.RegionSource == "" {
+		config.VCD.RegionSource = RegionSourceOrg
+	}
+	if config.Zones.Source == "" {
+		config.Zones.Source = ZoneSourceFile
+	}
+	if config.Zones.ReloadIntervalSeconds == 0 {
+		config.Zones.ReloadIntervalSeconds = DefaultZoneReloadIntervalSeconds
+	}
+	if config.VMDiscovery.Mode == "" {
+		config.VMDiscovery.Mode = VMDiscoveryModeVAppName
+	}
+	if config.VCD.BasicAuthSecret.Name == "" {
+		config.VCD.BasicAuthSecret.Name = DefaultBasicAuthSecretName
+	}
+	if config.VCD.BasicAuthSecret.Namespace == "" {
+		config.VCD.BasicAuthSecret.Namespace = DefaultBasicAuthSecretNamespace
+	}
+	if config.VCD.TLS.CARef != nil {
+		if config.VCD.TLS.CARef.Namespace == "" {
+			config.VCD.TLS.CARef.Namespace = DefaultCAReferenceNamespace
+		}
+		if config.VCD.TLS.CARef.Key == "" {
+			config.VCD.TLS.CARef.Key = DefaultCAReferenceKey
+		}
+	}
+	if config.VMInventory.RefreshIntervalSeconds == 0 {
+		config.VMInventory.RefreshIntervalSeconds = DefaultVMInventoryRefreshIntervalSeconds
+	}
+	if config.ConfigReload.IntervalSeconds == 0 {
+		config.ConfigReload.IntervalSeconds = DefaultConfigReloadIntervalSeconds
+	}
 
 	if config.ClusterID == "" {
 		config.ClusterID = os.Getenv("CLUSTER_ID")
@@ -91,19 +359,58 @@ func ParseCloudConfig(configReader io.Reader) (*CloudConfig, error) {
 		config.LB.CertificateAlias = fmt.Sprintf("%s-cert", config.ClusterID)
 		klog.Infof("Using certAlias [%s] from env since config has an empty string", config.LB.CertificateAlias)
 	}
+	if config.VMDiscovery.Mode == VMDiscoveryModeMetadata && config.VMDiscovery.MetadataValue == "" {
+		config.VMDiscovery.MetadataValue = config.ClusterID
+		klog.Infof("Using ClusterID [%s] as VM discovery metadata value since config has an empty string",
+			config.ClusterID)
+	}
+	if config.LB.RouteAdvertisement != nil && len(config.LB.RouteAdvertisement.Subnets) == 0 &&
+		config.LB.VIPSubnet != "" {
+		config.LB.RouteAdvertisement.Subnets = []string{config.LB.VIPSubnet}
+		klog.Infof("Using vipSubnet [%s] for route advertisement since no subnets are specified", config.LB.VIPSubnet)
+	}
 
 	return config, nil
 }
 
+// GetRegion returns the configured region, or the one derived from the org or VCD site.
+func GetRegion(config *CloudConfig) (string, error) {
+	if config.VCD.Region != "" {
+		return config.VCD.Region, nil
+	}
+
+	switch config.VCD.RegionSource {
+	case RegionSourceSite:
+		hostURL, err := url.Parse(config.VCD.Host)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse VCD host [%s]: [%v]", config.VCD.Host, err)
+		}
+		if hostURL.Hostname() == "" {
+			return "", fmt.Errorf("unable to get site name from VCD host [%s]", config.VCD.Host)
+		}
+		return hostURL.Hostname(), nil
+	case RegionSourceOrg, "":
+		return config.VCD.Org, nil
+	default:
+		return "", fmt.Errorf("invalid region source [%s]", config.VCD.RegionSource)
+	}
+}
+
 func SetAuthorization(config *CloudConfig) error {
-	refreshToken, err := os.ReadFile("/etc/kubernetes/vcloud/basic-auth/refreshToken")
+	return SetAuthorizationFromDir(config, BasicAuthSecretDir)
+}
+
+// SetAuthorizationFromDir sets the credentials of config from the username, password, refreshToken and clientId files
+// of the basic-auth secret mounted at secretDir.
+func SetAuthorizationFromDir(config *CloudConfig, secretDir string) error {
+	refreshToken, err := os.ReadFile(filepath.Join(secretDir, "refreshToken"))
 	if err != nil {
 		klog.Infof("Unable to get refresh token: [%v]", err)
 	} else {
 		config.VCD.RefreshToken = strings.TrimSuffix(string(refreshToken), "\n")
 	}
 
-	username, err := os.ReadFile("/etc/kubernetes/vcloud/basic-auth/username")
+	username, err := os.ReadFile(filepath.Join(secretDir, "username"))
 	if err != nil {
 		klog.Infof("Unable to get username: [%v]", err)
 	} else {
@@ -119,13 +426,26 @@ func SetAuthorization(config *CloudConfig) error {
 		config.VCD.UserOrg = strings.TrimSuffix(config.VCD.Org, "\n")
 	}
 
-	secret, err := os.ReadFile("/etc/kubernetes/vcloud/basic-auth/password")
+	secret, err := os.ReadFile(filepath.Join(secretDir, "password"))
 	if err != nil {
 		klog.Infof("Unable to get password: [%v]", err)
 	} else {
 		config.VCD.Secret = strings.TrimSuffix(string(secret), "\n")
 	}
 
+	clientID, err := os.ReadFile(filepath.Join(secretDir, "clientId"))
+	if err == nil {
+		config.VCD.ServiceAccountClientID = strings.TrimSuffix(string(clientID), "\n")
+	}
+
+	if config.VCD.ServiceAccountClientID != "" {
+		if config.VCD.RefreshToken == "" {
+			return fmt.Errorf("unable to get refresh token of service account [%s] from secrets",
+				config.VCD.ServiceAccountClientID)
+		}
+		klog.Infof("Using service account [%s].", config.VCD.ServiceAccountClientID)
+		return nil
+	}
 	if config.VCD.RefreshToken != "" {
 		klog.Infof("Using non-empty refresh token.")
 		return nil
@@ -138,6 +458,43 @@ func SetAuthorization(config *CloudConfig) error {
 	return fmt.Errorf("unable to get valid set of credentials from secrets")
 }
 
+// IsInsecureTLS returns true if the certificate of the VCD site should not be verified. This is the case if it is
+// explicitly requested, or, for compatibility with earlier releases, if no CA bundle is configured.
+func IsInsecureTLS(config *CloudConfig) bool {
+	if config.VCD.TLS.Insecure != nil {
+		return *config.VCD.TLS.Insecure
+	}
+	return config.VCD.TLS.CAFile == "" && config.VCD.TLS.CARef == nil
+}
+
+// HasSameCredentials returns true if both configs authenticate as the same user with the same secret or token.
+func HasSameCredentials(config *CloudConfig, otherConfig *CloudConfig) bool {
+	return config.VCD.User == otherConfig.VCD.User && config.VCD.UserOrg == otherConfig.VCD.UserOrg &&
+		config.VCD.Secret == otherConfig.VCD.Secret && config.VCD.RefreshToken == otherConfig.VCD.RefreshToken &&
+		config.VCD.ServiceAccountClientID == otherConfig.VCD.ServiceAccountClientID
+}
+
+// HasSameConnectionSettings returns true if both configs connect to the VCD site with the same TLS, transport and
+// rate limit settings.
+func HasSameConnectionSettings(config *CloudConfig, otherConfig *CloudConfig) bool {
+	return reflect.DeepEqual(config.VCD.TLS, otherConfig.VCD.TLS) &&
+		reflect.DeepEqual(config.VCD.Transport, otherConfig.VCD.Transport) &&
+		reflect.DeepEqual(config.VCD.RateLimit, otherConfig.VCD.RateLimit)
+}
+
+// IsEqualExceptAuthentication returns true if both configs differ at most in their credentials and in the TLS,
+// transport and rate limit settings to the VCD site.
+func IsEqualExceptAuthentication(config *CloudConfig, otherConfig *CloudConfig) bool {
+	configCopy, otherConfigCopy := *config, *otherConfig
+	for _, vcdConfig := range []*VCDConfig{&configCopy.VCD, &otherConfigCopy.VCD} {
+		vcdConfig.User, vcdConfig.UserOrg, vcdConfig.Secret, vcdConfig.RefreshToken = "", "", "", ""
+		vcdConfig.ServiceAccountClientID = ""
+		vcdConfig.TLS, vcdConfig.Transport = TLSConfig{}, TransportConfig{}
+		vcdConfig.RateLimit = RateLimitConfig{}
+	}
+	return reflect.DeepEqual(configCopy, otherConfigCopy)
+}
+
 func ValidateCloudConfig(config *CloudConfig) error {
 	// TODO: needs more validation
 	if config == nil {
@@ -150,11 +507,119 @@ func ValidateCloudConfig(config *CloudConfig) error {
 	if config.LB.VDCNetwork == "" {
 		return fmt.Errorf("need a valid ovdc network name")
 	}
+	if config.VCD.RegionSource != RegionSourceOrg && config.VCD.RegionSource != RegionSourceSite {
+		return fmt.Errorf("invalid region source [%s]; expected one of [%s, %s]",
+			config.VCD.RegionSource, RegionSourceOrg, RegionSourceSite)
+	}
 	if config.VAppName == "" {
 		return fmt.Errorf("need a valid vApp name")
 	}
-	if !config.LB.EnableVirtualServiceSharedIP && config.LB.OneArm == nil {
-		return fmt.Errorf("if not using virtual service shared IP feature, OneArm should be enabled")
+	if config.VCD.TLS.CAFile != "" && config.VCD.TLS.CARef != nil {
+		return fmt.Errorf("only one of CA file and CA reference can be set")
+	}
+	if config.VCD.TLS.Insecure != nil && *config.VCD.TLS.Insecure &&
+		(config.VCD.TLS.CAFile != "" || config.VCD.TLS.CARef != nil) {
+		return fmt.Errorf("insecure TLS cannot be combined with a CA bundle")
+	}
+	if proxyURL := config.VCD.Transport.ProxyURL; proxyURL != "" {
+		parsedProxyURL, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url [%s]: [%v]", proxyURL, err)
+		}
+		switch parsedProxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("invalid scheme [%s] of proxy url [%s]; expected one of [http, https, socks5]",
+				parsedProxyURL.Scheme, proxyURL)
+		}
+		if parsedProxyURL.Host == "" {
+			return fmt.Errorf("need a valid host in proxy url [%s]", proxyURL)
+		}
+	}
+	if transport := config.VCD.Transport; transport.DialTimeoutSeconds < 0 ||
+		transport.TLSHandshakeTimeoutSeconds < 0 || transport.ResponseTimeoutSeconds < 0 || transport.MaxIdleConns < 0 {
+		return fmt.Errorf("invalid transport config [%+v]; expected positive timeouts and connection limits",
+			transport)
+	}
+	if rateLimit := config.VCD.RateLimit; rateLimit.QPS < 0 || rateLimit.Burst < 0 ||
+		rateLimit.MaxConcurrentGatewayOperations < 0 {
+		return fmt.Errorf("invalid rate limit config [%+v]; expected positive rates and limits", rateLimit)
+	}
+	if caRef := config.VCD.TLS.CARef; caRef != nil {
+		if caRef.Kind != CAReferenceKindSecret && caRef.Kind != CAReferenceKindConfigMap {
+			return fmt.Errorf("invalid CA reference kind [%s]; expected one of [%s, %s]", caRef.Kind,
+				CAReferenceKindSecret, CAReferenceKindConfigMap)
+		}
+		if caRef.Name == "" {
+			return fmt.Errorf("need a valid name of the CA reference")
+		}
+	}
+	switch config.LB.Mode {
+	case LBModeAvi:
+		if !config.LB.EnableVirtualServiceSharedIP && config.LB.OneArm == nil {
+			return fmt.Errorf("if not using virtual service shared IP feature, OneArm should be enabled")
+		}
+	case LBModeNATOnly:
+		// DNAT rules forward directly to the nodes; OneArm and shared IP settings are not used
+	default:
+		return fmt.Errorf("invalid loadbalancer mode [%s]; expected one of [%s, %s]",
+			config.LB.Mode, LBModeAvi, LBModeNATOnly)
+	}
+	for _, label := range config.NodeLabels.Labels {
+		switch label {
+		case NodeLabelOVDC, NodeLabelPlacementPolicy, NodeLabelSizingPolicy, NodeLabelStorageProfile, NodeLabelVAppName:
+		default:
+			return fmt.Errorf("invalid node label [%s]; expected one of [%s, %s, %s, %s, %s]", label, NodeLabelOVDC,
+				NodeLabelPlacementPolicy, NodeLabelSizingPolicy, NodeLabelStorageProfile, NodeLabelVAppName)
+		}
+	}
+	for network, addressType := range config.NodeAddresses.Networks {
+		switch addressType {
+		case NodeAddressTypeInternalIP, NodeAddressTypeExternalIP, NodeAddressTypeSkip:
+		default:
+			return fmt.Errorf("invalid address type [%s] of network [%s]; expected one of [%s, %s, %s]", addressType,
+				network, NodeAddressTypeInternalIP, NodeAddressTypeExternalIP, NodeAddressTypeSkip)
+		}
+	}
+	switch config.Zones.Source {
+	case ZoneSourceFile:
+	case ZoneSourceVDCGroup:
+		if config.Zones.VDCGroup == "" {
+			return fmt.Errorf("need a valid VDC group for zone source [%s]", ZoneSourceVDCGroup)
+		}
+	default:
+		return fmt.Errorf("invalid zone source [%s]; expected one of [%s, %s]", config.Zones.Source,
+			ZoneSourceFile, ZoneSourceVDCGroup)
+	}
+	if config.Zones.ReloadIntervalSeconds < 0 {
+		return fmt.Errorf("invalid zone reload interval [%d]; expected a positive number of seconds",
+			config.Zones.ReloadIntervalSeconds)
+	}
+	switch config.VMDiscovery.Mode {
+	case VMDiscoveryModeVAppName:
+	case VMDiscoveryModeMetadata:
+		if config.VMDiscovery.MetadataKey == "" || config.VMDiscovery.MetadataValue == "" {
+			return fmt.Errorf("need a valid metadata key and value for VM discovery mode [%s]",
+				VMDiscoveryModeMetadata)
+		}
+	default:
+		return fmt.Errorf("invalid VM discovery mode [%s]; expected one of [%s, %s]", config.VMDiscovery.Mode,
+			VMDiscoveryModeVAppName, VMDiscoveryModeMetadata)
+	}
+	if config.VMInventory.RefreshIntervalSeconds < 0 {
+		return fmt.Errorf("invalid VM inventory refresh interval [%d]; expected a positive number of seconds",
+			config.VMInventory.RefreshIntervalSeconds)
+	}
+	if config.ConfigReload.IntervalSeconds < 0 {
+		return fmt.Errorf("invalid config reload interval [%d]; expected a positive number of seconds",
+			config.ConfigReload.IntervalSeconds)
+	}
+	if config.LB.RouteAdvertisement != nil {
+		for _, subnet := range config.LB.RouteAdvertisement.Subnets {
+			if _, _, err := net.ParseCIDR(subnet); err != nil {
+				return fmt.Errorf("invalid route advertisement subnet [%s]: [%v]", subnet, err)
+			}
+		}
 	}
 
 	return nil
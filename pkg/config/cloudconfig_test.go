@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -39,3 +40,256 @@ func TestCloudConfig(t *testing.T) {
 	_, err = ParseCloudConfig(configReader)
 	assert.NoError(t, err, "Unable to parse config file")
 }
+
+func TestGetRegion(t *testing.T) {
+
+	type TestCase struct {
+		VCD            VCDConfig
+		ExpectedRegion string
+		ExpectError    bool
+	}
+
+	testCaseList := []TestCase{
+		{
+			VCD:            VCDConfig{Host: "https://vcd.example.com", Org: "org1", Region: "region1", RegionSource: RegionSourceSite},
+			ExpectedRegion: "region1",
+		},
+		{
+			VCD:            VCDConfig{Host: "https://vcd.example.com", Org: "org1", RegionSource: RegionSourceOrg},
+			ExpectedRegion: "org1",
+		},
+		{
+			VCD:            VCDConfig{Host: "https://vcd.example.com:443", Org: "org1", RegionSource: RegionSourceSite},
+			ExpectedRegion: "vcd.example.com",
+		},
+		{
+			VCD:         VCDConfig{Host: "https://vcd.example.com", Org: "org1", RegionSource: "zone"},
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		region, err := GetRegion(&CloudConfig{VCD: testCase.VCD})
+		if testCase.ExpectError {
+			assert.Error(t, err, "invalid region source [%s] should fail", testCase.VCD.RegionSource)
+			continue
+		}
+		assert.NoError(t, err, "region should be obtained for [%#v]", testCase.VCD)
+		assert.Equal(t, testCase.ExpectedRegion, region, "unexpected region for [%#v]", testCase.VCD)
+	}
+}
+
+func TestVMDiscoveryConfig(t *testing.T) {
+
+	configYaml := `
+vcd:
+  host: "https://vcd.example.com"
+  org: "org1"
+loadbalancer:
+  network: "network1"
+  enableVirtualServiceSharedIP: true
+vmDiscovery:
+  mode: metadata
+  metadataKey: "cluster-id"
+clusterid: "cluster1"
+vAppName: "vapp1"
+`
+	config, err := ParseCloudConfig(strings.NewReader(configYaml))
+	assert.NoError(t, err, "unable to parse config")
+	assert.Equal(t, "cluster1", config.VMDiscovery.MetadataValue, "cluster ID should be the default metadata value")
+	assert.NoError(t, ValidateCloudConfig(config), "metadata discovery config should be valid")
+
+	config.VMDiscovery.MetadataKey = ""
+	assert.Error(t, ValidateCloudConfig(config), "metadata discovery without key should be invalid")
+
+	config, err = ParseCloudConfig(strings.NewReader(strings.Replace(configYaml, "mode: metadata",
+		"mode: vAppName", 1)))
+	assert.NoError(t, err, "unable to parse config")
+	assert.Equal(t, "", config.VMDiscovery.MetadataValue, "metadata value should not be defaulted in vApp mode")
+	assert.NoError(t, ValidateCloudConfig(config), "vApp discovery config should be valid")
+}
+
+func TestSetAuthorizationFromDir(t *testing.T) {
+
+	configYaml := `
+vcd:
+  host: "https://vcd.example.com"
+  org: "org1"
+loadbalancer:
+  network: "network1"
+  enableVirtualServiceSharedIP: true
+clusterid: "cluster1"
+vAppName: "vapp1"
+`
+	config, err := ParseCloudConfig(strings.NewReader(configYaml))
+	assert.NoError(t, err, "unable to parse config")
+	assert.Error(t, SetAuthorizationFromDir(config, t.TempDir()), "config without credentials should be invalid")
+
+	secretDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(secretDir, "username"), []byte("org2/user1\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(secretDir, "password"), []byte("password1\n"), 0600))
+	assert.NoError(t, SetAuthorizationFromDir(config, secretDir), "unable to set credentials from secret")
+	assert.Equal(t, "org2", config.VCD.UserOrg, "user org should be the prefix of the username")
+	assert.Equal(t, "user1", config.VCD.User, "user should be the username without org")
+	assert.Equal(t, "password1", config.VCD.Secret, "secret should be the password without newline")
+
+	rotatedConfig, err := ParseCloudConfig(strings.NewReader(configYaml))
+	assert.NoError(t, err, "unable to parse config")
+	assert.NoError(t, os.WriteFile(filepath.Join(secretDir, "password"), []byte("password2\n"), 0600))
+	assert.NoError(t, SetAuthorizationFromDir(rotatedConfig, secretDir), "unable to set credentials from secret")
+	assert.False(t, HasSameCredentials(config, rotatedConfig), "rotated password should be detected")
+	assert.True(t, IsEqualExceptAuthentication(config, rotatedConfig), "configs should differ only in credentials")
+
+	rotatedConfig.VCD.TLS.CAFile = "/etc/ssl/certs/vcd-ca.pem"
+	assert.True(t, IsEqualExceptAuthentication(config, rotatedConfig), "changed CA file should be ignored")
+
+	rotatedConfig.LB.VDCNetwork = "network2"
+	assert.False(t, IsEqualExceptAuthentication(config, rotatedConfig), "changed network should be detected")
+}
+
+func TestServiceAccountAuthorization(t *testing.T) {
+
+	configYaml := `
+vcd:
+  host: "https://vcd.example.com"
+  org: "org1"
+loadbalancer:
+  network: "network1"
+  enableVirtualServiceSharedIP: true
+clusterid: "cluster1"
+vAppName: "vapp1"
+`
+	config, err := ParseCloudConfig(strings.NewReader(configYaml))
+	assert.NoError(t, err, "unable to parse config")
+	assert.Equal(t, DefaultBasicAuthSecretName, config.VCD.BasicAuthSecret.Name, "secret name should be defaulted")
+	assert.Equal(t, DefaultBasicAuthSecretNamespace, config.VCD.BasicAuthSecret.Namespace,
+		"secret namespace should be defaulted")
+
+	secretDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(secretDir, "clientId"), []byte("urn:vcloud:serviceAccount:1\n"), 0600))
+	assert.Error(t, SetAuthorizationFromDir(config, secretDir), "service account without refresh token should be invalid")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(secretDir, "refreshToken"), []byte("token1\n"), 0600))
+	assert.NoError(t, SetAuthorizationFromDir(config, secretDir), "unable to set credentials from secret")
+	assert.Equal(t, "urn:vcloud:serviceAccount:1", config.VCD.ServiceAccountClientID, "client ID should be read")
+	assert.Equal(t, "token1", config.VCD.RefreshToken, "refresh token should be read")
+	assert.Equal(t, "org1", config.VCD.UserOrg, "service account should be in the cluster org by default")
+
+	rotatedConfig, err := ParseCloudConfig(strings.NewReader(configYaml))
+	assert.NoError(t, err, "unable to parse config")
+	assert.NoError(t, os.WriteFile(filepath.Join(secretDir, "refreshToken"), []byte("token2\n"), 0600))
+	assert.NoError(t, SetAuthorizationFromDir(rotatedConfig, secretDir), "unable to set credentials from secret")
+	assert.False(t, HasSameCredentials(config, rotatedConfig), "rotated refresh token should be detected")
+	assert.True(t, IsEqualExceptAuthentication(config, rotatedConfig), "configs should differ only in credentials")
+}
+
+func TestTLSConfig(t *testing.T) {
+
+	configYaml := `
+vcd:
+  host: "https://vcd.example.com"
+  org: "org1"
+  tls:
+    caRef:
+      kind: ConfigMap
+      name: vcd-ca
+loadbalancer:
+  network: "network1"
+  enableVirtualServiceSharedIP: true
+clusterid: "cluster1"
+vAppName: "vapp1"
+`
+	config, err := ParseCloudConfig(strings.NewReader(configYaml))
+	assert.NoError(t, err, "unable to parse config")
+	assert.NoError(t, ValidateCloudConfig(config), "CA reference config should be valid")
+	assert.Equal(t, DefaultCAReferenceNamespace, config.VCD.TLS.CARef.Namespace, "namespace should be defaulted")
+	assert.Equal(t, DefaultCAReferenceKey, config.VCD.TLS.CARef.Key, "key should be defaulted")
+	assert.False(t, IsInsecureTLS(config), "certificate should be verified with a CA bundle")
+
+	insecure := true
+	config.VCD.TLS.Insecure = &insecure
+	assert.Error(t, ValidateCloudConfig(config), "insecure TLS with a CA bundle should be invalid")
+
+	config.VCD.TLS.Insecure = nil
+	config.VCD.TLS.CAFile = "/etc/ssl/certs/vcd-ca.pem"
+	assert.Error(t, ValidateCloudConfig(config), "CA file with a CA reference should be invalid")
+
+	config.VCD.TLS.CARef.Kind = "Pod"
+	config.VCD.TLS.CAFile = ""
+	assert.Error(t, ValidateCloudConfig(config), "CA reference of unknown kind should be invalid")
+
+	config.VCD.TLS = TLSConfig{}
+	assert.True(t, IsInsecureTLS(config), "certificate should not be verified without a CA bundle by default")
+
+	secure := false
+	config.VCD.TLS.Insecure = &secure
+	assert.False(t, IsInsecureTLS(config), "certificate should be verified with the system CAs if requested")
+}
+
+func TestTransportConfig(t *testing.T) {
+
+	configYaml := `
+vcd:
+  host: "https://vcd.example.com"
+  org: "org1"
+  transport:
+    proxyURL: "http://proxy.example.com:3128"
+    noProxy:
+    - internal.example.com
+    responseTimeoutSeconds: 60
+loadbalancer:
+  network: "network1"
+  enableVirtualServiceSharedIP: true
+clusterid: "cluster1"
+vAppName: "vapp1"
+`
+	config, err := ParseCloudConfig(strings.NewReader(configYaml))
+	assert.NoError(t, err, "unable to parse config")
+	assert.NoError(t, ValidateCloudConfig(config), "transport config should be valid")
+	assert.Equal(t, []string{"internal.example.com"}, config.VCD.Transport.NoProxy, "no-proxy list should be parsed")
+
+	otherConfig, err := ParseCloudConfig(strings.NewReader(configYaml))
+	assert.NoError(t, err, "unable to parse config")
+	otherConfig.VCD.Transport.MaxIdleConns = 20
+	assert.False(t, HasSameConnectionSettings(config, otherConfig), "changed connection limit should be detected")
+	assert.True(t, IsEqualExceptAuthentication(config, otherConfig), "changed transport should be ignored")
+
+	config.VCD.Transport.ProxyURL = "ftp://proxy.example.com"
+	assert.Error(t, ValidateCloudConfig(config), "proxy with unsupported scheme should be invalid")
+
+	config.VCD.Transport.ProxyURL = ""
+	config.VCD.Transport.DialTimeoutSeconds = -1
+	assert.Error(t, ValidateCloudConfig(config), "negative timeout should be invalid")
+}
+
+func TestRateLimitConfig(t *testing.T) {
+
+	configYaml := `
+vcd:
+  host: "https://vcd.example.com"
+  org: "org1"
+  rateLimit:
+    qps: 5
+    burst: 10
+    maxConcurrentGatewayOperations: 2
+loadbalancer:
+  network: "network1"
+  enableVirtualServiceSharedIP: true
+clusterid: "cluster1"
+vAppName: "vapp1"
+`
+	config, err := ParseCloudConfig(strings.NewReader(configYaml))
+	assert.NoError(t, err, "unable to parse config")
+	assert.NoError(t, ValidateCloudConfig(config), "rate limit config should be valid")
+	assert.Equal(t, float32(5), config.VCD.RateLimit.QPS, "QPS should be parsed")
+	assert.Equal(t, 2, config.VCD.RateLimit.MaxConcurrentGatewayOperations, "gateway operations should be parsed")
+
+	otherConfig, err := ParseCloudConfig(strings.NewReader(configYaml))
+	assert.NoError(t, err, "unable to parse config")
+	otherConfig.VCD.RateLimit.Burst = 20
+	assert.False(t, HasSameConnectionSettings(config, otherConfig), "changed burst should be detected")
+	assert.True(t, IsEqualExceptAuthentication(config, otherConfig), "changed rate limit should be ignored")
+
+	config.VCD.RateLimit.QPS = -1
+	assert.Error(t, ValidateCloudConfig(config), "negative QPS should be invalid")
+}
@@ -15,13 +15,16 @@ import (
 
 const (
 	// Errors
-	CreateLoadbalancerError  = "CreateLoadbalancerError"
-	UpdateLoadbalancerError  = "UpdateLoadbalancerError"
-	DeleteLoadbalancerError  = "DeleteLoadbalancerError"
-	GetLoadbalancerError     = "GetLoadbalancerError"
-	CPIStatusUpgradeRdeError = "CPIStatusUpgradeRdeError"
-	RemoveVIPFromRdeError    = "RemoveVirtualIPFromRdeError"
-	AddVIPToRdeError         = "AddVirtualIPToRdeError"
+	CreateLoadbalancerError   = "CreateLoadbalancerError"
+	UpdateLoadbalancerError   = "UpdateLoadbalancerError"
+	DeleteLoadbalancerError   = "DeleteLoadbalancerError"
+	GetLoadbalancerError      = "GetLoadbalancerError"
+	CPIStatusUpgradeRdeError  = "CPIStatusUpgradeRdeError"
+	RemoveVIPFromRdeError     = "RemoveVirtualIPFromRdeError"
+	AddVIPToRdeError          = "AddVirtualIPToRdeError"
+	RouteAdvertisementError   = "RouteAdvertisementError"
+	ClientAuthenticationError = "ClientAuthenticationError"
+	LoadbalancerTaskTimeout   = "LoadbalancerTaskTimeout"
 
 	// Events
 	ClientAuthenticated  = "ClientAuthenticated"
@@ -49,14 +52,15 @@ func (cpiRDEManager *CPIRDEManager) GetRDEVirtualIps(ctx context.Context) ([]str
 	}
 
 	client := cpiRDEManager.RDEManager.Client
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
 		return nil, "", nil, fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
 	}
 	if clusterOrg == nil || clusterOrg.Org == nil {
 		return nil, "", nil, fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
-	defEnt, _, etag, err := client.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, cpiRDEManager.RDEManager.ClusterID,
+	defEnt, _, etag, err := session.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, cpiRDEManager.RDEManager.ClusterID,
 		clusterOrg.Org.ID, nil)
 	if err != nil {
 		return nil, "", nil, fmt.Errorf("error when getting defined entity: [%v]", err)
@@ -85,7 +89,8 @@ func (cpiRDEManager *CPIRDEManager) updateRDEVirtualIps(ctx context.Context, upd
 		return nil, fmt.Errorf("failed to locally edit RDE with ID [%s] with virtual IPs: [%v]", cpiRDEManager.RDEManager.ClusterID, err)
 	}
 	client := cpiRDEManager.RDEManager.Client
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
 	}
@@ -93,7 +98,7 @@ func (cpiRDEManager *CPIRDEManager) updateRDEVirtualIps(ctx context.Context, upd
 		return nil, fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
 	// can pass invokeHooks
-	_, httpResponse, err := client.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, *defEnt, etag, cpiRDEManager.RDEManager.ClusterID, clusterOrg.Org.ID, nil)
+	_, httpResponse, err := session.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, *defEnt, etag, cpiRDEManager.RDEManager.ClusterID, clusterOrg.Org.ID, nil)
 	if err != nil {
 		return httpResponse, fmt.Errorf("error when updating defined entity [%s]: [%v]", cpiRDEManager.RDEManager.ClusterID, err)
 	}
@@ -289,7 +294,8 @@ func UpgradeCPISectionInStatus(statusMap map[string]interface{}) (map[string]int
 func (cpiRDEManager *CPIRDEManager) UpgradeCPIStatusOfExistingRDE(ctx context.Context, rdeId string) error {
 	klog.Infof("upgrading CPI section in RDE")
 	client := cpiRDEManager.RDEManager.Client
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
 		return fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
 	}
@@ -297,7 +303,7 @@ func (cpiRDEManager *CPIRDEManager) UpgradeCPIStatusOfExistingRDE(ctx context.Co
 		return fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
 	for retries := 0; retries < vcdsdk.MaxRDEUpdateRetries; retries++ {
-		rde, resp, etag, err := client.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, clusterOrg.Org.ID, nil)
+		rde, resp, etag, err := session.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, clusterOrg.Org.ID, nil)
 		if resp != nil && resp.StatusCode != http.StatusOK {
 			var responseMessageBytes []byte
 			if gsErr, ok := err.(swaggerClient.GenericSwaggerError); ok {
@@ -332,7 +338,7 @@ func (cpiRDEManager *CPIRDEManager) UpgradeCPIStatusOfExistingRDE(ctx context.Co
 			return fmt.Errorf("failed to upgrade CPI section in RDE [%s]: [%v]", rdeId, err)
 		}
 
-		_, resp, err = client.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeId, clusterOrg.Org.ID, nil)
+		_, resp, err = session.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeId, clusterOrg.Org.ID, nil)
 		if resp != nil && resp.StatusCode != http.StatusOK {
 			var responseMessageBytes []byte
 			if gsErr, ok := err.(swaggerClient.GenericSwaggerError); ok {
@@ -367,7 +373,8 @@ func (cpiRDEManager *CPIRDEManager) AddVIPToVCDResourceSet(ctx context.Context,
 		return nil
 	}
 	client := cpiRDEManager.RDEManager.Client
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
 		return fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
 	}
@@ -375,7 +382,7 @@ func (cpiRDEManager *CPIRDEManager) AddVIPToVCDResourceSet(ctx context.Context,
 		return fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
 	for i := vcdsdk.MaxRDEUpdateRetries; i > 1; i-- {
-		rde, resp, etag, err := cpiRDEManager.RDEManager.Client.APIClient.DefinedEntityApi.GetDefinedEntity(
+		rde, resp, etag, err := session.APIClient.DefinedEntityApi.GetDefinedEntity(
 			ctx, cpiRDEManager.RDEManager.ClusterID, clusterOrg.Org.ID, nil)
 		if resp != nil && resp.StatusCode != http.StatusOK {
 			var responseMessageBytes []byte
@@ -446,7 +453,7 @@ func (cpiRDEManager *CPIRDEManager) AddVIPToVCDResourceSet(ctx context.Context,
 
 		rde.Entity["status"] = updatedStatusMap
 
-		_, resp, err = cpiRDEManager.RDEManager.Client.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag,
+		_, resp, err = session.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag,
 			cpiRDEManager.RDEManager.ClusterID, clusterOrg.Org.ID, nil)
 		if resp != nil {
 			if resp.StatusCode == http.StatusPreconditionFailed {
@@ -119,18 +119,18 @@ func cleanUpEntitiesWithName(t *testing.T, vcdClient *vcdsdk.Client, entityName
 	nameFilter := &swaggerClient.DefinedEntityApiGetDefinedEntitiesByEntityTypeOpts{
 		Filter: optional.NewString(fmt.Sprintf("name==%s", entityName)),
 	}
-	definedEntities, resp, err := vcdClient.APIClient.DefinedEntityApi.GetDefinedEntitiesByEntityType(context.TODO(),
+	definedEntities, resp, err := vcdClient.Session().APIClient.DefinedEntityApi.GetDefinedEntitiesByEntityType(context.TODO(),
 		vcdsdk.CAPVCDEntityTypeVendor, vcdsdk.CAPVCDEntityTypeNss, CAPVCDEntityTypeVersion, "", 1, 25, nameFilter)
 	assert.NoError(t, err, "expected no error executing list entities by entity type with name filter", "message", failMessage)
 	assert.NotNil(t, resp, "list entity by entity type response should not be nil", "message", failMessage)
 	assert.Equal(t, http.StatusOK, resp.StatusCode, "invalid response for list entity by entity type", "message", failMessage)
 	for _, e := range definedEntities.Values {
 		// resolve before delete - may fail with resolution errors.
-		entityState, _, _ := vcdClient.APIClient.DefinedEntityApi.ResolveDefinedEntity(context.TODO(), e.Id, "")
+		entityState, _, _ := vcdClient.Session().APIClient.DefinedEntityApi.ResolveDefinedEntity(context.TODO(), e.Id, "")
 		if entityState.State != "RESOLVED" {
 			fmt.Println("entity resolution failed for RDE ", entityState.Id, " with message: ", entityState.Message)
 		}
-		resp, err := vcdClient.APIClient.DefinedEntityApi.DeleteDefinedEntity(context.TODO(), e.Id, "", nil)
+		resp, err := vcdClient.Session().APIClient.DefinedEntityApi.DeleteDefinedEntity(context.TODO(), e.Id, "", nil)
 		assert.NoError(t, err, "expected no error cleaning up the defined entity", "message", failMessage)
 		assert.NotNil(t, resp, "did not expect a nil response while cleaning up defined entity by name", "message", failMessage)
 		assert.Equal(t, http.StatusNoContent, resp.StatusCode, "got unexpected response status code",
@@ -339,12 +339,12 @@ func TestAddVIPToVCDResourceSet(t *testing.T) {
 			EntityType: entityTypeID,
 			Entity:     tc.EntityCreated,
 		}
-		resp, err := vcdClient.APIClient.DefinedEntityApi.CreateDefinedEntity(context.TODO(), entityToBeCreated, entityTypeID, "", nil)
+		resp, err := vcdClient.Session().APIClient.DefinedEntityApi.CreateDefinedEntity(context.TODO(), entityToBeCreated, entityTypeID, "", nil)
 		assert.NoError(t, err, "expected no error creating defined entity", "test", tc.Message)
 		assert.NotNil(t, resp, "did not expect a nil response while creating defined entity", "test", tc.Message)
 		assert.Equal(t, http.StatusAccepted, resp.StatusCode, "status code is not as expected", "test", tc.Message)
 		taskURL := resp.Header.Get("Location")
-		task := govcd.NewTask(&vcdClient.VCDClient.Client)
+		task := govcd.NewTask(&vcdClient.Session().VCDClient.Client)
 		task.Task.HREF = taskURL
 		err = task.Refresh()
 		assert.NoError(t, err, "no error refreshing entity create task", "test", tc.Message)
@@ -358,7 +358,7 @@ func TestAddVIPToVCDResourceSet(t *testing.T) {
 		assert.NoError(t, err, "expected no error adding VIP to VCD resource set", "test", tc.Message)
 
 		// verify information in the RDE
-		definedEntity, resp, _, err := vcdClient.APIClient.DefinedEntityApi.GetDefinedEntity(context.TODO(), rdeID,
+		definedEntity, resp, _, err := vcdClient.Session().APIClient.DefinedEntityApi.GetDefinedEntity(context.TODO(), rdeID,
 			"", nil)
 		assert.NoError(t, err, "expected no error fetching defined entity after updating RDE with virtual service", "test", tc.Message)
 		assert.NotNil(t, definedEntity, "expected defined entity to be not nil", "test", tc.Message)
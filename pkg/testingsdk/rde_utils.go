@@ -106,14 +106,15 @@ func GetKubeconfigFromRDEId(ctx context.Context, client *vcdsdk.Client, clusterI
 }
 
 func getDecryptedRDE(ctx context.Context, client *vcdsdk.Client, rdeID, clusterName string) (*FullCAPVCDEntity, error) {
-	if client.VCDClient.Client.APIVCDMaxVersionIs(fmt.Sprintf("<%s", vcdsdk.VCloudApiVersion_37_2)) {
+	session := client.Session()
+	if session.VCDClient.Client.APIVCDMaxVersionIs(fmt.Sprintf("<%s", vcdsdk.VCloudApiVersion_37_2)) {
 		return nil, fmt.Errorf("skipping decrypt RDE for cluster [%s(%s)] as VCD API version is less than [%s]", clusterName, rdeID, vcdsdk.VCloudApiVersion_37_2)
 	}
-	if client.APIClient == nil {
+	if session.APIClient == nil {
 		return nil, fmt.Errorf("unable to decrypt RDE for cluster [%s(%s)] as API client for VCD API version [%s] is missing", clusterName, rdeID, vcdsdk.VCloudApiVersion_37_2)
 	}
 
-	resp, err := client.APIClient.DefinedInterfaceBehaviorsApi.InvokeDefinedEntityBehavior(ctx, rdeID, NoOpDecryptBehaviorID, nil)
+	resp, err := session.APIClient.DefinedInterfaceBehaviorsApi.InvokeDefinedEntityBehavior(ctx, rdeID, NoOpDecryptBehaviorID, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call decrypt behavior on RDE for cluster [%s(%s)]: [%v]", clusterName, rdeID, err)
 	}
@@ -132,7 +133,7 @@ func getDecryptedRDE(ctx context.Context, client *vcdsdk.Client, rdeID, clusterN
 	if decryptEntityTaskUrl == "" {
 		return nil, fmt.Errorf("unexpected response for decrypt behavior of RDE for cluster [%s(%s)] - task URL is empty", clusterName, rdeID)
 	}
-	task := govcd.NewTask(&client.VCDClient.Client)
+	task := govcd.NewTask(&session.VCDClient.Client)
 	task.Task.HREF = resp.Header.Get("Location")
 	err = task.WaitTaskCompletion()
 	if err != nil {
@@ -141,7 +142,7 @@ func getDecryptedRDE(ctx context.Context, client *vcdsdk.Client, rdeID, clusterN
 
 	// The following request to get the task needs to be explicitly made because GoVCD type for Task doesn't parse the Result field in the response.
 	taskResult := &TaskWithResult{}
-	_, err = client.VCDClient.Client.ExecuteRequest(decryptEntityTaskUrl, http.MethodGet, "", "error getting task: %s", nil, taskResult)
+	_, err = session.VCDClient.Client.ExecuteRequest(decryptEntityTaskUrl, http.MethodGet, "", "error getting task: %s", nil, taskResult)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read task response after decrypting RDE for cluster [%s(%s)]: [%v]", clusterName, rdeID, err)
 	}
@@ -22,14 +22,15 @@ func getTestVCDClient(params *VCDAuthParams) (*vcdsdk.Client, error) {
 }
 
 func getRdeById(ctx context.Context, client *vcdsdk.Client, rdeId string) (*swaggerClient.DefinedEntity, error) {
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	clusterOrg, err := session.VCDClient.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving org [%s]: [%v]", client.ClusterOrgName, err)
 	}
 	if clusterOrg == nil || clusterOrg.Org == nil {
 		return nil, fmt.Errorf("retrieved org is nil for [%s]", client.ClusterOrgName)
 	}
-	rde, _, _, err := client.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, clusterOrg.Org.ID, nil)
+	rde, _, _, err := session.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, clusterOrg.Org.ID, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving RDE [%s]: [%v]", rdeId, err)
 	}
@@ -37,9 +38,10 @@ func getRdeById(ctx context.Context, client *vcdsdk.Client, rdeId string) (*swag
 }
 
 func GetVappTemplates(client *vcdsdk.Client) ([]*types.QueryResultVappTemplateType, error) {
+	session := client.Session()
 	var allVappTemplates []*types.QueryResultVappTemplateType
 
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	clusterOrg, err := session.VCDClient.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving org [%s]: [%v]", client.ClusterOrgName, err)
 	}
@@ -0,0 +1,54 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// SanitizeLabelValue converts value into a valid Kubernetes label value. Characters that are not allowed are
+// replaced with '-', the value is truncated to the maximum length, and leading and trailing characters that are
+// not alphanumeric are trimmed. A non-empty result is also a valid name part of a label key.
+func SanitizeLabelValue(value string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+			r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, value)
+
+	if len(sanitized) > validation.LabelValueMaxLength {
+		sanitized = sanitized[:validation.LabelValueMaxLength]
+	}
+	return strings.Trim(sanitized, "-_.")
+}
+
+// GetManagedLabelsDiff returns the labels to set so that the labels of an object with one of managedPrefixes match
+// desiredLabels. Labels that are to be removed map to nil. It returns an empty map if nothing needs to change.
+func GetManagedLabelsDiff(existingLabels map[string]string, desiredLabels map[string]string,
+	managedPrefixes []string) map[string]interface{} {
+	labelsDiff := make(map[string]interface{})
+	for key, value := range desiredLabels {
+		if existingValue, ok := existingLabels[key]; !ok || existingValue != value {
+			labelsDiff[key] = value
+		}
+	}
+	for key := range existingLabels {
+		if _, ok := desiredLabels[key]; ok {
+			continue
+		}
+		for _, prefix := range managedPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				labelsDiff[key] = nil
+				break
+			}
+		}
+	}
+	return labelsDiff
+}
@@ -0,0 +1,55 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestSanitizeLabelValue(t *testing.T) {
+	assert.Equal(t, "gold-storage-policy", SanitizeLabelValue("gold-storage-policy"),
+		"valid value should not be changed")
+	assert.Equal(t, "System-Default", SanitizeLabelValue("System Default"), "space should be replaced")
+	assert.Equal(t, "tier-1", SanitizeLabelValue(" (tier/1) "), "invalid leading and trailing characters should be trimmed")
+	assert.Equal(t, "", SanitizeLabelValue("***"), "value without valid characters should be empty")
+
+	longValue := SanitizeLabelValue(strings.Repeat("a", 100))
+	assert.Equal(t, validation.LabelValueMaxLength, len(longValue), "long value should be truncated")
+
+	for _, value := range []string{"System Default", " (tier/1) ", strings.Repeat("ab-", 40)} {
+		assert.Empty(t, validation.IsValidLabelValue(SanitizeLabelValue(value)),
+			"sanitized value of [%s] should be valid", value)
+	}
+}
+
+func TestGetManagedLabelsDiff(t *testing.T) {
+	existingLabels := map[string]string{
+		"kubernetes.io/hostname":         "node1",
+		"example.com/ovdc":               "ovdc1",
+		"example.com/storage-profile":    "gold",
+		"metadata.example.com/owner":     "team-a",
+		"node-role.kubernetes.io/worker": "",
+	}
+	desiredLabels := map[string]string{
+		"example.com/ovdc":            "ovdc1",
+		"example.com/storage-profile": "silver",
+		"example.com/vapp":            "cluster1",
+	}
+
+	labelsDiff := GetManagedLabelsDiff(existingLabels, desiredLabels, []string{"example.com/", "metadata.example.com/"})
+	assert.Equal(t, map[string]interface{}{
+		"example.com/storage-profile": "silver",
+		"example.com/vapp":            "cluster1",
+		"metadata.example.com/owner":  nil,
+	}, labelsDiff, "only changed managed labels should be in the diff")
+
+	labelsDiff = GetManagedLabelsDiff(desiredLabels, desiredLabels, []string{"example.com/"})
+	assert.Empty(t, labelsDiff, "diff should be empty when labels match")
+}
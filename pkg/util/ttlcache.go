@@ -0,0 +1,182 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+// CacheLookupResult is the outcome of a TTLCache lookup.
+type CacheLookupResult string
+
+const (
+	// CacheHit means that an unexpired value was found.
+	CacheHit CacheLookupResult = "hit"
+	// CacheNegativeHit means that the key was recently recorded as not existing.
+	CacheNegativeHit CacheLookupResult = "negative_hit"
+	// CacheMiss means that there is no unexpired entry for the key.
+	CacheMiss CacheLookupResult = "miss"
+)
+
+type ttlCacheEntry struct {
+	value     interface{}
+	negative  bool
+	expiresAt time.Time
+}
+
+// TTLCache is a size-bounded map whose entries expire after a TTL. Negative entries record that a key does not
+// exist and expire after their own, usually shorter, TTL. When the cache is full, expired entries are evicted
+// first, then the entry that expires soonest.
+type TTLCache struct {
+	lock        sync.RWMutex
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxSize     int
+	entries     map[string]*ttlCacheEntry
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// NewTTLCache returns a cache that holds at most maxSize entries. A maxSize of 0 or less means no bound.
+func NewTTLCache(ttl time.Duration, negativeTTL time.Duration, maxSize int) *TTLCache {
+	return &TTLCache{
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		maxSize:     maxSize,
+		entries:     make(map[string]*ttlCacheEntry),
+		now:         time.Now,
+	}
+}
+
+// Get returns the value of key and whether it was a hit, a negative hit or a miss. Expired entries are evicted.
+func (c *TTLCache) Get(key string) (interface{}, CacheLookupResult) {
+	c.lock.RLock()
+	entry, ok := c.entries[key]
+	c.lock.RUnlock()
+	if !ok {
+		return nil, CacheMiss
+	}
+
+	if !c.now().Before(entry.expiresAt) {
+		c.lock.Lock()
+		// the entry may have been replaced since it was read
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.lock.Unlock()
+		return nil, CacheMiss
+	}
+
+	if entry.negative {
+		return nil, CacheNegativeHit
+	}
+	return entry.value, CacheHit
+}
+
+// Set stores value for key with the TTL of the cache.
+func (c *TTLCache) Set(key string, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.set(key, &ttlCacheEntry{
+		value:     value,
+		expiresAt: c.now().Add(c.ttl),
+	})
+}
+
+// SetNegative records that key does not exist for the negative TTL of the cache.
+func (c *TTLCache) SetNegative(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.set(key, &ttlCacheEntry{
+		negative:  true,
+		expiresAt: c.now().Add(c.negativeTTL),
+	})
+}
+
+// Delete removes key from the cache.
+func (c *TTLCache) Delete(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.entries, key)
+}
+
+// Replace replaces all values of the cache with values. Unexpired negative entries of keys that are not in values
+// are retained.
+func (c *TTLCache) Replace(values map[string]interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	entries := make(map[string]*ttlCacheEntry, len(values))
+	for key, entry := range c.entries {
+		if _, ok := values[key]; !ok && entry.negative && now.Before(entry.expiresAt) {
+			entries[key] = entry
+		}
+	}
+	c.entries = entries
+
+	for key, value := range values {
+		c.set(key, &ttlCacheEntry{
+			value:     value,
+			expiresAt: now.Add(c.ttl),
+		})
+	}
+}
+
+// Values returns the unexpired values of the cache by key.
+func (c *TTLCache) Values() map[string]interface{} {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	now := c.now()
+	values := make(map[string]interface{}, len(c.entries))
+	for key, entry := range c.entries {
+		if !entry.negative && now.Before(entry.expiresAt) {
+			values[key] = entry.value
+		}
+	}
+	return values
+}
+
+// Len returns the number of entries in the cache, including expired ones that have not been evicted yet.
+func (c *TTLCache) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return len(c.entries)
+}
+
+// set stores entry for key, evicting entries if the cache is full. The caller should hold the write lock.
+func (c *TTLCache) set(key string, entry *ttlCacheEntry) {
+	if _, ok := c.entries[key]; !ok && c.maxSize > 0 && len(c.entries) >= c.maxSize {
+		c.evict()
+	}
+	c.entries[key] = entry
+}
+
+// evict removes all expired entries, or the entry that expires soonest if none has expired. The caller should hold
+// the write lock.
+func (c *TTLCache) evict() {
+	now := c.now()
+	var evictionEntry *ttlCacheEntry
+	evictionKey := ""
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if evictionEntry == nil || entry.expiresAt.Before(evictionEntry.expiresAt) {
+			evictionKey, evictionEntry = key, entry
+		}
+	}
+	if evictionEntry != nil && len(c.entries) >= c.maxSize {
+		delete(c.entries, evictionKey)
+	}
+}
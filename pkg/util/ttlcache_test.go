@@ -0,0 +1,114 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	return fc.now
+}
+
+func newTestTTLCache(maxSize int) (*TTLCache, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := NewTTLCache(time.Minute, 10*time.Second, maxSize)
+	cache.now = clock.Now
+	return cache, clock
+}
+
+func TestTTLCacheExpiry(t *testing.T) {
+	cache, clock := newTestTTLCache(0)
+
+	cache.Set("vm-1", 1)
+	value, result := cache.Get("vm-1")
+	assert.Equal(t, CacheHit, result, "unexpired entry should be a hit")
+	assert.Equal(t, 1, value, "unexpected cached value")
+
+	clock.now = clock.now.Add(time.Minute)
+	value, result = cache.Get("vm-1")
+	assert.Equal(t, CacheMiss, result, "expired entry should be a miss")
+	assert.Nil(t, value, "expired entry should have no value")
+	assert.Equal(t, 0, cache.Len(), "expired entry should be evicted on lookup")
+
+	_, result = cache.Get("vm-2")
+	assert.Equal(t, CacheMiss, result, "unknown key should be a miss")
+
+	return
+}
+
+func TestTTLCacheNegativeEntries(t *testing.T) {
+	cache, clock := newTestTTLCache(0)
+
+	cache.SetNegative("vm-1")
+	_, result := cache.Get("vm-1")
+	assert.Equal(t, CacheNegativeHit, result, "negative entry should be a negative hit")
+
+	clock.now = clock.now.Add(10 * time.Second)
+	_, result = cache.Get("vm-1")
+	assert.Equal(t, CacheMiss, result, "negative entry should expire after the negative TTL")
+
+	cache.SetNegative("vm-1")
+	cache.Set("vm-1", 1)
+	_, result = cache.Get("vm-1")
+	assert.Equal(t, CacheHit, result, "value should replace the negative entry")
+
+	return
+}
+
+func TestTTLCacheSizeBound(t *testing.T) {
+	cache, clock := newTestTTLCache(2)
+
+	cache.Set("vm-1", 1)
+	clock.now = clock.now.Add(time.Second)
+	cache.Set("vm-2", 2)
+	cache.Set("vm-3", 3)
+	assert.Equal(t, 2, cache.Len(), "cache should not grow beyond its size")
+	_, result := cache.Get("vm-1")
+	assert.Equal(t, CacheMiss, result, "entry expiring soonest should be evicted")
+
+	cache.Set("vm-2", 22)
+	assert.Equal(t, 2, cache.Len(), "updating an entry should not evict another one")
+
+	clock.now = clock.now.Add(time.Minute)
+	cache.SetNegative("vm-4")
+	assert.Equal(t, 1, cache.Len(), "all expired entries should be evicted when the cache is full")
+
+	return
+}
+
+func TestTTLCacheReplace(t *testing.T) {
+	cache, clock := newTestTTLCache(0)
+
+	cache.Set("vm-1", 1)
+	cache.SetNegative("vm-2")
+	cache.SetNegative("vm-3")
+	clock.now = clock.now.Add(time.Second)
+
+	cache.Replace(map[string]interface{}{
+		"vm-3": 3,
+		"vm-4": 4,
+	})
+	_, result := cache.Get("vm-1")
+	assert.Equal(t, CacheMiss, result, "value absent from the replacement should be removed")
+	_, result = cache.Get("vm-2")
+	assert.Equal(t, CacheNegativeHit, result, "negative entry absent from the replacement should be retained")
+	value, result := cache.Get("vm-3")
+	assert.Equal(t, CacheHit, result, "replacement should override negative entries")
+	assert.Equal(t, 3, value, "unexpected cached value")
+
+	assert.Equal(t, map[string]interface{}{"vm-3": 3, "vm-4": 4}, cache.Values(),
+		"values should not include negative entries")
+
+	return
+}
@@ -6,13 +6,11 @@
 package vcdsdk
 
 import (
-	"crypto/tls"
 	"fmt"
 	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
 	"github.com/vmware/go-vcloud-director/v2/govcd"
 	"k8s.io/klog"
 	"net/http"
-	"net/url"
 )
 
 const (
@@ -30,26 +28,38 @@ type VCDAuthConfig struct {
 	VDC          string `json:"vdc"` // TODO: Get rid of
 	Insecure     bool   `json:"insecure"`
 	IsSysAdmin   bool   // will be set by GetBearerToken()
+	// Transport configures the connections to the VCD site; the defaults are used if nil
+	Transport *TransportConfig `json:"-"`
+	// ServiceAccountClientID is the client ID of the service account whose RefreshToken is used, if any
+	ServiceAccountClientID string `json:"serviceAccountClientId"`
+	// OnRefreshTokenRotated is called with the new refresh token of the service account after every authentication
+	OnRefreshTokenRotated func(refreshToken string) error `json:"-"`
+	// rateLimiter is the rate limiter of the Client that the config authenticates, if any
+	rateLimiter *clientRateLimiter
 }
 
 func (config *VCDAuthConfig) GetBearerToken() (*govcd.VCDClient, *http.Response, error) {
 	href := fmt.Sprintf("%s/api", config.Host)
-	u, err := url.ParseRequestURI(href)
+	// continue using API version 37.2 for GoVCD clients
+	vcdClient, err := config.newVCDClient()
 	if err != nil {
-		return nil, nil, fmt.Errorf("unable to parse url [%s]: %s", href, err)
-	}
-
-	vcdClient := govcd.NewVCDClient(*u, config.Insecure)
-	// continue using API version 36.0 for GoVCD clients
-	vcdClient.Client.APIVersion = VCloudApiVersion_37_2
-	if err != nil {
-		klog.Errorf("failed to set API version on GoVCD client: [%v]", err)
-		return nil, nil, fmt.Errorf("failed to set API version on the GoVCD client: [%v]", err)
+		klog.Errorf("failed to create GoVCD client: [%v]", err)
+		return nil, nil, fmt.Errorf("failed to create the GoVCD client: [%v]", err)
 	}
 
 	klog.Infof("Using VCD OpenAPI version [%s]", vcdClient.Client.APIVersion)
 
 	var resp *http.Response
+	if config.ServiceAccountClientID != "" {
+		if err = config.authenticateServiceAccount(vcdClient); err != nil {
+			return nil, nil, err
+		}
+		config.IsSysAdmin = vcdClient.Client.IsSysAdmin
+
+		klog.Infof("Running module as service account [%s], sysadmin [%v]", config.ServiceAccountClientID,
+			vcdClient.Client.IsSysAdmin)
+		return vcdClient, resp, nil
+	}
 	if config.RefreshToken != "" {
 		// NOTE: for a system admin user using refresh token, the userOrg will still be tenant org.
 		// try setting authentication as a system org user
@@ -97,9 +107,7 @@ func (config *VCDAuthConfig) GetSwaggerClientFromSecrets() (*govcd.VCDClient, *s
 	swaggerConfig37.BasePath = fmt.Sprintf("%s/cloudapi", config.Host)
 	swaggerConfig37.AddDefaultHeader("Authorization", authHeader)
 	swaggerConfig37.HTTPClient = &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: config.Insecure},
-		},
+		Transport: vcdClient.Client.Http.Transport,
 	}
 	apiClient37 := swaggerClient.NewAPIClient(swaggerConfig37)
 
@@ -108,15 +116,11 @@ func (config *VCDAuthConfig) GetSwaggerClientFromSecrets() (*govcd.VCDClient, *s
 
 func (config *VCDAuthConfig) GetPlainClientFromSecrets() (*govcd.VCDClient, error) {
 
-	href := fmt.Sprintf("%s/api", config.Host)
-	u, err := url.ParseRequestURI(href)
+	// continue using API version 37.2 for GoVCD clients
+	vcdClient, err := config.newVCDClient()
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse url: [%s]: [%v]", href, err)
+		return nil, err
 	}
-
-	vcdClient := govcd.NewVCDClient(*u, config.Insecure)
-	// continue using API version 36 for GoVCD clients
-	vcdClient.Client.APIVersion = VCloudApiVersion_37_2
 	klog.Infof("Using VCD XML API version [%s]", vcdClient.Client.APIVersion)
 	if err = vcdClient.Authenticate(config.User, config.Password, config.UserOrg); err != nil {
 		return nil, fmt.Errorf("cannot authenticate with vcd: [%v]", err)
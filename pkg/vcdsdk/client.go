@@ -6,26 +6,53 @@
 package vcdsdk
 
 import (
-	"crypto/tls"
 	"fmt"
-	swaggerClient37 "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
 	"k8s.io/klog"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	swaggerClient37 "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
 	"github.com/vmware/go-vcloud-director/v2/govcd"
 )
 
 // Client :
 type Client struct {
-	VCDAuthConfig         *VCDAuthConfig
 	ClusterOrgName        string
 	ClusterOVDCIdentifier string
-	VCDClient             *govcd.VCDClient
-	VDC                   *govcd.Vdc // TODO: Incrementally remove and test in tests
-	APIClient             *swaggerClient37.APIClient
 	RWLock                sync.RWMutex
+
+	// Deprecated: VCDAuthConfig, VCDClient, VDC and APIClient are replaced whenever the client authenticates again
+	// and are not safe to read while the client is used concurrently. Use Session instead, which returns a snapshot
+	// of all of them. They are kept up to date with the current session for existing callers; setting them has no
+	// effect on the session.
+	VCDAuthConfig *VCDAuthConfig
+	// Deprecated: use Session.
+	VCDClient *govcd.VCDClient
+	// Deprecated: use Session.
+	VDC *govcd.Vdc
+	// Deprecated: use Session.
+	APIClient *swaggerClient37.APIClient
+
+	// sessionLock serializes authentication and the replacement of the session
+	sessionLock sync.Mutex
+	// session holds the auth config, govcd and swagger clients and VDC; it is replaced as a whole and read with Session
+	session atomic.Pointer[Session]
+
+	// gatewayLocks limit the concurrent modifications of each edge gateway
+	gatewayLocks keyedLimiter
+	// serviceLocks serialize the operations on the load balancer of each Service
+	serviceLocks keyedLimiter
+	// ipAllocationLocks serialize the allocation of IPs from the pools of each edge gateway
+	ipAllocationLocks keyedLimiter
+	// pendingIPs are the IPs that were allocated to load balancers but are not yet used in VCD
+	pendingIPs pendingIPs
+	// lookups cache the orgs, VDCs, gateways of networks and service engine group assignments that are looked up
+	lookups lookupCache
+	// rateLimiter limits the rate of the requests of all sessions to VCD
+	rateLimiter clientRateLimiter
 }
 
 func GetUserAndOrg(fullUserName string, clusterOrg string, currentUserOrg string) (userOrg string, userName string, err error) {
@@ -56,90 +83,140 @@ func GetUserAndOrg(fullUserName string, clusterOrg string, currentUserOrg string
 	return userOrg, userName, nil
 }
 
-// RefreshBearerToken gets a new Bearer Token from an API token
+// Session returns the current session of the client. An operation should take a snapshot once and use its clients
+// throughout, rather than reading the session of the client again.
+func (client *Client) Session() *Session {
+	return client.session.Load()
+}
+
+// RefreshBearerToken ensures that the client has a valid session. The session is reused until shortly before its
+// token expires or until VCD rejects a request as unauthorized; only then is the client authenticated again and are
+// the VDC and swagger client rebuilt. Callers should take a snapshot with Session after calling it.
 func (client *Client) RefreshBearerToken() error {
-	klog.Infof("Refreshing vcd client")
+	_, err := client.refreshSession()
+	return err
+}
 
-	href := fmt.Sprintf("%s/api", client.VCDAuthConfig.Host)
-	// continue using API version 36 for GoVCD client
-	client.VCDClient.Client.APIVersion = VCloudApiVersion_37_2
+// refreshSession returns the current session of the client, after authenticating again if the session expired or
+// was rejected by VCD.
+func (client *Client) refreshSession() (*Session, error) {
+	client.sessionLock.Lock()
+	defer client.sessionLock.Unlock()
 
-	klog.Infof("Is user sysadmin: [%v]", client.VCDAuthConfig.IsSysAdmin)
-	if client.VCDAuthConfig.RefreshToken != "" {
-		userOrg := client.VCDAuthConfig.UserOrg
-		if client.VCDAuthConfig.IsSysAdmin {
-			userOrg = "system"
-		}
-		// Refresh vcd client using refresh token as system org user
-		err := client.VCDClient.SetToken(userOrg,
-			govcd.ApiTokenHeader, client.VCDAuthConfig.RefreshToken)
-		if err != nil {
-			return fmt.Errorf("failed to refresh VCD client with the refresh token: [%v]", err)
-		}
-	} else if client.VCDAuthConfig.User != "" && client.VCDAuthConfig.Password != "" {
-		// Refresh vcd client using username and password
-		resp, err := client.VCDClient.GetAuthResponse(client.VCDAuthConfig.User, client.VCDAuthConfig.Password,
-			client.VCDAuthConfig.UserOrg)
-		if err != nil {
-			return fmt.Errorf("unable to authenticate [%s/%s] for url [%s]: [%+v] : [%v]",
-				client.VCDAuthConfig.UserOrg, client.VCDAuthConfig.User, href, resp, err)
-		}
-	} else {
-		return fmt.Errorf(
-			"unable to find refresh token or secret to refresh vcd client for user [%s/%s] and url [%s]",
-			client.VCDAuthConfig.UserOrg, client.VCDAuthConfig.User, href)
+	current := client.Session()
+	if current.isValid(time.Now()) {
+		return current, nil
 	}
 
-	// reset legacy client
-	// Update client VDC if cluster org is provided
-	if client.ClusterOrgName != "" {
-		org, err := client.VCDClient.GetOrgByNameOrId(client.ClusterOrgName)
-		if err != nil {
-			return fmt.Errorf("unable to get vcd organization [%s]: [%v]",
-				client.ClusterOrgName, err)
-		}
-		vdc, err := org.GetVDCByNameOrId(client.ClusterOVDCIdentifier, true)
-		if err != nil {
-			return fmt.Errorf("unable to get VDC from org [%s], VDC [%s]: [%v]",
-				client.ClusterOrgName, client.VCDAuthConfig.VDC, err)
-		}
-		client.VDC = vdc
-	}
-
-	// reset swagger client
-	swaggerConfig := swaggerClient37.NewConfiguration()
-	swaggerConfig.BasePath = fmt.Sprintf("%s/cloudapi", client.VCDAuthConfig.Host)
-	swaggerConfig.AddDefaultHeader("Authorization", fmt.Sprintf("Bearer %s", client.VCDClient.Client.VCDToken))
-	swaggerConfig.HTTPClient = &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: client.VCDAuthConfig.Insecure},
-		},
-	}
-	client.APIClient = swaggerClient37.NewAPIClient(swaggerConfig)
-
-	// initialize swagger client for API version 37.2 only if API version 37.2 is available
-	if client.VCDClient.Client.APIVCDMaxVersionIs(fmt.Sprintf(">=%s", VCloudApiVersion_37_2)) {
-		swaggerConfig37 := swaggerClient37.NewConfiguration()
-		swaggerConfig37.BasePath = fmt.Sprintf("%s/cloudapi", client.VCDAuthConfig.Host)
-		swaggerConfig37.AddDefaultHeader("Authorization", fmt.Sprintf("Bearer %s", client.VCDClient.Client.VCDToken))
-		swaggerConfig37.HTTPClient = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: client.VCDAuthConfig.Insecure},
-			},
-		}
-		client.APIClient = swaggerClient37.NewAPIClient(swaggerConfig37)
+	klog.Infof("Refreshing vcd client")
+	klog.Infof("Is user sysadmin: [%v]", current.VCDAuthConfig.IsSysAdmin)
+	// the auth config of the current session is read concurrently; the refresh token is rotated on a copy
+	authConfig := *current.VCDAuthConfig
+	vcdClient, err := authConfig.authenticate()
+	if err != nil {
+		client.setRefreshToken(authConfig.ServiceAccountClientID, authConfig.RefreshToken)
+		return nil, err
+	}
+	if err = client.setSession(&authConfig, vcdClient, client.ClusterOrgName != ""); err != nil {
+		client.setRefreshToken(authConfig.ServiceAccountClientID, authConfig.RefreshToken)
+		return nil, err
 	}
 
 	klog.Info("successfully refreshed all clients")
+	return client.Session(), nil
+}
+
+// UpdateCredentials authenticates with the new credentials and TLS and transport settings and verifies that the org
+// and VDC of the cluster can be accessed with them before replacing the credentials and session of the client. The
+// client keeps its previous credentials and session if the new ones cannot be verified. The refresh token is the one
+// of the service account with serviceAccountClientID if it is set.
+func (client *Client) UpdateCredentials(userOrg string, user string, password string, refreshToken string,
+	serviceAccountClientID string, insecure bool, transportConfig *TransportConfig) error {
+	newUserOrg, newUsername, err := GetUserAndOrg(user, client.ClusterOrgName, userOrg)
+	if err != nil {
+		return fmt.Errorf("error parsing username before authenticating to VCD: [%v]", err)
+	}
+
+	client.sessionLock.Lock()
+	defer client.sessionLock.Unlock()
+
+	current := client.Session()
+	vcdAuthConfig := NewVCDAuthConfigFromSecrets(current.VCDAuthConfig.Host, newUsername, password, refreshToken,
+		newUserOrg, insecure)
+	vcdAuthConfig.Transport = transportConfig
+	vcdAuthConfig.rateLimiter = &client.rateLimiter
+	if serviceAccountClientID != "" {
+		vcdAuthConfig.ServiceAccountClientID = serviceAccountClientID
+		vcdAuthConfig.OnRefreshTokenRotated = current.VCDAuthConfig.OnRefreshTokenRotated
+	}
+	vcdClient, _, err := vcdAuthConfig.GetBearerToken()
+	if err != nil {
+		// the refresh token may have been rotated even though authentication failed
+		client.setRefreshToken(vcdAuthConfig.ServiceAccountClientID, vcdAuthConfig.RefreshToken)
+		return fmt.Errorf("unable to authenticate user [%s/%s] with new credentials: [%v]", newUserOrg,
+			newUsername, err)
+	}
+	if err = client.setSession(vcdAuthConfig, vcdClient, client.ClusterOrgName != ""); err != nil {
+		client.setRefreshToken(vcdAuthConfig.ServiceAccountClientID, vcdAuthConfig.RefreshToken)
+		return fmt.Errorf("unable to verify new credentials of user [%s/%s]: [%v]", newUserOrg, newUsername, err)
+	}
+
+	klog.Infof("Updated credentials of vcd client to user [%s/%s]", newUserOrg, newUsername)
 	return nil
 }
 
+// NewVCDClientFromServiceAccount returns a client authenticated as the service account with clientID in userOrg, or
+// in orgName if userOrg is empty. Every authentication rotates the refresh token of the service account;
+// onRefreshTokenRotated, if set, is called with the new refresh token so that it can be persisted.
+func NewVCDClientFromServiceAccount(host string, orgName string, vdcIdentifier string, userOrg string,
+	clientID string, refreshToken string, onRefreshTokenRotated func(refreshToken string) error, insecure bool,
+	transportConfig *TransportConfig, getVdcClient bool) (*Client, error) {
+	if clientID == "" || refreshToken == "" {
+		return nil, fmt.Errorf("need a valid client ID and refresh token of the service account")
+	}
+	if userOrg == "" {
+		userOrg = orgName
+	}
+
+	client := &Client{
+		ClusterOrgName:        orgName,
+		ClusterOVDCIdentifier: vdcIdentifier,
+	}
+	vcdAuthConfig := NewVCDAuthConfigFromSecrets(host, "", "", refreshToken, userOrg, insecure)
+	vcdAuthConfig.Transport = transportConfig
+	vcdAuthConfig.ServiceAccountClientID = clientID
+	vcdAuthConfig.OnRefreshTokenRotated = onRefreshTokenRotated
+	vcdAuthConfig.rateLimiter = &client.rateLimiter
+
+	vcdClient, _, err := vcdAuthConfig.GetBearerToken()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get bearer token of service account [%s]: [%v]", clientID, err)
+	}
+
+	// the session is reused by RefreshBearerToken until it expires
+	if err = client.setSession(vcdAuthConfig, vcdClient, getVdcClient); err != nil {
+		return nil, err
+	}
+
+	klog.Infof("Client is sysadmin: [%v]", client.Session().VCDClient.Client.IsSysAdmin)
+	return client, nil
+}
+
 // NewVCDClientFromSecrets :
 // host, orgName, userOrg, refreshToken, insecure, user, password
 
 // New method from (vdcClient, vdcIdentifier) return *govcd.Vdc
 func NewVCDClientFromSecrets(host string, orgName string, vdcIdentifier string, userOrg string,
 	user string, password string, refreshToken string, insecure bool, getVdcClient bool) (*Client, error) {
+	return NewVCDClientFromSecretsWithTransport(host, orgName, vdcIdentifier, userOrg, user, password, refreshToken,
+		insecure, nil, getVdcClient)
+}
+
+// NewVCDClientFromSecretsWithTransport is NewVCDClientFromSecrets with the trusted CAs, proxy, timeouts and
+// connection limits of transportConfig. The defaults are used if transportConfig is nil.
+func NewVCDClientFromSecretsWithTransport(host string, orgName string, vdcIdentifier string, userOrg string,
+	user string, password string, refreshToken string, insecure bool, transportConfig *TransportConfig,
+	getVdcClient bool) (*Client, error) {
 
 	// TODO: validation of parameters
 
@@ -156,11 +233,17 @@ func NewVCDClientFromSecrets(host string, orgName string, vdcIdentifier string,
 		return nil, fmt.Errorf("error parsing username before authenticating to VCD: [%v]", err)
 	}
 
+	client := &Client{
+		ClusterOrgName:        orgName,
+		ClusterOVDCIdentifier: vdcIdentifier,
+	}
 	vcdAuthConfig := NewVCDAuthConfigFromSecrets(host, newUsername, password, refreshToken, newUserOrg, insecure) //
+	vcdAuthConfig.Transport = transportConfig
+	vcdAuthConfig.rateLimiter = &client.rateLimiter
 
-	vcdClient, apiClient, err := vcdAuthConfig.GetSwaggerClientFromSecrets()
+	vcdClient, _, err := vcdAuthConfig.GetBearerToken()
 	if err != nil {
-		return nil, fmt.Errorf("unable to get swagger client from secrets: [%v]", err)
+		return nil, fmt.Errorf("unable to get bearer token from secrets: [%v]", err)
 	}
 
 	// We want to verify that user/pass is correct by getting the auth response. Unfortunately, govcd does not provide
@@ -177,27 +260,11 @@ func NewVCDClientFromSecrets(host string, orgName string, vdcIdentifier string,
 		}
 	}
 
-	client := &Client{
-		VCDAuthConfig:         vcdAuthConfig,
-		ClusterOrgName:        orgName,
-		ClusterOVDCIdentifier: vdcIdentifier,
-		VCDClient:             vcdClient,
-		APIClient:             apiClient,
-	}
-
-	if getVdcClient {
-		org, err := vcdClient.GetOrgByName(orgName)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get org from name [%s]: [%v]", orgName, err)
-		}
-
-		client.VDC, err = org.GetVDCByNameOrId(vdcIdentifier, true)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get VDC [%s] from org [%s]: [%v]", vdcIdentifier, orgName, err)
-		}
+	// the session is reused by RefreshBearerToken until it expires
+	if err = client.setSession(vcdAuthConfig, vcdClient, getVdcClient); err != nil {
+		return nil, err
 	}
-	client.VCDClient = vcdClient
 
-	klog.Infof("Client is sysadmin: [%v]", client.VCDClient.Client.IsSysAdmin)
+	klog.Infof("Client is sysadmin: [%v]", client.Session().VCDClient.Client.IsSysAdmin)
 	return client, nil
 }
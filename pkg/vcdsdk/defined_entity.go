@@ -31,6 +31,7 @@ const (
 	VcdResourceLoadBalancerPool = "lb-pool"
 	VcdResourceDNATRule         = "dnat-rule"
 	VcdResourceAppPortProfile   = "app-port-profile"
+	VcdResourceAdvertisedPrefix = "advertised-prefix"
 
 	CAPVCDEntityTypeVendor = "vmware"
 	CAPVCDEntityTypeNss    = "capvcdCluster"
@@ -219,6 +220,9 @@ status:
 // as the order coming in are not necessary sorted by timestamps
 // 3.A method that will remove errors and addition of success event at the same time
 func (rdeManager *RDEManager) AddToErrorSet(ctx context.Context, componentSectionName string, newError BackendError, rollingWindowSize int) error {
+	ctx, span := startSpan(ctx, "RDEManager.AddToErrorSet")
+	defer span.End()
+
 	if rdeManager.ClusterID == "" || strings.HasPrefix(rdeManager.ClusterID, NoRdePrefix) {
 		// Indicates that the RDE ID is either empty or it was auto-generated.
 		klog.V(3).Infof("ClusterID [%s] is empty or generated, hence cannot add errors [%v] to RDE",
@@ -226,7 +230,8 @@ func (rdeManager *RDEManager) AddToErrorSet(ctx context.Context, componentSectio
 		return nil
 	}
 	client := rdeManager.Client
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
 		return fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
 	}
@@ -234,7 +239,7 @@ func (rdeManager *RDEManager) AddToErrorSet(ctx context.Context, componentSectio
 		return fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
 	for i := MaxRDEUpdateRetries; i > 1; i-- {
-		rde, resp, etag, err := rdeManager.Client.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeManager.ClusterID,
+		rde, resp, etag, err := session.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeManager.ClusterID,
 			clusterOrg.Org.ID, nil)
 		if resp != nil && resp.StatusCode != http.StatusOK {
 			var responseMessageBytes []byte
@@ -272,7 +277,7 @@ func (rdeManager *RDEManager) AddToErrorSet(ctx context.Context, componentSectio
 		rde.Entity["status"] = updatedStatusMap
 
 		// persist the updated statusMap to VCD
-		_, resp, err = rdeManager.Client.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeManager.ClusterID, clusterOrg.Org.ID, nil)
+		_, resp, err = session.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeManager.ClusterID, clusterOrg.Org.ID, nil)
 		if resp != nil {
 			if resp.StatusCode == http.StatusPreconditionFailed {
 				klog.V(4).Infof("etag mismatch [%s] while adding newError [%s] in RDE [%s]. Retry attempts remaining: [%d]", etag, newError.Name, rdeManager.ClusterID, i-1)
@@ -316,6 +321,9 @@ RDE.entity
 	         <cloudInitError>
 */
 func (rdeManager *RDEManager) RemoveErrorByNameOrIdFromErrorSet(ctx context.Context, componentSectionName string, errorName string, vcdResourceId string, vcdResourceName string) error {
+	ctx, span := startSpan(ctx, "RDEManager.RemoveErrorByNameOrIdFromErrorSet")
+	defer span.End()
+
 	if rdeManager.ClusterID == "" || strings.HasPrefix(rdeManager.ClusterID, NoRdePrefix) {
 		// Indicates that the RDE ID is either empty or it was auto-generated.
 		klog.V(3).Infof("ClusterID [%s] is empty or generated, hence cannot remove any errors of name [%s] from RDE",
@@ -326,7 +334,8 @@ func (rdeManager *RDEManager) RemoveErrorByNameOrIdFromErrorSet(ctx context.Cont
 		return fmt.Errorf("errorName cannot be empty, while removing error from the errorSet of [%s]", rdeManager.ClusterID)
 	}
 	client := rdeManager.Client
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
 		return fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
 	}
@@ -334,7 +343,7 @@ func (rdeManager *RDEManager) RemoveErrorByNameOrIdFromErrorSet(ctx context.Cont
 		return fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
 	for i := MaxRDEUpdateRetries; i > 1; i-- {
-		rde, resp, etag, err := rdeManager.Client.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeManager.ClusterID,
+		rde, resp, etag, err := session.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeManager.ClusterID,
 			clusterOrg.Org.ID, nil)
 		if resp != nil && resp.StatusCode != http.StatusOK {
 			var responseMessageBytes []byte
@@ -376,7 +385,7 @@ func (rdeManager *RDEManager) RemoveErrorByNameOrIdFromErrorSet(ctx context.Cont
 		rde.Entity["status"] = updatedStatusMap
 
 		// persist the updated statusMap to VCD
-		_, resp, err = rdeManager.Client.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeManager.ClusterID, clusterOrg.Org.ID, nil)
+		_, resp, err = session.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeManager.ClusterID, clusterOrg.Org.ID, nil)
 		if resp != nil {
 			if resp.StatusCode == http.StatusPreconditionFailed {
 				klog.V(4).Infof("etag while removing error(s) of name [%s] in RDE [%s]. Retry attempts remaining: [%d]", errorName, rdeManager.ClusterID, i-1)
@@ -496,6 +505,9 @@ status:
 	     - existingEvent
 */
 func (rdeManager *RDEManager) AddToEventSet(ctx context.Context, componentSectionName string, newEvent BackendEvent, rollingWindowSize int) error {
+	ctx, span := startSpan(ctx, "RDEManager.AddToEventSet")
+	defer span.End()
+
 	if rdeManager.ClusterID == "" || strings.HasPrefix(rdeManager.ClusterID, NoRdePrefix) {
 		// Indicates that the RDE ID is either empty or it was auto-generated.
 		klog.V(3).Infof("ClusterID [%s] is empty or generated, hence cannot add events [%#v] to RDE",
@@ -503,7 +515,8 @@ func (rdeManager *RDEManager) AddToEventSet(ctx context.Context, componentSectio
 		return nil
 	}
 	client := rdeManager.Client
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
 		return fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
 	}
@@ -511,7 +524,7 @@ func (rdeManager *RDEManager) AddToEventSet(ctx context.Context, componentSectio
 		return fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
 	for i := MaxRDEUpdateRetries; i > 1; i-- {
-		rde, resp, etag, err := rdeManager.Client.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeManager.ClusterID,
+		rde, resp, etag, err := session.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeManager.ClusterID,
 			clusterOrg.Org.ID, nil)
 		if resp != nil && resp.StatusCode != http.StatusOK {
 			var responseMessageBytes []byte
@@ -549,7 +562,7 @@ func (rdeManager *RDEManager) AddToEventSet(ctx context.Context, componentSectio
 		rde.Entity["status"] = updatedStatusMap
 
 		// persist the updated statusMap to VCD
-		_, resp, err = rdeManager.Client.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeManager.ClusterID, clusterOrg.Org.ID, nil)
+		_, resp, err = session.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeManager.ClusterID, clusterOrg.Org.ID, nil)
 		if resp != nil {
 			if resp.StatusCode == http.StatusPreconditionFailed {
 				klog.V(4).Infof("etag mismatch [%s] while adding newEvent [%s] in RDE [%s]. Retry attempts remaining: [%d]", etag, newEvent.Name, rdeManager.ClusterID, i-1)
@@ -578,6 +591,7 @@ func (rdeManager *RDEManager) AddToEventSet(ctx context.Context, componentSectio
 }
 
 func (rdeManager *RDEManager) IsCapvcdEntityTypeRegistered(version string) bool {
+	session := rdeManager.Client.Session()
 	entityTypeID := strings.Join(
 		[]string{
 			CAPVCDEntityTypePrefix,
@@ -585,15 +599,15 @@ func (rdeManager *RDEManager) IsCapvcdEntityTypeRegistered(version string) bool
 		},
 		":",
 	)
-	entityTypeListUrl, err := rdeManager.Client.VCDClient.Client.OpenApiBuildEndpoint(
+	entityTypeListUrl, err := session.VCDClient.Client.OpenApiBuildEndpoint(
 		"1.0.0/entityTypes/" + entityTypeID)
 	if err != nil {
 		klog.Errorf("failed to construct URL to get list of entity types")
 		return false
 	}
 	var output EntityType
-	err = rdeManager.Client.VCDClient.Client.OpenApiGetItem(
-		rdeManager.Client.VCDClient.Client.APIVersion, entityTypeListUrl,
+	err = session.VCDClient.Client.OpenApiGetItem(
+		session.VCDClient.Client.APIVersion, entityTypeListUrl,
 		url.Values{}, &output, nil)
 	if err != nil {
 		klog.Errorf("CAPVCD entity type [%s] not registered: [%v]", entityTypeID, err)
@@ -606,6 +620,9 @@ func (rdeManager *RDEManager) IsCapvcdEntityTypeRegistered(version string) bool
 // AddToVCDResourceSet adds a VCDResource to the VCDResourceSet of the component in the RDE
 func (rdeManager *RDEManager) AddToVCDResourceSet(ctx context.Context, component string, resourceType string,
 	resourceName string, resourceId string, additionalDetails map[string]interface{}) error {
+	ctx, span := startSpan(ctx, "RDEManager.AddToVCDResourceSet")
+	defer span.End()
+
 	if rdeManager.ClusterID == "" || strings.HasPrefix(rdeManager.ClusterID, NoRdePrefix) {
 		// Indicates that the RDE ID is either empty or it was auto-generated.
 		klog.V(3).Infof("ClusterID [%s] is empty or generated, hence not adding VCDResource [%s:%s] to RDE",
@@ -613,7 +630,8 @@ func (rdeManager *RDEManager) AddToVCDResourceSet(ctx context.Context, component
 		return nil
 	}
 	client := rdeManager.Client
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
 		return fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
 	}
@@ -621,7 +639,7 @@ func (rdeManager *RDEManager) AddToVCDResourceSet(ctx context.Context, component
 		return fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
 	for i := MaxRDEUpdateRetries; i > 1; i-- {
-		rde, resp, etag, err := rdeManager.Client.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeManager.ClusterID,
+		rde, resp, etag, err := session.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeManager.ClusterID,
 			clusterOrg.Org.ID, nil)
 		if resp != nil && resp.StatusCode != http.StatusOK {
 			var responseMessageBytes []byte
@@ -666,7 +684,7 @@ func (rdeManager *RDEManager) AddToVCDResourceSet(ctx context.Context, component
 			return nil
 		}
 		rde.Entity["status"] = updatedStatusMap
-		_, resp, err = rdeManager.Client.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeManager.ClusterID, clusterOrg.Org.ID, nil)
+		_, resp, err = session.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeManager.ClusterID, clusterOrg.Org.ID, nil)
 		if resp != nil {
 			if resp.StatusCode == http.StatusPreconditionFailed {
 				klog.V(4).Infof("etag mismatch [%s] while adding [%s/%s] to VCDResourceSet in RDE [%s]. Retry attempts remaining: [%d]", etag, vcdResource.Type, vcdResource.Name, rdeManager.ClusterID, i-1)
@@ -693,6 +711,72 @@ func (rdeManager *RDEManager) AddToVCDResourceSet(ctx context.Context, component
 	return nil
 }
 
+// GetVCDResourcesFromStatusMap returns the VCDResources of resourceType in the VCDResourceSet of the component. No
+// resources are returned if the component is absent. This function doesn't make any calls to VCD.
+func GetVCDResourcesFromStatusMap(component string, statusMap map[string]interface{},
+	resourceType string) ([]VCDResource, error) {
+	componentIf, ok := statusMap[component]
+	if !ok {
+		return nil, nil
+	}
+	componentMap, ok := componentIf.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to convert the status belonging to component [%s] to map[string]interface{}", component)
+	}
+	componentStatus, err := convertMapToComponentStatus(componentMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert status of component [%s] to component status: [%v]", component, err)
+	}
+
+	vcdResources := make([]VCDResource, 0)
+	for _, resource := range componentStatus.VCDResourceSet {
+		if resource.Type == resourceType {
+			vcdResources = append(vcdResources, resource)
+		}
+	}
+	return vcdResources, nil
+}
+
+// GetVCDResources returns the VCDResources of resourceType in the VCDResourceSet of the component in the RDE. No
+// resources are returned if the RDE is not a CAPVCD entity since only those have a VCDResourceSet.
+func (rdeManager *RDEManager) GetVCDResources(ctx context.Context, component string,
+	resourceType string) ([]VCDResource, error) {
+	ctx, span := startSpan(ctx, "RDEManager.GetVCDResources")
+	defer span.End()
+
+	if rdeManager.ClusterID == "" || strings.HasPrefix(rdeManager.ClusterID, NoRdePrefix) {
+		klog.V(3).Infof("ClusterID [%s] is empty or generated, hence no VCDResources of type [%s] in RDE",
+			rdeManager.ClusterID, resourceType)
+		return nil, nil
+	}
+	client := rdeManager.Client
+	session := client.Session()
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
+	}
+	if clusterOrg == nil || clusterOrg.Org == nil {
+		return nil, fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
+	}
+	rde, resp, _, err := session.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeManager.ClusterID,
+		clusterOrg.Org.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving the RDE [%s]: resp: [%v]: [%w]", rdeManager.ClusterID, resp, err)
+	}
+	if !IsCAPVCDEntityType(rde.EntityType) {
+		return nil, nil
+	}
+	statusIf, ok := rde.Entity["status"]
+	if !ok {
+		return nil, nil
+	}
+	statusMap, ok := statusIf.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to convert RDE status [%s] to map[string]interface{}", rdeManager.ClusterID)
+	}
+	return GetVCDResourcesFromStatusMap(component, statusMap, resourceType)
+}
+
 // RemoveVCDResourceSetFromStatusMap removes a VCDResource from VCDResourceSet if type and name of the resource matches
 // If the component is absent, an empty component is initialized.
 func RemoveVCDResourceSetFromStatusMap(component string, componentName string, componentVersion string,
@@ -744,13 +828,17 @@ func RemoveVCDResourceSetFromStatusMap(component string, componentName string, c
 // Removal of a VCDResource from VCDResourceSet is done by name to support removal of the resource from RDE on retries if the resource
 // has been deleted from VCD
 func (rdeManager *RDEManager) RemoveFromVCDResourceSet(ctx context.Context, component, resourceType, resourceName string) error {
+	ctx, span := startSpan(ctx, "RDEManager.RemoveFromVCDResourceSet")
+	defer span.End()
+
 	if rdeManager.ClusterID == "" || strings.HasPrefix(rdeManager.ClusterID, NoRdePrefix) {
 		klog.V(3).Infof("ClusterID [%s] is empty or generated, hence not removing VCDResource [%s:%s] from RDE",
 			rdeManager.ClusterID, resourceType, resourceName)
 		return nil
 	}
 	client := rdeManager.Client
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
 		return fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
 	}
@@ -758,7 +846,7 @@ func (rdeManager *RDEManager) RemoveFromVCDResourceSet(ctx context.Context, comp
 		return fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
 	for i := MaxRDEUpdateRetries; i > 1; i-- {
-		rde, resp, etag, err := rdeManager.Client.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeManager.ClusterID,
+		rde, resp, etag, err := session.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeManager.ClusterID,
 			clusterOrg.Org.ID, nil)
 		if resp != nil && resp.StatusCode != http.StatusOK {
 			var responseMessageBytes []byte
@@ -797,7 +885,7 @@ func (rdeManager *RDEManager) RemoveFromVCDResourceSet(ctx context.Context, comp
 		}
 		rde.Entity["status"] = updatedStatus
 
-		_, resp, err = rdeManager.Client.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeManager.ClusterID, clusterOrg.Org.ID, nil)
+		_, resp, err = session.APIClient.DefinedEntityApi.UpdateDefinedEntity(ctx, rde, etag, rdeManager.ClusterID, clusterOrg.Org.ID, nil)
 		if resp != nil {
 			if resp.StatusCode == http.StatusPreconditionFailed {
 				klog.V(4).Infof("etag mismatch [%s] while removing [%s/%s] from VCDResourceSet in RDE [%s]. Retry attempts remaining: [%d]", etag, resourceType, resourceName, rdeManager.ClusterID, i-1)
@@ -44,7 +44,7 @@ func TestCRUDOnEventSet(t *testing.T) {
 	require.NotNil(t, vcdClient, "VCD Client should not be nil")
 	ctx := context.Background()
 
-	org, err := vcdClient.VCDClient.GetOrgByName(vcdConfig.UserOrg)
+	org, err := vcdClient.Session().VCDClient.GetOrgByName(vcdConfig.UserOrg)
 	assert.NoError(t, err, "unable to get org by name for org ", vcdConfig.UserOrg)
 	assert.NotNil(t, org, "org obtained is nil")
 	assert.NotNil(t, org.Org, "org.Org is nil")
@@ -87,7 +87,7 @@ func TestCRUDOnEventSet(t *testing.T) {
 	assert.NoError(t, err, "failed to add event into the eventset")
 
 	// get the rde and check if the length of errors added is same as expected
-	rde, _, _, err := vcdClient.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, org.Org.ID, nil)
+	rde, _, _, err := vcdClient.Session().APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, org.Org.ID, nil)
 	status, _ := rde.Entity["status"].(map[string]interface{})
 	capvcdComponent, _ := status[CAPVCDComponentRDESectionName].(map[string]interface{})
 	eventSet, _ := capvcdComponent["eventSet"].([]interface{})
@@ -97,15 +97,15 @@ func TestCRUDOnEventSet(t *testing.T) {
 	assert.NoError(t, err, "failed to add event into the eventset")
 
 	// get the rde and check if the length of events is still capped at 3- window size (even though 4 events were added)
-	rde, _, _, err = vcdClient.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, org.Org.ID, nil)
+	rde, _, _, err = vcdClient.Session().APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, org.Org.ID, nil)
 	status, _ = rde.Entity["status"].(map[string]interface{})
 	capvcdComponent, _ = status[CAPVCDComponentRDESectionName].(map[string]interface{})
 	eventSet, _ = capvcdComponent["eventSet"].([]interface{})
 	assert.Equal(t, 3, len(eventSet), "Length of error set must match with rollingWindowSize")
 
 	// delete RDE
-	_, _, err = vcdClient.APIClient.DefinedEntityApi.ResolveDefinedEntity(ctx, rdeId, org.Org.ID)
-	_, err = vcdClient.APIClient.DefinedEntityApi.DeleteDefinedEntity(ctx,
+	_, _, err = vcdClient.Session().APIClient.DefinedEntityApi.ResolveDefinedEntity(ctx, rdeId, org.Org.ID)
+	_, err = vcdClient.Session().APIClient.DefinedEntityApi.DeleteDefinedEntity(ctx,
 		rdeId, org.Org.ID, nil)
 	assert.NoError(t, err, "failed to delete rdeId")
 }
@@ -120,7 +120,7 @@ func TestCRUDOnErrorSet(t *testing.T) {
 	require.NotNil(t, vcdClient, "VCD Client should not be nil")
 	ctx := context.Background()
 
-	org, err := vcdClient.VCDClient.GetOrgByName(vcdConfig.UserOrg)
+	org, err := vcdClient.Session().VCDClient.GetOrgByName(vcdConfig.UserOrg)
 	assert.NoError(t, err, "unable to get org by name for org ", vcdConfig.UserOrg)
 	assert.NotNil(t, org, "org obtained is nil")
 	assert.NotNil(t, org.Org, "org.Org is nil")
@@ -173,7 +173,7 @@ func TestCRUDOnErrorSet(t *testing.T) {
 	assert.NoError(t, err, "failed to add error into the errorset")
 
 	// get the rde and check if the length of errors added is same as expected
-	rde, _, _, err := vcdClient.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, org.Org.ID, nil)
+	rde, _, _, err := vcdClient.Session().APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, org.Org.ID, nil)
 	status, _ := rde.Entity["status"].(map[string]interface{})
 	capvcdComponent, _ := status[CAPVCDComponentRDESectionName].(map[string]interface{})
 	errorSet, _ := capvcdComponent["errorSet"].([]interface{})
@@ -188,15 +188,15 @@ func TestCRUDOnErrorSet(t *testing.T) {
 	assert.NoError(t, err, "failed to remove error from the errorset")
 
 	// get the rde and check if the length of the errorSet after removing errors is same as expected
-	rde, _, _, err = vcdClient.APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, org.Org.ID, nil)
+	rde, _, _, err = vcdClient.Session().APIClient.DefinedEntityApi.GetDefinedEntity(ctx, rdeId, org.Org.ID, nil)
 	status, _ = rde.Entity["status"].(map[string]interface{})
 	capvcdComponent, _ = status[CAPVCDComponentRDESectionName].(map[string]interface{})
 	errorSet, _ = capvcdComponent["errorSet"].([]interface{})
 	assert.Equal(t, 0, len(errorSet), "Length of error should be reduced to 0 after removing few errors")
 
 	// delete RDE
-	_, _, err = vcdClient.APIClient.DefinedEntityApi.ResolveDefinedEntity(ctx, rdeId, org.Org.ID)
-	_, err = vcdClient.APIClient.DefinedEntityApi.DeleteDefinedEntity(ctx,
+	_, _, err = vcdClient.Session().APIClient.DefinedEntityApi.ResolveDefinedEntity(ctx, rdeId, org.Org.ID)
+	_, err = vcdClient.Session().APIClient.DefinedEntityApi.DeleteDefinedEntity(ctx,
 		rdeId, org.Org.ID, nil)
 	assert.NoError(t, err, "failed to delete rdeId")
 }
@@ -213,7 +213,7 @@ func createCapvcdRDE(ctx context.Context, vcdClient *Client, clusterName string,
 		},
 	}
 	rde.Entity = entityMap
-	resp, err := vcdClient.APIClient.DefinedEntityApi.CreateDefinedEntity(ctx, *rde, rde.EntityType, orgID, nil)
+	resp, err := vcdClient.Session().APIClient.DefinedEntityApi.CreateDefinedEntity(ctx, *rde, rde.EntityType, orgID, nil)
 	if err != nil {
 		return "", fmt.Errorf("error occurred during RDE creation for the cluster [%s]: [%v]", clusterName, err)
 	}
@@ -221,7 +221,7 @@ func createCapvcdRDE(ctx context.Context, vcdClient *Client, clusterName string,
 		return "", fmt.Errorf("error occurred during RDE creation for the cluster [%s]", clusterName)
 	}
 	taskURL := resp.Header.Get(VCDLocationHeader)
-	task := govcd.NewTask(&vcdClient.VCDClient.Client)
+	task := govcd.NewTask(&vcdClient.Session().VCDClient.Client)
 	task.Task.HREF = taskURL
 	err = task.Refresh()
 	if err != nil {
@@ -532,3 +532,35 @@ func TestRemoveFromCPIVCDResourceSet(t *testing.T) {
 		assert.JSONEqf(t, expectedJson, actualJson, tc.Message)
 	}
 }
+
+func TestGetVCDResourcesFromStatusMap(t *testing.T) {
+
+	statusMap := map[string]interface{}{
+		ComponentCPI: map[string]interface{}{
+			"name":    "ccm",
+			"version": "1.1.1",
+			"vcdResourceSet": []interface{}{
+				map[string]interface{}{
+					"type": VcdResourceVirtualService,
+					"name": "virtual-service-1",
+					"id":   "12345",
+				},
+				map[string]interface{}{
+					"type": VcdResourceAdvertisedPrefix,
+					"name": "10.10.0.0/24",
+				},
+			},
+		},
+	}
+
+	vcdResources, err := GetVCDResourcesFromStatusMap(ComponentCPI, statusMap, VcdResourceAdvertisedPrefix)
+	assert.NoError(t, err, "resources should be read from the status map")
+	assert.Equal(t, []VCDResource{{Type: VcdResourceAdvertisedPrefix, Name: "10.10.0.0/24"}}, vcdResources,
+		"only resources of the type should be returned")
+
+	vcdResources, err = GetVCDResourcesFromStatusMap(ComponentCSI, statusMap, VcdResourceAdvertisedPrefix)
+	assert.NoError(t, err, "absent component should not be an error")
+	assert.Empty(t, vcdResources, "absent component should have no resources")
+
+	return
+}
@@ -1,8 +1,18 @@
 package vcdsdk
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
 )
 
 type VirtualServicePendingError struct {
@@ -84,3 +94,198 @@ func (nre *NoRDEError) Error() string {
 func NewNoRDEError(message string) *NoRDEError {
 	return &NoRDEError{msg: message}
 }
+
+// VCDErrorKind classifies errors of VCD by how the caller should react to them.
+type VCDErrorKind string
+
+const (
+	// VCDErrorUnknown is an error that is not known to resolve without a change
+	VCDErrorUnknown = VCDErrorKind("Unknown")
+	// VCDErrorNotFound is an error for an entity that does not exist
+	VCDErrorNotFound = VCDErrorKind("NotFound")
+	// VCDErrorBusy is an error for an entity that is being modified by another operation, or a conflicting change
+	VCDErrorBusy = VCDErrorKind("Busy")
+	// VCDErrorPending is an error for an entity that is not ready yet
+	VCDErrorPending = VCDErrorKind("Pending")
+	// VCDErrorQuotaExceeded is an error for a request that exceeds a quota or finds no free resource
+	VCDErrorQuotaExceeded = VCDErrorKind("QuotaExceeded")
+	// VCDErrorAuthExpired is an error for a request with an expired or invalidated session
+	VCDErrorAuthExpired = VCDErrorKind("AuthExpired")
+	// VCDErrorTransient is an error of VCD or of the connection to VCD that is expected to resolve on retry
+	VCDErrorTransient = VCDErrorKind("Transient")
+	// VCDErrorTimeout is an error for a task of VCD that did not complete in time
+	VCDErrorTimeout = VCDErrorKind("Timeout")
+)
+
+// VCDError is an error of VCD with its classification.
+type VCDError struct {
+	Kind       VCDErrorKind
+	StatusCode int
+	Err        error
+}
+
+func (vcdError *VCDError) Error() string {
+	return vcdError.Err.Error()
+}
+
+func (vcdError *VCDError) Unwrap() error {
+	return vcdError.Err
+}
+
+// NewVCDError returns err classified as kind.
+func NewVCDError(kind VCDErrorKind, err error) *VCDError {
+	return &VCDError{
+		Kind: kind,
+		Err:  err,
+	}
+}
+
+// NewVCDErrorFromResponse returns err of a request to VCD classified by the status code of resp and err. It returns
+// nil if err is nil.
+func NewVCDErrorFromResponse(err error, resp *http.Response) error {
+	if err == nil {
+		return nil
+	}
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	kind := GetVCDErrorKind(err)
+	if kind == VCDErrorUnknown && statusCode != 0 {
+		kind = getVCDErrorKindFromStatus(statusCode, "", "")
+	}
+	return &VCDError{
+		Kind:       kind,
+		StatusCode: statusCode,
+		Err:        err,
+	}
+}
+
+// minorErrorCodeKinds are the minor error codes of VCD that classify an error independent of its status code
+var minorErrorCodeKinds = map[string]VCDErrorKind{
+	"NOT_FOUND":    VCDErrorNotFound,
+	"BUSY_ENTITY":  VCDErrorBusy,
+	"UNAUTHORIZED": VCDErrorAuthExpired,
+}
+
+func getVCDErrorKindFromStatus(statusCode int, minorErrorCode string, message string) VCDErrorKind {
+	if kind, ok := minorErrorCodeKinds[strings.ToUpper(minorErrorCode)]; ok {
+		return kind
+	}
+	if strings.Contains(strings.ToLower(message), "quota") {
+		return VCDErrorQuotaExceeded
+	}
+	switch {
+	case statusCode == http.StatusNotFound:
+		return VCDErrorNotFound
+	case statusCode == http.StatusConflict:
+		return VCDErrorBusy
+	case statusCode == http.StatusUnauthorized:
+		return VCDErrorAuthExpired
+	case statusCode == http.StatusTooManyRequests:
+		return VCDErrorTransient
+	case statusCode == http.StatusBadGateway, statusCode == http.StatusServiceUnavailable,
+		statusCode == http.StatusGatewayTimeout:
+		return VCDErrorTransient
+	}
+	return VCDErrorUnknown
+}
+
+// GetVCDErrorKind classifies err and the errors it wraps. The VCD errors of the swagger client and govcd are
+// classified by their status and minor error codes; errors that only carry a message are classified by the markers
+// of govcd.
+func GetVCDErrorKind(err error) VCDErrorKind {
+	if err == nil {
+		return VCDErrorUnknown
+	}
+
+	var vcdError *VCDError
+	if errors.As(err, &vcdError) && vcdError.Kind != VCDErrorUnknown {
+		return vcdError.Kind
+	}
+	var taskTimeoutError *TaskTimeoutError
+	if errors.As(err, &taskTimeoutError) {
+		return VCDErrorTimeout
+	}
+	var vsPendingError *VirtualServicePendingError
+	if errors.As(err, &vsPendingError) {
+		return VCDErrorPending
+	}
+	var vsBusyError *VirtualServiceBusyError
+	var lbPoolBusyError *LoadBalancerPoolBusyError
+	var gatewayBusyError *GatewayBusyError
+	if errors.As(err, &vsBusyError) || errors.As(err, &lbPoolBusyError) || errors.As(err, &gatewayBusyError) {
+		return VCDErrorBusy
+	}
+
+	var swaggerError swaggerClient.GenericSwaggerError
+	if errors.As(err, &swaggerError) {
+		// the error of the swagger client is the status of the response, such as "404 Not Found"
+		statusCode, _ := strconv.Atoi(strings.SplitN(swaggerError.Error(), " ", 2)[0])
+		openAPIError := types.OpenApiError{}
+		_ = json.Unmarshal(swaggerError.Body(), &openAPIError)
+		if kind := getVCDErrorKindFromStatus(statusCode, openAPIError.MinorErrorCode,
+			openAPIError.Message); kind != VCDErrorUnknown {
+			return kind
+		}
+	}
+	var openAPIError types.OpenApiError
+	if errors.As(err, &openAPIError) {
+		if kind := getVCDErrorKindFromStatus(0, openAPIError.MinorErrorCode,
+			openAPIError.Message); kind != VCDErrorUnknown {
+			return kind
+		}
+	}
+	var apiError types.Error
+	if errors.As(err, &apiError) {
+		if kind := getVCDErrorKindFromStatus(apiError.MajorErrorCode, apiError.MinorErrorCode,
+			apiError.Message); kind != VCDErrorUnknown {
+			return kind
+		}
+	}
+
+	// errors of govcd are mostly formatted strings
+	message := err.Error()
+	switch {
+	case govcd.ContainsNotFound(err):
+		return VCDErrorNotFound
+	case strings.Contains(message, "BUSY_ENTITY"):
+		return VCDErrorBusy
+	}
+	return VCDErrorUnknown
+}
+
+// TaskTimeoutError is returned if a task of VCD did not complete before the deadline of the context of its caller.
+// The task may still complete in VCD.
+type TaskTimeoutError struct {
+	TaskHREF  string
+	Operation string
+	Progress  int
+	Elapsed   time.Duration
+	Err       error
+}
+
+func (taskTimeoutError *TaskTimeoutError) Error() string {
+	return fmt.Sprintf("task [%s] of operation [%s] did not complete after [%v] at progress [%d%%]: [%v]",
+		taskTimeoutError.TaskHREF, taskTimeoutError.Operation, taskTimeoutError.Elapsed.Round(time.Second),
+		taskTimeoutError.Progress, taskTimeoutError.Err)
+}
+
+func (taskTimeoutError *TaskTimeoutError) Unwrap() error {
+	return taskTimeoutError.Err
+}
+
+// IsNotFoundError returns true if err is classified as an error for an entity that does not exist.
+func IsNotFoundError(err error) bool {
+	return GetVCDErrorKind(err) == VCDErrorNotFound
+}
+
+// IsRetriableError returns true if err is classified as an error that is expected to resolve without a change, such
+// as a busy or pending entity, an expired session or a transient error of VCD.
+func IsRetriableError(err error) bool {
+	switch GetVCDErrorKind(err) {
+	case VCDErrorBusy, VCDErrorPending, VCDErrorAuthExpired, VCDErrorTransient:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+func TestGetVCDErrorKind(t *testing.T) {
+
+	assert.Equal(t, VCDErrorUnknown, GetVCDErrorKind(nil), "nil error should be unknown")
+	assert.Equal(t, VCDErrorUnknown, GetVCDErrorKind(fmt.Errorf("unexpected error")),
+		"plain error should be unknown")
+
+	assert.Equal(t, VCDErrorNotFound, GetVCDErrorKind(fmt.Errorf("unable to get app port profile: [%w]",
+		govcd.ErrorEntityNotFound)), "wrapped govcd error should be not found")
+	assert.Equal(t, VCDErrorNotFound, GetVCDErrorKind(fmt.Errorf("could not find profile: %s",
+		govcd.ErrorEntityNotFound)), "formatted govcd error should be not found")
+
+	assert.Equal(t, VCDErrorBusy, GetVCDErrorKind(fmt.Errorf("update failed: [%w]",
+		NewGatewayBusyError("gateway1"))), "wrapped gateway busy error should be busy")
+	assert.Equal(t, VCDErrorBusy, GetVCDErrorKind(NewLBPoolBusyError("pool1")), "pool busy error should be busy")
+	assert.Equal(t, VCDErrorPending, GetVCDErrorKind(fmt.Errorf("create failed: [%w]",
+		NewVirtualServicePendingError("vs1"))), "wrapped pending error should be pending")
+
+	assert.Equal(t, VCDErrorBusy, GetVCDErrorKind(fmt.Errorf("update failed: [%w]",
+		types.OpenApiError{MinorErrorCode: "BUSY_ENTITY", Message: "entity is busy"})),
+		"open API error with busy minor code should be busy")
+	assert.Equal(t, VCDErrorQuotaExceeded, GetVCDErrorKind(types.OpenApiError{MinorErrorCode: "BAD_REQUEST",
+		Message: "The maximum number of virtual services exceeds the quota"}),
+		"open API error about a quota should be quota exceeded")
+	assert.Equal(t, VCDErrorNotFound, GetVCDErrorKind(types.Error{MajorErrorCode: http.StatusNotFound,
+		Message: "no such vApp"}), "API error with 404 should be not found")
+	assert.Equal(t, VCDErrorAuthExpired, GetVCDErrorKind(types.Error{MajorErrorCode: http.StatusUnauthorized,
+		Message: "session expired"}), "API error with 401 should be auth expired")
+	assert.Equal(t, VCDErrorTransient, GetVCDErrorKind(types.Error{MajorErrorCode: http.StatusServiceUnavailable,
+		Message: "try again"}), "API error with 503 should be transient")
+
+	quotaErr := NewVCDError(VCDErrorQuotaExceeded, fmt.Errorf("no free IP"))
+	assert.Equal(t, VCDErrorQuotaExceeded, GetVCDErrorKind(fmt.Errorf("reserve failed: [%w]", quotaErr)),
+		"wrapped VCD error should keep its kind")
+	assert.Equal(t, "no free IP", quotaErr.Error(), "VCD error should have the message of its error")
+
+	assert.Equal(t, VCDErrorTransient, GetVCDErrorKind(NewVCDErrorFromResponse(fmt.Errorf("502 Bad Gateway"),
+		&http.Response{StatusCode: http.StatusBadGateway})), "error of response with 502 should be transient")
+	assert.Nil(t, NewVCDErrorFromResponse(nil, &http.Response{StatusCode: http.StatusOK}),
+		"nil error should not be classified")
+
+	return
+}
+
+func TestIsRetriableError(t *testing.T) {
+
+	assert.True(t, IsRetriableError(NewVirtualServiceBusyError("vs1")), "busy error should be retriable")
+	assert.True(t, IsRetriableError(NewVCDError(VCDErrorTransient, fmt.Errorf("timeout"))),
+		"transient error should be retriable")
+	assert.False(t, IsRetriableError(NewVCDError(VCDErrorQuotaExceeded, fmt.Errorf("no free IP"))),
+		"quota exceeded error should not be retriable")
+	assert.False(t, IsRetriableError(govcd.ErrorEntityNotFound), "not found error should not be retriable")
+	assert.True(t, IsNotFoundError(govcd.ErrorEntityNotFound), "govcd not found error should be not found")
+
+	return
+}
@@ -11,13 +11,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/antihax/optional"
-	"github.com/peterhellberg/link"
 	"github.com/vmware/cloud-provider-for-cloud-director/pkg/util"
 	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
 	"github.com/vmware/go-vcloud-director/v2/govcd"
 	"github.com/vmware/go-vcloud-director/v2/types/v56"
 	"k8s.io/klog"
-	"math"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -40,13 +38,19 @@ type GatewayManager struct {
 
 // CacheGatewayDetails get gateway reference and cache some details in client object
 func (gm *GatewayManager) cacheGatewayDetails(ctx context.Context, ovdcIdentifier string) error {
+	ctx, span := startSpan(ctx, "GatewayManager.cacheGatewayDetails")
+	defer span.End()
+
 	if gm.NetworkName == "" {
 		return fmt.Errorf("network name should not be empty")
 	}
 
-	ovdcNetwork, err := gm.getOVDCNetwork(ctx, gm.NetworkName, ovdcIdentifier)
+	ovdcNetwork, err := cachedLookup(&gm.Client.lookups, networkLookupKey(ovdcIdentifier, gm.NetworkName),
+		func() (*swaggerClient.VdcNetwork, error) {
+			return gm.getOVDCNetwork(ctx, gm.NetworkName, ovdcIdentifier)
+		})
 	if err != nil {
-		return fmt.Errorf("unable to get OVDC network [%s]: [%v]", gm.NetworkName, err)
+		return fmt.Errorf("unable to get OVDC network [%s]: [%w]", gm.NetworkName, err)
 	}
 
 	// Cache backing type
@@ -87,154 +91,169 @@ func NewGatewayManager(ctx context.Context, client *Client, networkName string,
 
 	err := gateway.cacheGatewayDetails(ctx, Identifier)
 	if err != nil {
-		return nil, fmt.Errorf("error caching gateway related details: [%v]", err)
+		return nil, fmt.Errorf("error caching gateway related details: [%w]", err)
 	}
 	return &gateway, nil
 }
 
 func (gm *GatewayManager) getOVDCNetwork(ctx context.Context, networkName string, ovdcIdentifier string) (*swaggerClient.VdcNetwork, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.getOVDCNetwork")
+	defer span.End()
+
 	if networkName == "" {
 		return nil, fmt.Errorf("network name should not be empty")
 	}
 
 	client := gm.Client
-	ovdcNetworksAPI := client.APIClient.OrgVdcNetworksApi
-	pageNum := int32(1)
+	session := client.Session()
+	ovdcNetworksAPI := session.APIClient.OrgVdcNetworksApi
 	ovdcNetworkID := ""
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
 	}
+	ovdcNetworks, err := listAllPages(pageNumberPagination, 32,
+		func(page pageRequest) ([]swaggerClient.VdcNetwork, int32, *http.Response, error) {
+			ovdcNetworks, resp, err := ovdcNetworksAPI.GetAllVdcNetworks(ctx, org.Org.ID, page.PageNum,
+				page.PageSize, nil)
+			return ovdcNetworks.Values, ovdcNetworks.PageCount, resp, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all ovdc networks: [%w]", err)
+	}
 	networkFound := false
-	for {
-		ovdcNetworks, resp, err := ovdcNetworksAPI.GetAllVdcNetworks(ctx, org.Org.ID, pageNum, 32, nil)
-		if err != nil {
-			// TODO: log resp in debug mode only
-			return nil, fmt.Errorf("unable to get all ovdc networks: [%+v]: [%v]", resp, err)
-		}
-
-		if len(ovdcNetworks.Values) == 0 {
-			break
-		}
-
-		for _, ovdcNetwork := range ovdcNetworks.Values {
-			if ovdcNetwork.Name == gm.NetworkName &&
-				(ovdcNetwork.OrgVdc == nil ||
-					ovdcNetwork.OrgVdc.Name == ovdcIdentifier ||
-					ovdcNetwork.OrgVdc.Id == ovdcIdentifier) {
-				if networkFound {
-					return nil, fmt.Errorf("found more than one network with the name [%s] in the org [%s] - "+
-						"please ensure the network name is unique within an org", gm.NetworkName, client.ClusterOrgName)
-				}
-				ovdcNetworkID = ovdcNetwork.Id
-				networkFound = true
+	for _, ovdcNetwork := range ovdcNetworks {
+		if ovdcNetwork.Name == gm.NetworkName &&
+			(ovdcNetwork.OrgVdc == nil ||
+				ovdcNetwork.OrgVdc.Name == ovdcIdentifier ||
+				ovdcNetwork.OrgVdc.Id == ovdcIdentifier) {
+			if networkFound {
+				return nil, fmt.Errorf("found more than one network with the name [%s] in the org [%s] - "+
+					"please ensure the network name is unique within an org", gm.NetworkName, client.ClusterOrgName)
 			}
+			ovdcNetworkID = ovdcNetwork.Id
+			networkFound = true
 		}
-		pageNum++
 	}
 	if ovdcNetworkID == "" {
 		return nil, fmt.Errorf("unable to obtain ID for ovdc network name [%s]",
 			gm.NetworkName)
 	}
 
-	ovdcNetworkAPI := client.APIClient.OrgVdcNetworkApi
+	ovdcNetworkAPI := session.APIClient.OrgVdcNetworkApi
 	ovdcNetwork, resp, err := ovdcNetworkAPI.GetOrgVdcNetwork(ctx, ovdcNetworkID, org.Org.ID)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get network for id [%s]: [%+v]: [%v]", ovdcNetworkID, resp, err)
+		return nil, fmt.Errorf("unable to get network for id [%s]: [%+v]: [%w]", ovdcNetworkID, resp, err)
 	}
 
 	return &ovdcNetwork, nil
 }
 
 // GetLoadBalancerSEG TODO: There could be a race here as we don't book a slot. Retry repeatedly to get a LB Segment.
+// The service engine group assignments of the gateway are cached, and a slot of the chosen assignment is counted as
+// used in the cache until the assignments are looked up again.
 func (gm *GatewayManager) GetLoadBalancerSEG(ctx context.Context) (*swaggerClient.EntityReference, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.GetLoadBalancerSEG")
+	defer span.End()
+
 	if gm.GatewayRef == nil {
 		return nil, fmt.Errorf("gateway reference should not be nil")
 	}
 
-	client := gm.Client
-	pageNum := int32(1)
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	segAssignmentsKey := segAssignmentsLookupKey(gm.GatewayRef.Id)
+	segAssignments, err := cachedLookup(&gm.Client.lookups, segAssignmentsKey,
+		func() ([]swaggerClient.LoadBalancerServiceEngineGroupAssignment, error) {
+			return gm.getLoadBalancerSEGAssignments(ctx)
+		})
 	if err != nil {
-		return nil, fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
-	}
-	if org == nil || org.Org == nil {
-		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
+		return nil, err
 	}
-	var chosenSEGAssignment *swaggerClient.LoadBalancerServiceEngineGroupAssignment = nil
-	for {
-		segAssignments, resp, err := client.APIClient.LoadBalancerServiceEngineGroupAssignmentsApi.GetServiceEngineGroupAssignments(
-			ctx, pageNum, 25, org.Org.ID,
-			&swaggerClient.LoadBalancerServiceEngineGroupAssignmentsApiGetServiceEngineGroupAssignmentsOpts{
-				Filter: optional.NewString(fmt.Sprintf("gatewayRef.id==%s", gm.GatewayRef.Id)),
-			},
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get service engine group for gateway [%s]: resp: [%v]: [%v]",
-				gm.GatewayRef.Name, resp, err)
-		}
-		if len(segAssignments.Values) == 0 {
-			return nil, fmt.Errorf("obtained no service engine group assignment for gateway [%s]: [%v]", gm.GatewayRef.Name, err)
-		}
 
-		for _, segAssignment := range segAssignments.Values {
-			if segAssignment.NumDeployedVirtualServices < segAssignment.MaxVirtualServices {
-				chosenSEGAssignment = &segAssignment
-				break
-			}
+	var chosenSEGAssignment *swaggerClient.LoadBalancerServiceEngineGroupAssignment = nil
+	for idx, segAssignment := range segAssignments {
+		if segAssignment.ServiceEngineGroupRef != nil {
+			observeSEGUtilization(gm.GatewayRef.Name, segAssignment.ServiceEngineGroupRef.Name,
+				segAssignment.NumDeployedVirtualServices, segAssignment.MaxVirtualServices)
 		}
-		if chosenSEGAssignment != nil {
+		if segAssignment.NumDeployedVirtualServices < segAssignment.MaxVirtualServices {
+			chosenSEGAssignment = &segAssignments[idx]
 			break
 		}
-
-		pageNum++
 	}
 
 	if chosenSEGAssignment == nil {
-		return nil, fmt.Errorf("unable to find service engine group with free instances")
-	}
+		gm.Client.lookups.invalidate(segAssignmentsKey)
+		return nil, NewVCDError(VCDErrorQuotaExceeded,
+			fmt.Errorf("unable to find service engine group with free instances"))
+	}
+
+	chosenSEGAssignmentID := chosenSEGAssignment.Id
+	gm.Client.lookups.update(segAssignmentsKey, func(value interface{}) interface{} {
+		cachedSEGAssignments, ok := value.([]swaggerClient.LoadBalancerServiceEngineGroupAssignment)
+		if !ok {
+			return value
+		}
+		updatedSEGAssignments := make([]swaggerClient.LoadBalancerServiceEngineGroupAssignment,
+			len(cachedSEGAssignments))
+		copy(updatedSEGAssignments, cachedSEGAssignments)
+		for idx := range updatedSEGAssignments {
+			if updatedSEGAssignments[idx].Id == chosenSEGAssignmentID {
+				updatedSEGAssignments[idx].NumDeployedVirtualServices++
+			}
+		}
+		return updatedSEGAssignments
+	})
 
 	klog.Infof("Using service engine group [%v] on gateway [%v]\n", chosenSEGAssignment.ServiceEngineGroupRef, gm.GatewayRef.Name)
 
 	return chosenSEGAssignment.ServiceEngineGroupRef, nil
 }
 
-func getCursor(resp *http.Response) (string, error) {
-	cursorURI := ""
-	for _, linklet := range resp.Header["Link"] {
-		for _, l := range link.Parse(linklet) {
-			if l.Rel == "nextPage" {
-				cursorURI = l.URI
-				break
-			}
-		}
-		if cursorURI != "" {
-			break
-		}
-	}
-	if cursorURI == "" {
-		return "", nil
+// invalidateLoadBalancerSEGAssignments drops the cached service engine group assignments of the gateway, whose numbers
+// of deployed virtual services are no longer known.
+func (gm *GatewayManager) invalidateLoadBalancerSEGAssignments() {
+	if gm.GatewayRef != nil {
+		gm.Client.lookups.invalidate(segAssignmentsLookupKey(gm.GatewayRef.Id))
 	}
+}
 
-	u, err := url.Parse(cursorURI)
+// getLoadBalancerSEGAssignments returns all service engine group assignments of the gateway. The assignments are read
+// page by page until the last page, so that a gateway with more assignments than fit on one page is not reported as
+// having none when a later page is empty. A gateway without any assignment is an error.
+func (gm *GatewayManager) getLoadBalancerSEGAssignments(ctx context.Context) (
+	[]swaggerClient.LoadBalancerServiceEngineGroupAssignment, error) {
+
+	client := gm.Client
+	session := client.Session()
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return "", fmt.Errorf("unable to parse cursor URI [%s]: [%v]", cursorURI, err)
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
-
-	cursorStr := ""
-	keyMap, err := url.ParseQuery(u.RawQuery)
+	if org == nil || org.Org == nil {
+		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
+	}
+	segAssignmentList, err := listAllPages(pageNumberPagination, DefaultPageSize,
+		func(page pageRequest) ([]swaggerClient.LoadBalancerServiceEngineGroupAssignment, int32, *http.Response, error) {
+			segAssignments, resp, err := session.APIClient.LoadBalancerServiceEngineGroupAssignmentsApi.GetServiceEngineGroupAssignments(
+				ctx, page.PageNum, page.PageSize, org.Org.ID,
+				&swaggerClient.LoadBalancerServiceEngineGroupAssignmentsApiGetServiceEngineGroupAssignmentsOpts{
+					Filter: optional.NewString(fmt.Sprintf("gatewayRef.id==%s", gm.GatewayRef.Id)),
+				},
+			)
+			return segAssignments.Values, segAssignments.PageCount, resp, err
+		})
 	if err != nil {
-		return "", fmt.Errorf("unable to parse raw query [%s]: [%v]", u.RawQuery, err)
+		return nil, fmt.Errorf("unable to get service engine group for gateway [%s]: [%w]",
+			gm.GatewayRef.Name, err)
 	}
-
-	if cursorStrList, ok := keyMap["cursor"]; ok {
-		cursorStr = cursorStrList[0]
+	if len(segAssignmentList) == 0 {
+		return nil, fmt.Errorf("obtained no service engine group assignment for gateway [%s]", gm.GatewayRef.Name)
 	}
 
-	return cursorStr, nil
+	return segAssignmentList, nil
 }
 
 type NatRuleRef struct {
@@ -249,40 +268,47 @@ type NatRuleRef struct {
 
 // GetNATRuleRef returns nil if the rule is not found;
 func (gm *GatewayManager) GetNATRuleRef(ctx context.Context, natRuleName string) (*NatRuleRef, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.GetNATRuleRef")
+	defer span.End()
 
 	if gm.GatewayRef == nil {
 		return nil, fmt.Errorf("gateway reference should not be nil")
 	}
 	client := gm.Client
+	session := client.Session()
 	var natRuleRef *NatRuleRef = nil
-	cursor := optional.EmptyString()
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
 	}
-	for {
-		natRules, resp, err := client.APIClient.EdgeGatewayNatRulesApi.GetNatRules(
-			ctx, 128, gm.GatewayRef.Id, org.Org.ID,
-			&swaggerClient.EdgeGatewayNatRulesApiGetNatRulesOpts{
-				Cursor: cursor,
-			})
+	natRulePages := newPageIterator(cursorPagination, MaxPageSize,
+		func(page pageRequest) ([]swaggerClient.EdgeNatRule, int32, *http.Response, error) {
+			natRules, resp, err := session.APIClient.EdgeGatewayNatRulesApi.GetNatRules(
+				ctx, page.PageSize, gm.GatewayRef.Id, org.Org.ID,
+				&swaggerClient.EdgeGatewayNatRulesApiGetNatRulesOpts{
+					Cursor: page.Cursor,
+				})
+			return natRules.Values, 0, resp, err
+		})
+	for natRuleRef == nil {
+		natRules, ok, err := natRulePages.nextPage()
 		if err != nil {
-			return nil, fmt.Errorf("unable to get nat rules: resp: [%+v]: [%v]", resp, err)
+			return nil, fmt.Errorf("unable to get nat rules: [%w]", err)
 		}
-		if len(natRules.Values) == 0 {
+		if !ok {
 			break
 		}
 
-		for _, rule := range natRules.Values {
+		for _, rule := range natRules {
 			if rule.Name == natRuleName {
 				externalPort := 0
 				if rule.DnatExternalPort != "" {
 					externalPort, err = strconv.Atoi(rule.DnatExternalPort)
 					if err != nil {
-						return nil, fmt.Errorf("unable to convert external port [%s] to int: [%v]",
+						return nil, fmt.Errorf("unable to convert external port [%s] to int: [%w]",
 							rule.DnatExternalPort, err)
 					}
 				}
@@ -291,7 +317,7 @@ func (gm *GatewayManager) GetNATRuleRef(ctx context.Context, natRuleName string)
 				if rule.InternalPort != "" {
 					internalPort, err = strconv.Atoi(rule.InternalPort)
 					if err != nil {
-						return nil, fmt.Errorf("unable to convert internal port [%s] to int: [%v]",
+						return nil, fmt.Errorf("unable to convert internal port [%s] to int: [%w]",
 							rule.InternalPort, err)
 					}
 				}
@@ -308,18 +334,6 @@ func (gm *GatewayManager) GetNATRuleRef(ctx context.Context, natRuleName string)
 				break
 			}
 		}
-		if natRuleRef != nil {
-			break
-		}
-
-		cursorStr, err := getCursor(resp)
-		if err != nil {
-			return nil, fmt.Errorf("error while parsing response [%+v]: [%v]", resp, err)
-		}
-		if cursorStr == "" {
-			break
-		}
-		cursor = optional.NewString(cursorStr)
 	}
 
 	if natRuleRef == nil {
@@ -329,6 +343,35 @@ func (gm *GatewayManager) GetNATRuleRef(ctx context.Context, natRuleName string)
 	return natRuleRef, nil
 }
 
+// listNATRules returns all NAT rules of the gateway.
+func (gm *GatewayManager) listNATRules(ctx context.Context) ([]swaggerClient.EdgeNatRule, error) {
+	if gm.GatewayRef == nil {
+		return nil, fmt.Errorf("gateway reference should not be nil")
+	}
+	client := gm.Client
+	session := client.Session()
+	org, err := client.GetOrgByName(client.ClusterOrgName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
+	}
+	if org == nil || org.Org == nil {
+		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
+	}
+	natRules, err := listAllPages(cursorPagination, MaxPageSize,
+		func(page pageRequest) ([]swaggerClient.EdgeNatRule, int32, *http.Response, error) {
+			natRules, resp, err := session.APIClient.EdgeGatewayNatRulesApi.GetNatRules(
+				ctx, page.PageSize, gm.GatewayRef.Id, org.Org.ID,
+				&swaggerClient.EdgeGatewayNatRulesApiGetNatRulesOpts{
+					Cursor: page.Cursor,
+				})
+			return natRules.Values, 0, resp, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get nat rules of gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
+	return natRules, nil
+}
+
 func GetDNATRuleName(virtualServiceName string) string {
 	return fmt.Sprintf("dnat-%s", virtualServiceName)
 }
@@ -339,15 +382,16 @@ func GetAppPortProfileName(dnatRuleName string) string {
 
 func (gm *GatewayManager) CreateAppPortProfile(appPortProfileName string, externalPort int32) (*govcd.NsxtAppPortProfile, error) {
 	client := gm.Client
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("unable to find org [%s] by name: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("unable to find org [%s] by name: [%w]", client.ClusterOrgName, err)
 	}
 
 	klog.Infof("Verifying if app port profile [%s] exists in org [%s]...", appPortProfileName,
 		client.ClusterOrgName)
 	// we always use tenant scoped profiles
-	contextEntityID := client.VDC.Vdc.ID
+	contextEntityID := session.VDC.Vdc.ID
 	scope := types.ApplicationPortProfileScopeTenant
 	appPortProfile, err := org.GetNsxtAppPortProfileByName(appPortProfileName, scope)
 	if err != nil && !strings.Contains(err.Error(), govcd.ErrorEntityNotFound.Error()) {
@@ -388,7 +432,7 @@ func (gm *GatewayManager) CreateAppPortProfile(appPortProfileName string, extern
 		client.ClusterOrgName)
 	appPortProfile, err = org.CreateNsxtAppPortProfile(appPortProfileConfig)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create nsxt app port profile with config [%#v]: [%v]",
+		return nil, fmt.Errorf("unable to create nsxt app port profile with config [%#v]: [%w]",
 			appPortProfileConfig, err)
 	}
 
@@ -399,15 +443,23 @@ func (gm *GatewayManager) CreateAppPortProfile(appPortProfileName string, extern
 
 func (gm *GatewayManager) CreateDNATRule(ctx context.Context, dnatRuleName string,
 	externalIP string, internalIP string, externalPort int32, internalPort int32, appPortProfile *govcd.NsxtAppPortProfile) error {
+	ctx, span := startSpan(ctx, "GatewayManager.CreateDNATRule")
+	defer span.End()
 
 	if gm.GatewayRef == nil {
 		return fmt.Errorf("gateway reference should not be nil")
 	}
+	unlockGateway, err := gm.Client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return err
+	}
+	defer unlockGateway()
 
 	client := gm.Client
+	session := client.Session()
 	dnatRuleRef, err := gm.GetNATRuleRef(ctx, dnatRuleName)
 	if err != nil {
-		return fmt.Errorf("unexpected error while looking for nat rule [%s] in gateway [%s]: [%v]",
+		return fmt.Errorf("unexpected error while looking for nat rule [%s] in gateway [%s]: [%w]",
 			dnatRuleName, gm.GatewayRef.Name, err)
 	}
 	if dnatRuleRef != nil {
@@ -419,9 +471,9 @@ func (gm *GatewayManager) CreateDNATRule(ctx context.Context, dnatRuleName strin
 		return fmt.Errorf("empty app port profile")
 	}
 
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
@@ -439,9 +491,9 @@ func (gm *GatewayManager) CreateDNATRule(ctx context.Context, dnatRuleName strin
 			Id:   appPortProfile.NsxtAppPortProfile.ID,
 		},
 	}
-	resp, err := client.APIClient.EdgeGatewayNatRulesApi.CreateNatRule(ctx, edgeNatRule, gm.GatewayRef.Id, org.Org.ID)
+	resp, err := session.APIClient.EdgeGatewayNatRulesApi.CreateNatRule(ctx, edgeNatRule, gm.GatewayRef.Id, org.Org.ID)
 	if err != nil {
-		return fmt.Errorf("unable to create dnat rule [%s]: [%s:%d]=>[%s:%d]: [%v]", dnatRuleName,
+		return fmt.Errorf("unable to create dnat rule [%s]: [%s:%d]=>[%s:%d]: [%w]", dnatRuleName,
 			externalIP, externalPort, internalIP, internalPort, err)
 	}
 	if resp != nil && resp.StatusCode != http.StatusAccepted {
@@ -449,21 +501,21 @@ func (gm *GatewayManager) CreateDNATRule(ctx context.Context, dnatRuleName strin
 			"unable to create dnat rule [%s]: [%s]=>[%s]; expected http response [%v], obtained [%v]: [%v]",
 			dnatRuleName, externalIP, internalIP, http.StatusAccepted, resp.StatusCode, err)
 	} else if err != nil {
-		return fmt.Errorf("unable to create dnat rule [%s]: [%s:%d]=>[%s:%d]: [%v]", dnatRuleName,
+		return fmt.Errorf("unable to create dnat rule [%s]: [%s:%d]=>[%s:%d]: [%w]", dnatRuleName,
 			externalIP, externalPort, internalIP, internalPort, err)
 	}
 
 	taskURL := resp.Header.Get("Location")
-	task := govcd.NewTask(&client.VCDClient.Client)
+	task := govcd.NewTask(&session.VCDClient.Client)
 	task.Task.HREF = taskURL
-	if err = task.WaitTaskCompletion(); err != nil {
-		return fmt.Errorf("unable to create dnat rule [%s]: [%s]=>[%s]; creation task [%s] did not complete: [%v]",
+	if err = waitTaskCompletion(ctx, task); err != nil {
+		return fmt.Errorf("unable to create dnat rule [%s]: [%s]=>[%s]; creation task [%s] did not complete: [%w]",
 			dnatRuleName, externalIP, internalIP, taskURL, err)
 	}
 
 	dnatRuleRef, err = gm.GetNATRuleRef(ctx, dnatRuleName)
 	if err != nil {
-		return fmt.Errorf("unexpected error while looking for nat rule [%s] after creating it in gateway [%s]: [%v]",
+		return fmt.Errorf("unexpected error while looking for nat rule [%s] after creating it in gateway [%s]: [%w]",
 			dnatRuleName, gm.GatewayRef.Name, err)
 	}
 	if dnatRuleRef == nil {
@@ -478,9 +530,9 @@ func (gm *GatewayManager) CreateDNATRule(ctx context.Context, dnatRuleName strin
 
 func (gm *GatewayManager) UpdateAppPortProfile(appPortProfileName string, externalPort int32) (*govcd.NsxtAppPortProfile, error) {
 	client := gm.Client
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("unable to find org [%s] by name: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("unable to find org [%s] by name: [%w]", client.ClusterOrgName, err)
 	}
 	appPortProfile, err := org.GetNsxtAppPortProfileByName(appPortProfileName, types.ApplicationPortProfileScopeTenant)
 	if err != nil {
@@ -505,19 +557,32 @@ func (gm *GatewayManager) UpdateAppPortProfile(appPortProfileName string, extern
 }
 
 func (gm *GatewayManager) UpdateDNATRule(ctx context.Context, dnatRuleName string, externalIP string, internalIP string, externalPort int32) (*NatRuleRef, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.UpdateDNATRule")
+	defer span.End()
+
+	if gm.GatewayRef == nil {
+		return nil, fmt.Errorf("gateway reference should not be nil")
+	}
+	unlockGateway, err := gm.Client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockGateway()
+
 	client := gm.Client
+	session := client.Session()
 	if err := gm.checkIfGatewayIsReady(ctx); err != nil {
 		klog.Errorf("failed to update DNAT rule; gateway [%s] is busy", gm.GatewayRef.Name)
 		return nil, err
 	}
 	dnatRuleRef, err := gm.GetNATRuleRef(ctx, dnatRuleName)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error while looking for nat rule [%s] in gateway [%s]: [%v]",
+		return nil, fmt.Errorf("unexpected error while looking for nat rule [%s] in gateway [%s]: [%w]",
 			dnatRuleName, gm.GatewayRef.Name, err)
 	}
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
@@ -525,7 +590,7 @@ func (gm *GatewayManager) UpdateDNATRule(ctx context.Context, dnatRuleName strin
 	if dnatRuleRef == nil {
 		return nil, fmt.Errorf("failed to get DNAT rule name [%s]", dnatRuleName)
 	}
-	dnatRule, resp, err := client.APIClient.EdgeGatewayNatRuleApi.GetNatRule(ctx, gm.GatewayRef.Id, dnatRuleRef.ID, org.Org.ID)
+	dnatRule, resp, err := session.APIClient.EdgeGatewayNatRuleApi.GetNatRule(ctx, gm.GatewayRef.Id, dnatRuleRef.ID, org.Org.ID)
 	if resp != nil && resp.StatusCode != http.StatusOK {
 		var responseMessageBytes []byte
 		if gsErr, ok := err.(swaggerClient.GenericSwaggerError); ok {
@@ -535,7 +600,7 @@ func (gm *GatewayManager) UpdateDNATRule(ctx context.Context, dnatRuleName strin
 			"unable to get DNAT rule [%s]; expected http response [%v], obtained [%v]: resp: [%#v]: [%v]",
 			dnatRuleRef.Name, http.StatusOK, resp.StatusCode, string(responseMessageBytes), err)
 	} else if err != nil {
-		return nil, fmt.Errorf("error while getting DNAT rule [%s]: [%v]", dnatRuleRef.Name, err)
+		return nil, fmt.Errorf("error while getting DNAT rule [%s]: [%w]", dnatRuleRef.Name, err)
 	}
 
 	if dnatRule.ExternalAddresses == externalIP &&
@@ -547,7 +612,7 @@ func (gm *GatewayManager) UpdateDNATRule(ctx context.Context, dnatRuleName strin
 	dnatRule.ExternalAddresses = externalIP
 	dnatRule.InternalAddresses = internalIP
 	dnatRule.DnatExternalPort = fmt.Sprintf("%d", externalPort)
-	resp, err = client.APIClient.EdgeGatewayNatRuleApi.UpdateNatRule(ctx, dnatRule, gm.GatewayRef.Id, dnatRuleRef.ID, org.Org.ID)
+	resp, err = session.APIClient.EdgeGatewayNatRuleApi.UpdateNatRule(ctx, dnatRule, gm.GatewayRef.Id, dnatRuleRef.ID, org.Org.ID)
 	if resp != nil && resp.StatusCode != http.StatusAccepted {
 		var responseMessageBytes []byte
 		if gsErr, ok := err.(swaggerClient.GenericSwaggerError); ok {
@@ -557,19 +622,19 @@ func (gm *GatewayManager) UpdateDNATRule(ctx context.Context, dnatRuleName strin
 			"unable to update DNAT rule [%s]; expected http response [%v], obtained [%v]: resp: [%#v]: [%v]",
 			dnatRuleRef.Name, http.StatusAccepted, resp.StatusCode, string(responseMessageBytes), err)
 	} else if err != nil {
-		return nil, fmt.Errorf("error while updating DNAT rule [%s]: [%v]", dnatRuleRef.Name, err)
+		return nil, fmt.Errorf("error while updating DNAT rule [%s]: [%w]", dnatRuleRef.Name, err)
 	}
 	taskURL := resp.Header.Get("Location")
-	task := govcd.NewTask(&client.VCDClient.Client)
+	task := govcd.NewTask(&session.VCDClient.Client)
 	task.Task.HREF = taskURL
-	if err = task.WaitTaskCompletion(); err != nil {
-		return nil, fmt.Errorf("unable to delete dnat rule [%s]: deletion task [%s] did not complete: [%v]",
+	if err = waitTaskCompletion(ctx, task); err != nil {
+		return nil, fmt.Errorf("unable to delete dnat rule [%s]: deletion task [%s] did not complete: [%w]",
 			dnatRuleName, taskURL, err)
 	}
 
 	dnatRuleRef, err = gm.GetNATRuleRef(ctx, dnatRuleName)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error while looking for nat rule [%s] in gateway [%s]: [%v]",
+		return nil, fmt.Errorf("unexpected error while looking for nat rule [%s] in gateway [%s]: [%w]",
 			dnatRuleName, gm.GatewayRef.Name, err)
 	}
 	if dnatRuleRef == nil {
@@ -587,9 +652,9 @@ func (gm *GatewayManager) DeleteAppPortProfile(appPortProfileName string, failIf
 	klog.Infof("Checking if App Port Profile [%s] in org [%s] exists", appPortProfileName,
 		client.ClusterOrgName)
 
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return fmt.Errorf("unable to find org [%s] by name: [%v]", client.ClusterOrgName, err)
+		return fmt.Errorf("unable to find org [%s] by name: [%w]", client.ClusterOrgName, err)
 	}
 
 	// we always use tenant scoped profiles
@@ -616,7 +681,7 @@ func (gm *GatewayManager) DeleteAppPortProfile(appPortProfileName string, failIf
 		klog.Infof("Deleting App Port Profile [%s] in org [%s]", appPortProfileName,
 			client.ClusterOrgName)
 		if err = appPortProfile.Delete(); err != nil {
-			return fmt.Errorf("unable to delete application port profile [%s]: [%v]", appPortProfileName, err)
+			return fmt.Errorf("unable to delete application port profile [%s]: [%w]", appPortProfileName, err)
 		}
 	}
 	return nil
@@ -626,8 +691,11 @@ func (gm *GatewayManager) DeleteAppPortProfile(appPortProfileName string, failIf
 // even if we don't find a DNAT rule, to ensure that everything is cleaned up.
 func (gm *GatewayManager) DeleteDNATRule(ctx context.Context, dnatRuleName string,
 	failIfAbsent bool) error {
+	ctx, span := startSpan(ctx, "GatewayManager.DeleteDNATRule")
+	defer span.End()
 
 	client := gm.Client
+	session := client.Session()
 	if err := gm.checkIfGatewayIsReady(ctx); err != nil {
 		klog.Errorf("failed to update DNAT rule; gateway [%s] is busy", gm.GatewayRef.Name)
 		return err
@@ -636,17 +704,22 @@ func (gm *GatewayManager) DeleteDNATRule(ctx context.Context, dnatRuleName strin
 	if gm.GatewayRef == nil {
 		return fmt.Errorf("gateway reference should not be nil")
 	}
+	unlockGateway, err := gm.Client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return err
+	}
+	defer unlockGateway()
 
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
 	}
 	dnatRuleRef, err := gm.GetNATRuleRef(ctx, dnatRuleName)
 	if err != nil {
-		return fmt.Errorf("unexpected error while finding dnat rule [%s]: [%v]", dnatRuleName, err)
+		return fmt.Errorf("unexpected error while finding dnat rule [%s]: [%w]", dnatRuleName, err)
 	}
 	if dnatRuleRef == nil {
 		if failIfAbsent {
@@ -655,7 +728,7 @@ func (gm *GatewayManager) DeleteDNATRule(ctx context.Context, dnatRuleName strin
 
 		klog.Infof("DNAT rule [%s] does not exist", dnatRuleName)
 	} else {
-		resp, err := client.APIClient.EdgeGatewayNatRuleApi.DeleteNatRule(ctx,
+		resp, err := session.APIClient.EdgeGatewayNatRuleApi.DeleteNatRule(ctx,
 			gm.GatewayRef.Id, dnatRuleRef.ID, org.Org.ID)
 		if resp.StatusCode != http.StatusAccepted {
 			var responseMessageBytes []byte
@@ -667,10 +740,10 @@ func (gm *GatewayManager) DeleteDNATRule(ctx context.Context, dnatRuleName strin
 		}
 
 		taskURL := resp.Header.Get("Location")
-		task := govcd.NewTask(&client.VCDClient.Client)
+		task := govcd.NewTask(&session.VCDClient.Client)
 		task.Task.HREF = taskURL
-		if err = task.WaitTaskCompletion(); err != nil {
-			return fmt.Errorf("unable to delete dnat rule [%s]: deletion task [%s] did not complete: [%v]",
+		if err = waitTaskCompletion(ctx, task); err != nil {
+			return fmt.Errorf("unable to delete dnat rule [%s]: deletion task [%s] did not complete: [%w]",
 				dnatRuleName, taskURL, err)
 		}
 		klog.Infof("Deleted DNAT rule [%s] on gateway [%s]\n", dnatRuleName, gm.GatewayRef.Name)
@@ -680,42 +753,51 @@ func (gm *GatewayManager) DeleteDNATRule(ctx context.Context, dnatRuleName strin
 
 func (gm *GatewayManager) getLoadBalancerPoolSummary(ctx context.Context,
 	lbPoolName string) (*swaggerClient.EdgeLoadBalancerPoolSummary, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.getLoadBalancerPoolSummary")
+	defer span.End()
+
 	if gm.GatewayRef == nil {
 		return nil, fmt.Errorf("gateway reference should not be nil")
 	}
 
 	client := gm.Client
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
 	}
-	// This should return exactly one result, so no need to accumulate results
-	lbPoolSummaries, resp, err := client.APIClient.EdgeGatewayLoadBalancerPoolsApi.GetPoolSummariesForGateway(
-		ctx, 1, 25, gm.GatewayRef.Id, org.Org.ID,
-		&swaggerClient.EdgeGatewayLoadBalancerPoolsApiGetPoolSummariesForGatewayOpts{
-			Filter: optional.NewString(fmt.Sprintf("name==%s", lbPoolName)),
-		},
-	)
+	// This should return exactly one result, but all pages are read so that duplicates are not missed
+	lbPoolSummaries, err := listAllPages(pageNumberPagination, DefaultPageSize,
+		func(page pageRequest) ([]swaggerClient.EdgeLoadBalancerPoolSummary, int32, *http.Response, error) {
+			lbPoolSummaries, resp, err := session.APIClient.EdgeGatewayLoadBalancerPoolsApi.GetPoolSummariesForGateway(
+				ctx, page.PageNum, page.PageSize, gm.GatewayRef.Id, org.Org.ID,
+				&swaggerClient.EdgeGatewayLoadBalancerPoolsApiGetPoolSummariesForGatewayOpts{
+					Filter: optional.NewString(fmt.Sprintf("name==%s", lbPoolName)),
+				},
+			)
+			return lbPoolSummaries.Values, lbPoolSummaries.PageCount, resp, err
+		})
 	if err != nil {
-		return nil, fmt.Errorf("unable to get reference for LB pool [%s]: [%+v]: [%v]",
-			lbPoolName, resp, err)
+		return nil, fmt.Errorf("unable to get reference for LB pool [%s]: [%w]", lbPoolName, err)
 	}
-	if len(lbPoolSummaries.Values) != 1 {
+	if len(lbPoolSummaries) != 1 {
 		return nil, nil // this is not an error
 	}
 
-	return &lbPoolSummaries.Values[0], nil
+	return &lbPoolSummaries[0], nil
 }
 
 func (gm *GatewayManager) getLoadBalancerPool(ctx context.Context,
 	lbPoolName string) (*swaggerClient.EntityReference, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.getLoadBalancerPool")
+	defer span.End()
 
 	lbPoolSummary, err := gm.getLoadBalancerPoolSummary(ctx, lbPoolName)
 	if err != nil {
-		return nil, fmt.Errorf("error when getting LB Pool: [%v]", err)
+		return nil, fmt.Errorf("error when getting LB Pool: [%w]", err)
 	}
 	if lbPoolSummary == nil {
 		return nil, nil // this is not an error
@@ -757,15 +839,23 @@ func (gm *GatewayManager) formLoadBalancerPool(lbPoolName string, ips []string,
 
 func (gm *GatewayManager) CreateLoadBalancerPool(ctx context.Context, lbPoolName string, lbPoolIPList []string,
 	internalPort int32, protocol string) (*swaggerClient.EntityReference, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.CreateLoadBalancerPool")
+	defer span.End()
 
 	client := gm.Client
+	session := client.Session()
 	if gm.GatewayRef == nil {
 		return nil, fmt.Errorf("gateway reference should not be nil")
 	}
+	unlockGateway, err := gm.Client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockGateway()
 
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
@@ -773,7 +863,7 @@ func (gm *GatewayManager) CreateLoadBalancerPool(ctx context.Context, lbPoolName
 
 	lbPoolRef, err := gm.getLoadBalancerPool(ctx, lbPoolName)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error when querying for pool [%s]: [%v]",
+		return nil, fmt.Errorf("unexpected error when querying for pool [%s]: [%w]",
 			lbPoolName, err)
 	}
 	if lbPoolRef != nil {
@@ -787,10 +877,10 @@ func (gm *GatewayManager) CreateLoadBalancerPool(ctx context.Context, lbPoolName
 	}
 	lbPoolUniqueIPList := util.NewSet(lbPoolIPList).GetElements()
 	lbPool, lbPoolMembers := gm.formLoadBalancerPool(lbPoolName, lbPoolUniqueIPList, internalPort, healthMonitor)
-	resp, err := client.APIClient.EdgeGatewayLoadBalancerPoolsApi.CreateLoadBalancerPool(ctx, lbPool, org.Org.ID)
+	resp, err := session.APIClient.EdgeGatewayLoadBalancerPoolsApi.CreateLoadBalancerPool(ctx, lbPool, org.Org.ID)
 
 	if err != nil {
-		return nil, fmt.Errorf("unable to create loadbalancer pool with name [%s], members [%+v]: resp [%+v]: [%v]",
+		return nil, fmt.Errorf("unable to create loadbalancer pool with name [%s], members [%+v]: resp [%+v]: [%w]",
 			lbPoolName, lbPoolMembers, resp, err)
 	}
 	if resp.StatusCode != http.StatusAccepted {
@@ -799,17 +889,17 @@ func (gm *GatewayManager) CreateLoadBalancerPool(ctx context.Context, lbPoolName
 	}
 
 	taskURL := resp.Header.Get("Location")
-	task := govcd.NewTask(&client.VCDClient.Client)
+	task := govcd.NewTask(&session.VCDClient.Client)
 	task.Task.HREF = taskURL
-	if err = task.WaitTaskCompletion(); err != nil {
-		return nil, fmt.Errorf("unable to create loadbalancer pool; creation task [%s] did not complete: [%v]",
+	if err = waitTaskCompletion(ctx, task); err != nil {
+		return nil, fmt.Errorf("unable to create loadbalancer pool; creation task [%s] did not complete: [%w]",
 			taskURL, err)
 	}
 
 	// Get the pool to return it
 	lbPoolRef, err = gm.getLoadBalancerPool(ctx, lbPoolName)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error when querying for pool [%s]: [%v]",
+		return nil, fmt.Errorf("unexpected error when querying for pool [%s]: [%w]",
 			lbPoolName, err)
 	}
 	if lbPoolRef == nil {
@@ -824,15 +914,23 @@ func (gm *GatewayManager) CreateLoadBalancerPool(ctx context.Context, lbPoolName
 
 func (gm *GatewayManager) DeleteLoadBalancerPool(ctx context.Context, lbPoolName string,
 	failIfAbsent bool) error {
+	ctx, span := startSpan(ctx, "GatewayManager.DeleteLoadBalancerPool")
+	defer span.End()
 
 	client := gm.Client
+	session := client.Session()
 	if gm.GatewayRef == nil {
 		return fmt.Errorf("gateway reference should not be nil")
 	}
+	unlockGateway, err := gm.Client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return err
+	}
+	defer unlockGateway()
 
 	lbPoolRef, err := gm.getLoadBalancerPool(ctx, lbPoolName)
 	if err != nil {
-		return fmt.Errorf("unexpected error in retrieving loadbalancer pool [%s]: [%v]",
+		return fmt.Errorf("unexpected error in retrieving loadbalancer pool [%s]: [%w]",
 			lbPoolName, err)
 	}
 	if lbPoolRef == nil {
@@ -842,9 +940,9 @@ func (gm *GatewayManager) DeleteLoadBalancerPool(ctx context.Context, lbPoolName
 
 		return nil
 	}
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
@@ -854,17 +952,17 @@ func (gm *GatewayManager) DeleteLoadBalancerPool(ctx context.Context, lbPoolName
 		return err
 	}
 
-	resp, err := client.APIClient.EdgeGatewayLoadBalancerPoolApi.DeleteLoadBalancerPool(ctx, lbPoolRef.Id, org.Org.ID)
+	resp, err := session.APIClient.EdgeGatewayLoadBalancerPoolApi.DeleteLoadBalancerPool(ctx, lbPoolRef.Id, org.Org.ID)
 	if resp.StatusCode != http.StatusAccepted {
 		return fmt.Errorf("unable to delete lb pool; expected http response [%v], obtained [%v]",
 			http.StatusAccepted, resp.StatusCode)
 	}
 
 	taskURL := resp.Header.Get("Location")
-	task := govcd.NewTask(&client.VCDClient.Client)
+	task := govcd.NewTask(&session.VCDClient.Client)
 	task.Task.HREF = taskURL
-	if err = task.WaitTaskCompletion(); err != nil {
-		return fmt.Errorf("unable to delete lb pool; deletion task [%s] did not complete: [%v]",
+	if err = waitTaskCompletion(ctx, task); err != nil {
+		return fmt.Errorf("unable to delete lb pool; deletion task [%s] did not complete: [%w]",
 			taskURL, err)
 	}
 	klog.Infof("Deleted loadbalancer pool [%s]\n", lbPoolName)
@@ -890,26 +988,39 @@ func hasSameLBPoolMembers(array1 []swaggerClient.EdgeLoadBalancerPoolMember, arr
 
 func (gm *GatewayManager) UpdateLoadBalancerPool(ctx context.Context, lbPoolName string, lbPoolIPList []string,
 	internalPort int32, protocol string) (*swaggerClient.EntityReference, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.UpdateLoadBalancerPool")
+	defer span.End()
+
+	if gm.GatewayRef == nil {
+		return nil, fmt.Errorf("gateway reference should not be nil")
+	}
+	unlockGateway, err := gm.Client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockGateway()
+
 	client := gm.Client
+	session := client.Session()
 	lbPoolRef, err := gm.getLoadBalancerPool(ctx, lbPoolName)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error when querying for pool [%s]: [%v]", lbPoolName, err)
+		return nil, fmt.Errorf("unexpected error when querying for pool [%s]: [%w]", lbPoolName, err)
 	}
 	if lbPoolRef == nil {
 		return nil, fmt.Errorf("no lb pool found with name [%s]: [%v]", lbPoolName, err)
 	}
 
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
 	}
 
-	lbPool, resp, err := client.APIClient.EdgeGatewayLoadBalancerPoolApi.GetLoadBalancerPool(ctx, lbPoolRef.Id, org.Org.ID)
+	lbPool, resp, err := session.APIClient.EdgeGatewayLoadBalancerPoolApi.GetLoadBalancerPool(ctx, lbPoolRef.Id, org.Org.ID)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get loadbalancer pool with id [%s]: [%v]", lbPoolRef.Id, err)
+		return nil, fmt.Errorf("unable to get loadbalancer pool with id [%s]: [%w]", lbPoolRef.Id, err)
 	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("unable to get loadbalancer pool with id [%s], expected http response [%v], obtained [%v]", lbPoolRef.Id, http.StatusOK, resp.StatusCode)
@@ -921,15 +1032,15 @@ func (gm *GatewayManager) UpdateLoadBalancerPool(ctx context.Context, lbPoolName
 		return lbPoolRef, nil
 	}
 	if err = gm.checkIfLBPoolIsReady(ctx, lbPoolName); err != nil {
-		return nil, fmt.Errorf("unable to update loadbalancer pool [%s]; loadbalancer pool is busy: [%v]", lbPoolName, err)
+		return nil, fmt.Errorf("unable to update loadbalancer pool [%s]; loadbalancer pool is busy: [%w]", lbPoolName, err)
 	}
 	if err := gm.checkIfGatewayIsReady(ctx); err != nil {
 		klog.Errorf("failed to update DNAT rule; gateway [%s] is busy", gm.GatewayRef.Name)
 		return nil, fmt.Errorf("unable to update loadbalancer pool [%s]; gateway is busy: [%s]", lbPoolName, err)
 	}
-	lbPool, resp, err = client.APIClient.EdgeGatewayLoadBalancerPoolApi.GetLoadBalancerPool(ctx, lbPoolRef.Id, org.Org.ID)
+	lbPool, resp, err = session.APIClient.EdgeGatewayLoadBalancerPoolApi.GetLoadBalancerPool(ctx, lbPoolRef.Id, org.Org.ID)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get loadbalancer pool with id [%s]: [%v]", lbPoolRef.Id, err)
+		return nil, fmt.Errorf("unable to get loadbalancer pool with id [%s]: [%w]", lbPoolRef.Id, err)
 	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("unable to get loadbalancer pool with id [%s], expected http response [%v], obtained [%v]", lbPoolRef.Id, http.StatusOK, resp.StatusCode)
@@ -940,7 +1051,7 @@ func (gm *GatewayManager) UpdateLoadBalancerPool(ctx context.Context, lbPoolName
 		healthMonitor = &swaggerClient.EdgeLoadBalancerHealthMonitor{Type_: protocol}
 	}
 	updatedLBPool, lbPoolMembers := gm.formLoadBalancerPool(lbPoolName, lbPoolUniqueIPList, internalPort, healthMonitor)
-	resp, err = client.APIClient.EdgeGatewayLoadBalancerPoolApi.UpdateLoadBalancerPool(ctx, updatedLBPool, lbPoolRef.Id, org.Org.ID)
+	resp, err = session.APIClient.EdgeGatewayLoadBalancerPoolApi.UpdateLoadBalancerPool(ctx, updatedLBPool, lbPoolRef.Id, org.Org.ID)
 	if resp != nil && resp.StatusCode != http.StatusAccepted {
 		var responseMessageBytes []byte
 		if gsErr, ok := err.(swaggerClient.GenericSwaggerError); ok {
@@ -950,22 +1061,22 @@ func (gm *GatewayManager) UpdateLoadBalancerPool(ctx context.Context, lbPoolName
 			"unable to update loadblanacer pool [%s] having members [%+v]; expected http response [%v], obtained [%v]: resp: [%#v]: [%v]",
 			lbPoolName, lbPoolMembers, http.StatusAccepted, resp.StatusCode, string(responseMessageBytes), err)
 	} else if err != nil {
-		return nil, fmt.Errorf("unable to update loadbalancer pool having name [%s], members [%+v]: resp [%+v]: [%v]",
+		return nil, fmt.Errorf("unable to update loadbalancer pool having name [%s], members [%+v]: resp [%+v]: [%w]",
 			lbPoolName, lbPoolMembers, resp, err)
 	}
 
 	taskURL := resp.Header.Get("Location")
-	task := govcd.NewTask(&client.VCDClient.Client)
+	task := govcd.NewTask(&session.VCDClient.Client)
 	task.Task.HREF = taskURL
-	if err = task.WaitTaskCompletion(); err != nil {
-		return nil, fmt.Errorf("unable to update loadbalancer pool; update task [%s] did not complete: [%v]",
+	if err = waitTaskCompletion(ctx, task); err != nil {
+		return nil, fmt.Errorf("unable to update loadbalancer pool; update task [%s] did not complete: [%w]",
 			taskURL, err)
 	}
 
 	// Get the pool to return it
 	lbPoolRef, err = gm.getLoadBalancerPool(ctx, lbPoolName)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error when querying for pool [%s]: [%v]",
+		return nil, fmt.Errorf("unexpected error when querying for pool [%s]: [%w]",
 			lbPoolName, err)
 	}
 	if lbPoolRef == nil {
@@ -979,38 +1090,47 @@ func (gm *GatewayManager) UpdateLoadBalancerPool(ctx context.Context, lbPoolName
 
 func (gm *GatewayManager) GetVirtualService(ctx context.Context,
 	virtualServiceName string) (*swaggerClient.EdgeLoadBalancerVirtualServiceSummary, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.GetVirtualService")
+	defer span.End()
 
 	client := gm.Client
+	session := client.Session()
 	if gm.GatewayRef == nil {
 		return nil, fmt.Errorf("gateway reference should not be nil")
 	}
 
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
 	}
-	// This should return exactly one result, so no need to accumulate results
-	lbVSSummaries, resp, err := client.APIClient.EdgeGatewayLoadBalancerVirtualServicesApi.GetVirtualServiceSummariesForGateway(
-		ctx, 1, 25, gm.GatewayRef.Id, org.Org.ID,
-		&swaggerClient.EdgeGatewayLoadBalancerVirtualServicesApiGetVirtualServiceSummariesForGatewayOpts{
-			Filter: optional.NewString(fmt.Sprintf("name==%s", virtualServiceName)),
-		},
-	)
+	// This should return exactly one result, but all pages are read so that duplicates are not missed
+	lbVSSummaries, err := listAllPages(pageNumberPagination, DefaultPageSize,
+		func(page pageRequest) ([]swaggerClient.EdgeLoadBalancerVirtualServiceSummary, int32, *http.Response, error) {
+			lbVSSummaries, resp, err := session.APIClient.EdgeGatewayLoadBalancerVirtualServicesApi.GetVirtualServiceSummariesForGateway(
+				ctx, page.PageNum, page.PageSize, gm.GatewayRef.Id, org.Org.ID,
+				&swaggerClient.EdgeGatewayLoadBalancerVirtualServicesApiGetVirtualServiceSummariesForGatewayOpts{
+					Filter: optional.NewString(fmt.Sprintf("name==%s", virtualServiceName)),
+				},
+			)
+			return lbVSSummaries.Values, lbVSSummaries.PageCount, resp, err
+		})
 	if err != nil {
-		return nil, fmt.Errorf("unable to get reference for LB VS [%s]: resp: [%v]: [%v]",
-			virtualServiceName, resp, err)
+		return nil, fmt.Errorf("unable to get reference for LB VS [%s]: [%w]", virtualServiceName, err)
 	}
-	if len(lbVSSummaries.Values) != 1 {
+	if len(lbVSSummaries) != 1 {
 		return nil, nil // this is not an error
 	}
 
-	return &lbVSSummaries.Values[0], nil
+	return &lbVSSummaries[0], nil
 }
 
 func (gm *GatewayManager) CheckIfVirtualServiceIsPending(ctx context.Context, virtualServiceName string) error {
+	ctx, span := startSpan(ctx, "GatewayManager.CheckIfVirtualServiceIsPending")
+	defer span.End()
+
 	if gm.GatewayRef == nil {
 		return fmt.Errorf("gateway reference should not be nil")
 	}
@@ -1018,7 +1138,7 @@ func (gm *GatewayManager) CheckIfVirtualServiceIsPending(ctx context.Context, vi
 	klog.V(3).Infof("Checking if virtual service [%s] is still pending", virtualServiceName)
 	vsSummary, err := gm.GetVirtualService(ctx, virtualServiceName)
 	if err != nil {
-		return fmt.Errorf("unable to get summary for LB VS [%s]: [%v]", virtualServiceName, err)
+		return fmt.Errorf("unable to get summary for LB VS [%s]: [%w]", virtualServiceName, err)
 	}
 	if vsSummary == nil {
 		return fmt.Errorf("unable to get summary of virtual service [%s]: [%v]", virtualServiceName, err)
@@ -1034,6 +1154,9 @@ func (gm *GatewayManager) CheckIfVirtualServiceIsPending(ctx context.Context, vi
 }
 
 func (gm *GatewayManager) checkIfVirtualServiceIsReady(ctx context.Context, virtualServiceName string) error {
+	ctx, span := startSpan(ctx, "GatewayManager.checkIfVirtualServiceIsReady")
+	defer span.End()
+
 	if gm.GatewayRef == nil {
 		return fmt.Errorf("gateway reference should not be nil")
 	}
@@ -1041,7 +1164,7 @@ func (gm *GatewayManager) checkIfVirtualServiceIsReady(ctx context.Context, virt
 	klog.V(3).Infof("Checking if virtual service [%s] is busy", virtualServiceName)
 	vsSummary, err := gm.GetVirtualService(ctx, virtualServiceName)
 	if err != nil {
-		return fmt.Errorf("unable to get summary for LB VS [%s]: [%v]", virtualServiceName, err)
+		return fmt.Errorf("unable to get summary for LB VS [%s]: [%w]", virtualServiceName, err)
 	}
 	if vsSummary == nil {
 		return fmt.Errorf("unable to get summary of virtual service [%s]: [%v]", virtualServiceName, err)
@@ -1057,6 +1180,9 @@ func (gm *GatewayManager) checkIfVirtualServiceIsReady(ctx context.Context, virt
 }
 
 func (gm *GatewayManager) checkIfLBPoolIsReady(ctx context.Context, lbPoolName string) error {
+	ctx, span := startSpan(ctx, "GatewayManager.checkIfLBPoolIsReady")
+	defer span.End()
+
 	if gm.GatewayRef == nil {
 		return fmt.Errorf("gateway reference should not be nil")
 	}
@@ -1064,7 +1190,7 @@ func (gm *GatewayManager) checkIfLBPoolIsReady(ctx context.Context, lbPoolName s
 	klog.V(3).Infof("Checking if loadbalancer pool [%s] is busy", lbPoolName)
 	lbPoolSummary, err := gm.getLoadBalancerPoolSummary(ctx, lbPoolName)
 	if err != nil {
-		return fmt.Errorf("unable to get summary for LB VS [%s]: [%v]", lbPoolName, err)
+		return fmt.Errorf("unable to get summary for LB VS [%s]: [%w]", lbPoolName, err)
 	}
 	if lbPoolSummary == nil {
 		return fmt.Errorf("unable to get summary of virtual service [%s]: [%v]", lbPoolName, err)
@@ -1080,15 +1206,19 @@ func (gm *GatewayManager) checkIfLBPoolIsReady(ctx context.Context, lbPoolName s
 }
 
 func (gm *GatewayManager) checkIfGatewayIsReady(ctx context.Context) error {
+	ctx, span := startSpan(ctx, "GatewayManager.checkIfGatewayIsReady")
+	defer span.End()
+
 	client := gm.Client
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	session := client.Session()
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
 	}
-	edgeGateway, resp, err := client.APIClient.EdgeGatewayApi.GetEdgeGateway(ctx, gm.GatewayRef.Id, org.Org.ID)
+	edgeGateway, resp, err := session.APIClient.EdgeGatewayApi.GetEdgeGateway(ctx, gm.GatewayRef.Id, org.Org.ID)
 	if resp != nil && resp.StatusCode != http.StatusOK {
 		var responseMessageBytes []byte
 		if gsErr, ok := err.(swaggerClient.GenericSwaggerError); ok {
@@ -1098,7 +1228,7 @@ func (gm *GatewayManager) checkIfGatewayIsReady(ctx context.Context) error {
 			"unable to get gateway details; expected http response [%v], obtained [%v]: resp: [%#v]: [%v]",
 			http.StatusOK, resp.StatusCode, string(responseMessageBytes), err)
 	} else if err != nil {
-		return fmt.Errorf("error while checking gateway status for [%s]: [%v]", gm.GatewayRef.Name, err)
+		return fmt.Errorf("error while checking gateway status for [%s]: [%w]", gm.GatewayRef.Name, err)
 	}
 	if *edgeGateway.Status == "REALIZED" {
 		klog.V(3).Infof("Completed waiting for [%s] to be configured since gateway status is [%s]",
@@ -1111,10 +1241,23 @@ func (gm *GatewayManager) checkIfGatewayIsReady(ctx context.Context) error {
 
 func (gm *GatewayManager) UpdateVirtualService(ctx context.Context, virtualServiceName string,
 	virtualServiceIP string, externalPort int32, oneArmEnabled bool) (*swaggerClient.EntityReference, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.UpdateVirtualService")
+	defer span.End()
+
+	if gm.GatewayRef == nil {
+		return nil, fmt.Errorf("gateway reference should not be nil")
+	}
+	unlockGateway, err := gm.Client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockGateway()
+
 	client := gm.Client
+	session := client.Session()
 	vsSummary, err := gm.GetVirtualService(ctx, virtualServiceName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get virtual service summary for virtual service [%s]: [%v]", virtualServiceName, err)
+		return nil, fmt.Errorf("failed to get virtual service summary for virtual service [%s]: [%w]", virtualServiceName, err)
 	}
 	if vsSummary == nil {
 		return nil, fmt.Errorf("virtual service [%s] doesn't exist", virtualServiceName)
@@ -1122,9 +1265,9 @@ func (gm *GatewayManager) UpdateVirtualService(ctx context.Context, virtualServi
 	if len(vsSummary.ServicePorts) == 0 {
 		return nil, fmt.Errorf("virtual service [%s] has no service ports", virtualServiceName)
 	}
-	org, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	org, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if org == nil || org.Org == nil {
 		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
@@ -1140,7 +1283,7 @@ func (gm *GatewayManager) UpdateVirtualService(ctx context.Context, virtualServi
 	if err = gm.checkIfVirtualServiceIsReady(ctx, virtualServiceName); err != nil {
 		return nil, err
 	}
-	vs, _, err := client.APIClient.EdgeGatewayLoadBalancerVirtualServiceApi.GetVirtualService(ctx, vsSummary.Id, org.Org.ID)
+	vs, _, err := session.APIClient.EdgeGatewayLoadBalancerVirtualServiceApi.GetVirtualService(ctx, vsSummary.Id, org.Org.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get virtual service with ID [%s]", vsSummary.Id)
 	}
@@ -1153,7 +1296,7 @@ func (gm *GatewayManager) UpdateVirtualService(ctx context.Context, virtualServi
 		// update the virtual IP address of the virtual service when one arm is nil
 		vs.VirtualIpAddress = virtualServiceIP
 	}
-	resp, err := client.APIClient.EdgeGatewayLoadBalancerVirtualServiceApi.UpdateVirtualService(ctx, vs, vsSummary.Id, org.Org.ID)
+	resp, err := session.APIClient.EdgeGatewayLoadBalancerVirtualServiceApi.UpdateVirtualService(ctx, vs, vsSummary.Id, org.Org.ID)
 	if resp != nil && resp.StatusCode != http.StatusAccepted {
 		var responseMessageBytes []byte
 		if gsErr, ok := err.(swaggerClient.GenericSwaggerError); ok {
@@ -1163,19 +1306,19 @@ func (gm *GatewayManager) UpdateVirtualService(ctx context.Context, virtualServi
 			"unable to update virtual service; expected http response [%v], obtained [%v]: resp: [%#v]: [%v]",
 			http.StatusAccepted, resp.StatusCode, string(responseMessageBytes), err)
 	} else if err != nil {
-		return nil, fmt.Errorf("error while updating virtual service [%s]: [%v]", virtualServiceName, err)
+		return nil, fmt.Errorf("error while updating virtual service [%s]: [%w]", virtualServiceName, err)
 	}
 	taskURL := resp.Header.Get("Location")
-	task := govcd.NewTask(&client.VCDClient.Client)
+	task := govcd.NewTask(&session.VCDClient.Client)
 	task.Task.HREF = taskURL
-	if err = task.WaitTaskCompletion(); err != nil {
-		return nil, fmt.Errorf("unable to update virtual service; update task [%s] did not complete: [%v]",
+	if err = waitTaskCompletion(ctx, task); err != nil {
+		return nil, fmt.Errorf("unable to update virtual service; update task [%s] did not complete: [%w]",
 			taskURL, err)
 	}
 
 	vsSummary, err = gm.GetVirtualService(ctx, virtualServiceName)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get summary for freshly created LB VS [%s]: [%v]",
+		return nil, fmt.Errorf("unable to get summary for freshly created LB VS [%s]: [%w]",
 			virtualServiceName, err)
 	}
 	if vsSummary == nil {
@@ -1194,15 +1337,23 @@ func (gm *GatewayManager) CreateVirtualService(ctx context.Context, virtualServi
 	lbPoolRef *swaggerClient.EntityReference, segRef *swaggerClient.EntityReference,
 	freeIP string, vsType string, externalPort int32,
 	useSSL bool, certificateAlias string) (*swaggerClient.EntityReference, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.CreateVirtualService")
+	defer span.End()
 
 	client := gm.Client
+	session := client.Session()
 	if gm.GatewayRef == nil {
 		return nil, fmt.Errorf("gateway reference should not be nil")
 	}
+	unlockGateway, err := gm.Client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockGateway()
 
 	vsSummary, err := gm.GetVirtualService(ctx, virtualServiceName)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error while getting summary for LB VS [%s]: [%v]",
+		return nil, fmt.Errorf("unexpected error while getting summary for LB VS [%s]: [%w]",
 			virtualServiceName, err)
 	}
 	if vsSummary != nil {
@@ -1265,55 +1416,62 @@ func (gm *GatewayManager) CreateVirtualService(ctx context.Context, virtualServi
 		return nil, fmt.Errorf("unhandled virtual service type [%s]", vsType)
 	}
 
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("unable to get org for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if clusterOrg == nil || clusterOrg.Org == nil {
 		return nil, fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
 	if useSSL {
-		certLibItems, resp, err := client.APIClient.CertificateLibraryApi.QueryCertificateLibrary(ctx,
-			1, 128,
-			&swaggerClient.CertificateLibraryApiQueryCertificateLibraryOpts{
-				Filter: optional.NewString(fmt.Sprintf("alias==%s", certificateAlias)),
-			},
-			clusterOrg.Org.ID,
-		)
+		certLibItems, err := listAllPages(pageNumberPagination, MaxPageSize,
+			func(page pageRequest) ([]swaggerClient.CertificateLibraryItem, int32, *http.Response, error) {
+				certLibItems, resp, err := session.APIClient.CertificateLibraryApi.QueryCertificateLibrary(ctx,
+					page.PageNum, page.PageSize,
+					&swaggerClient.CertificateLibraryApiQueryCertificateLibraryOpts{
+						Filter: optional.NewString(fmt.Sprintf("alias==%s", certificateAlias)),
+					},
+					clusterOrg.Org.ID,
+				)
+				return certLibItems.Values, certLibItems.PageCount, resp, err
+			})
 		if err != nil {
-			return nil, fmt.Errorf("unable to get cert with alias [%s] in org [%s]: resp: [%v]: [%v]",
-				certificateAlias, client.ClusterOrgName, resp, err)
+			return nil, fmt.Errorf("unable to get cert with alias [%s] in org [%s]: [%w]",
+				certificateAlias, client.ClusterOrgName, err)
 		}
-		if len(certLibItems.Values) != 1 {
+		if len(certLibItems) != 1 {
 			return nil, fmt.Errorf("expected 1 cert with alias [%s], obtained [%d]",
-				certificateAlias, len(certLibItems.Values))
+				certificateAlias, len(certLibItems))
 		}
 		virtualServiceConfig.CertificateRef = &swaggerClient.EntityReference{
-			Name: certLibItems.Values[0].Alias,
-			Id:   certLibItems.Values[0].Id,
+			Name: certLibItems[0].Alias,
+			Id:   certLibItems[0].Id,
 		}
 	}
 
-	resp, gsErr := client.APIClient.EdgeGatewayLoadBalancerVirtualServicesApi.CreateVirtualService(ctx, *virtualServiceConfig, clusterOrg.Org.ID)
+	resp, gsErr := session.APIClient.EdgeGatewayLoadBalancerVirtualServicesApi.CreateVirtualService(ctx, *virtualServiceConfig, clusterOrg.Org.ID)
 	if resp != nil && resp.StatusCode != http.StatusAccepted {
+		gm.invalidateLoadBalancerSEGAssignments()
 		return nil, fmt.Errorf(
 			"unable to create virtual service; expected http response [%v], obtained [%v]: resp: [%#v]: [%v]: [%v]",
 			http.StatusAccepted, resp.StatusCode, resp, gsErr, string(gsErr.Body()))
 	} else if gsErr != nil {
+		gm.invalidateLoadBalancerSEGAssignments()
 		return nil, fmt.Errorf("error while creating virtual service [%s]: [%v]", virtualServiceName, gsErr)
 	}
 
 	taskURL := resp.Header.Get("Location")
-	task := govcd.NewTask(&client.VCDClient.Client)
+	task := govcd.NewTask(&session.VCDClient.Client)
 	task.Task.HREF = taskURL
-	if err = task.WaitTaskCompletion(); err != nil {
-		return nil, fmt.Errorf("unable to create virtual service; creation task [%s] did not complete: [%v]",
+	if err = waitTaskCompletion(ctx, task); err != nil {
+		gm.invalidateLoadBalancerSEGAssignments()
+		return nil, fmt.Errorf("unable to create virtual service; creation task [%s] did not complete: [%w]",
 			taskURL, err)
 	}
 
 	vsSummary, err = gm.GetVirtualService(ctx, virtualServiceName)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get summary for freshly created LB VS [%s]: [%v]",
+		return nil, fmt.Errorf("unable to get summary for freshly created LB VS [%s]: [%w]",
 			virtualServiceName, err)
 	}
 	if vsSummary == nil {
@@ -1337,15 +1495,23 @@ func (gm *GatewayManager) CreateVirtualService(ctx context.Context, virtualServi
 
 func (gm *GatewayManager) DeleteVirtualService(ctx context.Context, virtualServiceName string,
 	failIfAbsent bool) error {
+	ctx, span := startSpan(ctx, "GatewayManager.DeleteVirtualService")
+	defer span.End()
 
 	client := gm.Client
+	session := client.Session()
 	if gm.GatewayRef == nil {
 		return fmt.Errorf("gateway reference should not be nil")
 	}
+	unlockGateway, err := gm.Client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return err
+	}
+	defer unlockGateway()
 
 	vsSummary, err := gm.GetVirtualService(ctx, virtualServiceName)
 	if err != nil {
-		return fmt.Errorf("unable to get summary for LB Virtual Service [%s]: [%v]",
+		return fmt.Errorf("unable to get summary for LB Virtual Service [%s]: [%w]",
 			virtualServiceName, err)
 	}
 	if vsSummary == nil {
@@ -1355,9 +1521,9 @@ func (gm *GatewayManager) DeleteVirtualService(ctx context.Context, virtualServi
 
 		return nil
 	}
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
+		return fmt.Errorf("unable to get org for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if clusterOrg == nil || clusterOrg.Org == nil {
 		return fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
@@ -1369,7 +1535,7 @@ func (gm *GatewayManager) DeleteVirtualService(ctx context.Context, virtualServi
 		return err
 	}
 
-	resp, err := client.APIClient.EdgeGatewayLoadBalancerVirtualServiceApi.DeleteVirtualService(
+	resp, err := session.APIClient.EdgeGatewayLoadBalancerVirtualServiceApi.DeleteVirtualService(
 		ctx, vsSummary.Id, clusterOrg.Org.ID)
 	if resp != nil && resp.StatusCode != http.StatusAccepted {
 		var responseMessageBytes []byte
@@ -1379,16 +1545,18 @@ func (gm *GatewayManager) DeleteVirtualService(ctx context.Context, virtualServi
 		return fmt.Errorf("unable to delete virtual service [%s]; expected http response [%v], obtained [%v] with response [%v]",
 			vsSummary.Name, http.StatusAccepted, resp.StatusCode, string(responseMessageBytes))
 	} else if err != nil {
-		return fmt.Errorf("failed to delete virtual service [%s]: [%v]", vsSummary.Name, err)
+		return fmt.Errorf("failed to delete virtual service [%s]: [%w]", vsSummary.Name, err)
 	}
 
 	taskURL := resp.Header.Get("Location")
-	task := govcd.NewTask(&client.VCDClient.Client)
+	task := govcd.NewTask(&session.VCDClient.Client)
 	task.Task.HREF = taskURL
-	if err = task.WaitTaskCompletion(); err != nil {
-		return fmt.Errorf("unable to delete virtual service; deletion task [%s] did not complete: [%v]",
+	if err = waitTaskCompletion(ctx, task); err != nil {
+		return fmt.Errorf("unable to delete virtual service; deletion task [%s] did not complete: [%w]",
 			taskURL, err)
 	}
+	// the virtual service freed a slot of its service engine group
+	gm.invalidateLoadBalancerSEGAssignments()
 	klog.Infof("Deleted virtual service [%s]\n", virtualServiceName)
 
 	return nil
@@ -1405,11 +1573,13 @@ type PortDetails struct {
 
 // GetLoadBalancer :
 func (gm *GatewayManager) GetLoadBalancer(ctx context.Context, virtualServiceName string, lbPoolName string, oneArm *OneArm) (string, *util.AllocatedResourcesMap, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.GetLoadBalancer")
+	defer span.End()
 
 	allocatedResources := util.AllocatedResourcesMap{}
 	vsSummary, err := gm.GetVirtualService(ctx, virtualServiceName)
 	if err != nil {
-		return "", nil, fmt.Errorf("unable to get summary for LB Virtual Service [%s]: [%v]",
+		return "", nil, fmt.Errorf("unable to get summary for LB Virtual Service [%s]: [%w]",
 			virtualServiceName, err)
 	}
 	if vsSummary == nil {
@@ -1422,7 +1592,7 @@ func (gm *GatewayManager) GetLoadBalancer(ctx context.Context, virtualServiceNam
 
 	lbPoolRef, err := gm.GetLoadBalancerPool(ctx, lbPoolName)
 	if err != nil {
-		return "", nil, fmt.Errorf("unable to get load balancer pool information for LB Pool [%s]: [%v]", lbPoolName, err)
+		return "", nil, fmt.Errorf("unable to get load balancer pool information for LB Pool [%s]: [%w]", lbPoolName, err)
 	}
 	// no need to check if lbPoolRef is nil. Since the function GetLoadBalancerPool returns an error if lbPoolRef returned is nil
 	allocatedResources.Insert(VcdResourceLoadBalancerPool, lbPoolRef)
@@ -1439,7 +1609,7 @@ func (gm *GatewayManager) GetLoadBalancer(ctx context.Context, virtualServiceNam
 	dnatRuleName := GetDNATRuleName(virtualServiceName)
 	dnatRuleRef, err := gm.GetNATRuleRef(ctx, dnatRuleName)
 	if err != nil {
-		return "", &allocatedResources, fmt.Errorf("unable to find dnat rule [%s] for virtual service [%s]: [%v]",
+		return "", &allocatedResources, fmt.Errorf("unable to find dnat rule [%s] for virtual service [%s]: [%w]",
 			dnatRuleName, virtualServiceName, err)
 	}
 	if dnatRuleRef == nil {
@@ -1474,16 +1644,16 @@ func (gm *GatewayManager) IsNSXTBackedGateway() bool {
 func (gm *GatewayManager) IsUsingIpSpaces() (bool, error) {
 	edgeGatewayName := gm.GatewayRef.Name
 	edgeGatewayID := gm.GatewayRef.Id
-	clusterOrg, err := gm.Client.VCDClient.GetOrgByName(gm.Client.ClusterOrgName)
+	clusterOrg, err := gm.Client.GetOrgByName(gm.Client.ClusterOrgName)
 	if err != nil {
-		return false, fmt.Errorf("error retrieving org [%s]: [%v]", gm.Client.ClusterOrgName, err)
+		return false, fmt.Errorf("error retrieving org [%s]: [%w]", gm.Client.ClusterOrgName, err)
 	}
 	if clusterOrg == nil || clusterOrg.Org == nil {
 		return false, fmt.Errorf("unable to determine if gateway [%s] is using Ip Spaces or not; obtained nil org", edgeGatewayName)
 	}
 	edgeGateway, err := clusterOrg.GetNsxtEdgeGatewayById(edgeGatewayID)
 	if err != nil {
-		return false, fmt.Errorf("unable to determine if gateway [%s] is using Ip Spaces or not. error [%v]", edgeGatewayName, err)
+		return false, fmt.Errorf("unable to determine if gateway [%s] is using Ip Spaces or not. error [%w]", edgeGatewayName, err)
 	}
 
 	edgeGatewayUplinks := edgeGateway.EdgeGateway.EdgeGatewayUplinks
@@ -1503,9 +1673,12 @@ func (gm *GatewayManager) IsUsingIpSpaces() (bool, error) {
 }
 
 func (gm *GatewayManager) GetLoadBalancerPool(ctx context.Context, lbPoolName string) (*swaggerClient.EntityReference, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.GetLoadBalancerPool")
+	defer span.End()
+
 	lbPoolRef, err := gm.getLoadBalancerPool(ctx, lbPoolName)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get reference for LB pool [%s]: [%v]", lbPoolName, err)
+		return nil, fmt.Errorf("unable to get reference for LB pool [%s]: [%w]", lbPoolName, err)
 	}
 	if lbPoolRef == nil {
 		return nil, govcd.ErrorEntityNotFound
@@ -1514,20 +1687,24 @@ func (gm *GatewayManager) GetLoadBalancerPool(ctx context.Context, lbPoolName st
 }
 
 func (gm *GatewayManager) GetLoadBalancerPoolMemberIPs(ctx context.Context, lbPoolRef *swaggerClient.EntityReference) ([]string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.GetLoadBalancerPoolMemberIPs")
+	defer span.End()
+
 	client := gm.Client
+	session := client.Session()
 	if lbPoolRef == nil {
 		return nil, govcd.ErrorEntityNotFound
 	}
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
+		return nil, fmt.Errorf("unable to get org for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if clusterOrg == nil || clusterOrg.Org == nil {
 		return nil, fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
-	lbPool, resp, err := client.APIClient.EdgeGatewayLoadBalancerPoolApi.GetLoadBalancerPool(ctx, lbPoolRef.Id, clusterOrg.Org.ID)
+	lbPool, resp, err := session.APIClient.EdgeGatewayLoadBalancerPoolApi.GetLoadBalancerPool(ctx, lbPoolRef.Id, clusterOrg.Org.ID)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get the details for LB pool [%s]: [%+v]: [%v]",
+		return nil, fmt.Errorf("unable to get the details for LB pool [%s]: [%+v]: [%w]",
 			lbPoolRef.Name, resp, err)
 	}
 
@@ -1539,6 +1716,34 @@ func (gm *GatewayManager) GetLoadBalancerPoolMemberIPs(ctx context.Context, lbPo
 	return memberIPs, nil
 }
 
+// getExternalIPForLoadBalancer picks a VIP for a new load balancer. If the gateway uses IP spaces, an IP is reserved
+// and claimed using lbIpClaimMarker; otherwise the first unused IP in the IPAM subnet of the gateway is returned.
+func (gm *GatewayManager) getExternalIPForLoadBalancer(ctx context.Context, lbIpClaimMarker string) (string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.getExternalIPForLoadBalancer")
+	defer span.End()
+
+	isGatewayUsingIpSpaces, err := gm.IsUsingIpSpaces()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine if gateway [%s] is using IP spaces: [%w]", gm.GatewayRef.Name, err)
+	}
+	if isGatewayUsingIpSpaces {
+		klog.Infof("Determined gateway [%s] is using IP spaces, using IP space specific logic to reserve an IP", gm.GatewayRef.Name)
+		externalIP, err := gm.ReserveIpForLoadBalancer(ctx, lbIpClaimMarker)
+		if err != nil {
+			return "", fmt.Errorf("unable to reservce IP address for load balancer. error [%w]", err)
+		}
+		return externalIP, nil
+	}
+
+	klog.Infof("Determined gateway [%s] is not using IP spaces, using legacy IPAM solution to find a free IP", gm.GatewayRef.Name)
+	externalIP, err := gm.GetUnusedExternalIPAddress(ctx, gm.IPAMSubnet)
+	if err != nil {
+		return "", fmt.Errorf("unable to get unused IP address from subnet [%s]: [%w]",
+			gm.IPAMSubnet, err)
+	}
+	return externalIP, nil
+}
+
 func (gm *GatewayManager) CreateLoadBalancer(
 	ctx context.Context, virtualServiceNamePrefix string, lbPoolNamePrefix string, lbIpClaimMarker string,
 	ips []string, portDetailsList []PortDetails, oneArm *OneArm, enableVirtualServiceSharedIP bool,
@@ -1556,8 +1761,15 @@ func (gm *GatewayManager) CreateLoadBalancer(
 	klog.Infof("Using provided IP [%s]\n", providedIP)
 
 	client := gm.Client
-	client.RWLock.Lock()
-	defer client.RWLock.Unlock()
+	unlockService, err := client.lockService(ctx, gm.GatewayRef.Id, virtualServiceNamePrefix)
+	if err != nil {
+		return "", err
+	}
+	defer unlockService()
+
+	// the IPs allocated below are used by the NAT rules and virtual services that are created before this returns
+	claims := gm.newIPClaims()
+	defer claims.release()
 
 	// get shared ip when vsSharedIP is true and portNameToIP is not nil
 	sharedVirtualIP := ""
@@ -1576,7 +1788,6 @@ func (gm *GatewayManager) CreateLoadBalancer(
 	// partial creation of load-balancer is continued and an externalIP was claimed earlier by a dnat rule
 	externalIP := providedIP
 	sharedInternalIP := ""
-	var err error
 	if oneArm != nil {
 		for _, portDetails := range portDetailsList {
 			if portDetails.InternalPort == 0 {
@@ -1587,7 +1798,7 @@ func (gm *GatewayManager) CreateLoadBalancer(
 			dnatRuleName := GetDNATRuleName(virtualServiceName)
 			dnatRuleRef, err := gm.GetNATRuleRef(ctx, dnatRuleName)
 			if err != nil {
-				return "", fmt.Errorf("unable to retrieve created dnat rule [%s]: [%v]", dnatRuleName, err)
+				return "", fmt.Errorf("unable to retrieve created dnat rule [%s]: [%w]", dnatRuleName, err)
 			}
 			if dnatRuleRef == nil {
 				continue // ths implies that the rule does not exist
@@ -1616,31 +1827,21 @@ func (gm *GatewayManager) CreateLoadBalancer(
 		}
 	} else if enableVirtualServiceSharedIP && oneArm != nil { // internal ip used, dnat rule is needed
 		if sharedInternalIP == "" { // no dnat rule has been created yet
-			sharedInternalIP, err = gm.GetUnusedInternalIPAddress(ctx, oneArm)
+			sharedInternalIP, err = claims.allocate(ctx, func(ctx context.Context) (string, error) {
+				return gm.GetUnusedInternalIPAddress(ctx, oneArm)
+			})
 			if err != nil {
-				return "", fmt.Errorf("unable to get internal IP address for one-arm mode: [%v]", err)
+				return "", fmt.Errorf("unable to get internal IP address for one-arm mode: [%w]", err)
 			}
 		}
 	}
 
 	if externalIP == "" {
-		isGatewayUsingIpSpaces, err := gm.IsUsingIpSpaces()
+		externalIP, err = claims.allocate(ctx, func(ctx context.Context) (string, error) {
+			return gm.getExternalIPForLoadBalancer(ctx, lbIpClaimMarker)
+		})
 		if err != nil {
-			return "", fmt.Errorf("unable to create load balancer. err [%v]", err)
-		}
-		if isGatewayUsingIpSpaces {
-			klog.Infof("Determined gateway [%s] is using IP spaces, using IP space specific logic to reserve an IP", gm.GatewayRef.Name)
-			externalIP, err = gm.ReserveIpForLoadBalancer(ctx, lbIpClaimMarker)
-			if err != nil {
-				return "", fmt.Errorf("unable to reservce IP address for load balancer. error [%v]", err)
-			}
-		} else {
-			klog.Infof("Determined gateway [%s] is not using IP spaces, using legacy IPAM solution to find a free IP", gm.GatewayRef.Name)
-			externalIP, err = gm.GetUnusedExternalIPAddress(ctx, gm.IPAMSubnet)
-			if err != nil {
-				return "", fmt.Errorf("unable to get unused IP address from subnet [%s]: [%v]",
-					gm.IPAMSubnet, err)
-			}
+			return "", fmt.Errorf("unable to create load balancer. err [%w]", err)
 		}
 	}
 	klog.Infof("Using VIP [%s] for virtual service\n", externalIP)
@@ -1657,7 +1858,7 @@ func (gm *GatewayManager) CreateLoadBalancer(
 
 		vsSummary, err := gm.GetVirtualService(ctx, virtualServiceName)
 		if err != nil {
-			return "", fmt.Errorf("unexpected error while querying for virtual service [%s]: [%v]",
+			return "", fmt.Errorf("unexpected error while querying for virtual service [%s]: [%w]",
 				virtualServiceName, err)
 		}
 		if vsSummary != nil {
@@ -1687,9 +1888,11 @@ func (gm *GatewayManager) CreateLoadBalancer(
 				// created with the same IP and different ports
 				internalIP = sharedInternalIP
 			} else {
-				internalIP, err = gm.GetUnusedInternalIPAddress(ctx, oneArm)
+				internalIP, err = claims.allocate(ctx, func(ctx context.Context) (string, error) {
+					return gm.GetUnusedInternalIPAddress(ctx, oneArm)
+				})
 				if err != nil {
-					return "", fmt.Errorf("unable to get internal IP address for one-arm mode: [%v]", err)
+					return "", fmt.Errorf("unable to get internal IP address for one-arm mode: [%w]", err)
 				}
 			}
 
@@ -1699,7 +1902,7 @@ func (gm *GatewayManager) CreateLoadBalancer(
 			appPortProfileName := GetAppPortProfileName(dnatRuleName)
 			appPortProfile, err := gm.CreateAppPortProfile(appPortProfileName, portDetails.ExternalPort)
 			if err != nil {
-				return "", fmt.Errorf("failed to create App Port Profile: [%v]", err)
+				return "", fmt.Errorf("failed to create App Port Profile: [%w]", err)
 			}
 			if appPortProfile == nil || appPortProfile.NsxtAppPortProfile == nil {
 				return "", fmt.Errorf("creation of app port profile succeeded but app port profile is empty")
@@ -1711,7 +1914,7 @@ func (gm *GatewayManager) CreateLoadBalancer(
 
 			if err = gm.CreateDNATRule(ctx, dnatRuleName, externalIP, internalIP,
 				portDetails.ExternalPort, portDetails.InternalPort, appPortProfile); err != nil {
-				return "", fmt.Errorf("unable to create dnat rule [%s:%d]=>[%s:%d] with profile [%v]: [%v]",
+				return "", fmt.Errorf("unable to create dnat rule [%s:%d]=>[%s:%d] with profile [%v]: [%w]",
 					externalIP, portDetails.ExternalPort, internalIP, portDetails.InternalPort, appPortProfile, err)
 			}
 			resourcesAllocated.Insert(VcdResourceDNATRule, &swaggerClient.EntityReference{
@@ -1728,7 +1931,7 @@ func (gm *GatewayManager) CreateLoadBalancer(
 			// _acquisition_, the new externalIP can just be forgotten about.
 			dnatRuleRef, err := gm.GetNATRuleRef(ctx, dnatRuleName)
 			if err != nil {
-				return "", fmt.Errorf("unable to retrieve created dnat rule [%s]: [%v]", dnatRuleName, err)
+				return "", fmt.Errorf("unable to retrieve created dnat rule [%s]: [%w]", dnatRuleName, err)
 			}
 			if dnatRuleRef == nil {
 				return "", fmt.Errorf("retrieved dnat rule ref is nil")
@@ -1747,14 +1950,14 @@ func (gm *GatewayManager) CreateLoadBalancer(
 
 		segRef, err := gm.GetLoadBalancerSEG(ctx)
 		if err != nil {
-			return "", fmt.Errorf("unable to get service engine group from edge [%s]: [%v]",
+			return "", fmt.Errorf("unable to get service engine group from edge [%s]: [%w]",
 				gm.GatewayRef.Name, err)
 		}
 
 		lbPoolRef, err := gm.CreateLoadBalancerPool(ctx, lbPoolName, ips, portDetails.InternalPort,
 			portDetails.Protocol)
 		if err != nil {
-			return "", fmt.Errorf("unable to create load balancer pool [%s]: [%v]", lbPoolName, err)
+			return "", fmt.Errorf("unable to create load balancer pool [%s]: [%w]", lbPoolName, err)
 		}
 		resourcesAllocated.Insert(VcdResourceLoadBalancerPool, lbPoolRef)
 
@@ -1793,12 +1996,18 @@ func (gm *GatewayManager) DeleteLoadBalancer(
 		return "", fmt.Errorf("GatewayManager cannot be nil")
 	}
 
+	if gm.GatewayRef == nil {
+		return "", fmt.Errorf("gateway reference should not be nil")
+	}
+
 	client := gm.Client
-	client.RWLock.Lock()
-	defer client.RWLock.Unlock()
+	unlockService, err := client.lockService(ctx, gm.GatewayRef.Id, virtualServiceNamePrefix)
+	if err != nil {
+		return "", err
+	}
+	defer unlockService()
 
 	// TODO: try to continue in case of errors
-	var err error
 
 	// Here the principle is to delete what is available; retry in case of failure
 	// but do not fail for missing entities, since a retry will always have missing
@@ -1821,7 +2030,7 @@ func (gm *GatewayManager) DeleteLoadBalancer(
 			dnatRuleName = GetDNATRuleName(virtualServiceName)
 			dnatRuleRef, err := gm.GetNATRuleRef(ctx, dnatRuleName)
 			if err != nil {
-				return "", fmt.Errorf("unable to get dnat rule ref for nat rule [%s]: [%v]", dnatRuleName, err)
+				return "", fmt.Errorf("unable to get dnat rule ref for nat rule [%s]: [%w]", dnatRuleName, err)
 			}
 			if dnatRuleRef != nil {
 				rdeVIP = dnatRuleRef.ExternalIP
@@ -1829,7 +2038,7 @@ func (gm *GatewayManager) DeleteLoadBalancer(
 		} else {
 			vsSummary, err := gm.GetVirtualService(ctx, virtualServiceName)
 			if err != nil {
-				return "", fmt.Errorf("unable to get summary for LB Virtual Service [%s]: [%v]",
+				return "", fmt.Errorf("unable to get summary for LB Virtual Service [%s]: [%w]",
 					virtualServiceName, err)
 			}
 			if vsSummary != nil {
@@ -1844,7 +2053,7 @@ func (gm *GatewayManager) DeleteLoadBalancer(
 					virtualServiceName, err)
 				return "", vsBusyErr
 			}
-			return "", fmt.Errorf("unable to delete virtual service [%s]: [%v]", virtualServiceName, err)
+			return "", fmt.Errorf("unable to delete virtual service [%s]: [%w]", virtualServiceName, err)
 		}
 		// removal from vCDResourceSet is based on ID and type comparison.
 		virtualServiceRef := &swaggerClient.EntityReference{
@@ -1858,7 +2067,7 @@ func (gm *GatewayManager) DeleteLoadBalancer(
 				klog.Errorf("delete loadbalancer pool failed; loadbalancer pool [%s] is busy: [%v]", lbPoolName, err)
 				return "", lbPoolBusyErr
 			}
-			return "", fmt.Errorf("unable to delete load balancer pool [%s]: [%v]", lbPoolName, err)
+			return "", fmt.Errorf("unable to delete load balancer pool [%s]: [%w]", lbPoolName, err)
 		}
 		resourcesDeallocated.Insert(VcdResourceLoadBalancerPool, &swaggerClient.EntityReference{
 			Name: lbPoolName,
@@ -1867,7 +2076,7 @@ func (gm *GatewayManager) DeleteLoadBalancer(
 		if oneArm != nil {
 			err = gm.DeleteDNATRule(ctx, dnatRuleName, false)
 			if err != nil {
-				return "", fmt.Errorf("unable to delete dnat rule [%s]: [%v]", dnatRuleName, err)
+				return "", fmt.Errorf("unable to delete dnat rule [%s]: [%w]", dnatRuleName, err)
 			}
 			resourcesDeallocated.Insert(VcdResourceDNATRule, &swaggerClient.EntityReference{
 				Name: dnatRuleName,
@@ -1875,7 +2084,7 @@ func (gm *GatewayManager) DeleteLoadBalancer(
 			appPortProfileName := GetAppPortProfileName(dnatRuleName)
 			err = gm.DeleteAppPortProfile(appPortProfileName, false)
 			if err != nil {
-				return "", fmt.Errorf("unable to delete app port profile [%s]: [%v]", appPortProfileName, err)
+				return "", fmt.Errorf("unable to delete app port profile [%s]: [%w]", appPortProfileName, err)
 			}
 			resourcesDeallocated.Insert(VcdResourceAppPortProfile, &swaggerClient.EntityReference{
 				Name: appPortProfileName,
@@ -1883,41 +2092,65 @@ func (gm *GatewayManager) DeleteLoadBalancer(
 		}
 	}
 
+	if err = gm.releaseExternalIPOfLoadBalancer(ctx, rdeVIP, lbIpClaimMarker); err != nil {
+		return "", err
+	}
+
+	return rdeVIP, nil
+}
+
+// releaseExternalIPOfLoadBalancer releases the VIP claimed with lbIpClaimMarker if the gateway uses IP spaces.
+// If the gateway is using IP blocks, there is no need to explicitly release the IP.
+func (gm *GatewayManager) releaseExternalIPOfLoadBalancer(ctx context.Context, rdeVIP string, lbIpClaimMarker string) error {
+	ctx, span := startSpan(ctx, "GatewayManager.releaseExternalIPOfLoadBalancer")
+	defer span.End()
+
 	isGatewayUsingIpSpaces, err := gm.IsUsingIpSpaces()
 	if err != nil {
-		return "", fmt.Errorf("unable to release IP [%s] used by load balancer. err [%v]", rdeVIP, err)
+		return fmt.Errorf("unable to release IP [%s] used by load balancer. err [%w]", rdeVIP, err)
 	}
 	if isGatewayUsingIpSpaces {
 		klog.Infof("Determined gateway [%s] is using IP spaces, using IP space specific logic to release IP [%s]", gm.GatewayRef.Name, rdeVIP)
 		err = gm.ReleaseIpFromLoadBalancer(ctx, rdeVIP, lbIpClaimMarker)
 		if err != nil {
-			return "", fmt.Errorf("unable to release IP address [%s] from load balancer. error [%v]", rdeVIP, err)
+			return fmt.Errorf("unable to release IP address [%s] from load balancer. error [%w]", rdeVIP, err)
 		}
 	}
-	// if gateway is using IP blocks, no need to explicitly release the IP
 
-	return rdeVIP, nil
+	return nil
 }
 
-func (gm *GatewayManager) UpdateLoadBalancer(ctx context.Context, lbPoolName string, virtualServiceName string,
-	ips []string, externalIP string, internalPort int32, externalPort int32, oneArm *OneArm, enableVirtualServiceSharedIP bool, protocol string,
-	resourcesAllocated *util.AllocatedResourcesMap) (string, error) {
+// UpdateLoadBalancer updates the pool and virtual service of the port with portSuffix of the load balancer whose pools
+// and virtual services are named with lbPoolNamePrefix and virtualServiceNamePrefix, as in CreateLoadBalancer. It waits
+// for other operations on the same load balancer, such as its creation or deletion, to complete.
+func (gm *GatewayManager) UpdateLoadBalancer(ctx context.Context, lbPoolNamePrefix string, virtualServiceNamePrefix string,
+	portSuffix string, ips []string, externalIP string, internalPort int32, externalPort int32, oneArm *OneArm,
+	enableVirtualServiceSharedIP bool, protocol string, resourcesAllocated *util.AllocatedResourcesMap) (string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.UpdateLoadBalancer")
+	defer span.End()
 
 	if gm == nil {
 		return "", fmt.Errorf("GatewayManager cannot be nil")
 	}
+	if gm.GatewayRef == nil {
+		return "", fmt.Errorf("gateway reference should not be nil")
+	}
 
-	client := gm.Client
-	client.RWLock.Lock()
-	defer client.RWLock.Unlock()
+	unlockService, err := gm.Client.lockService(ctx, gm.GatewayRef.Id, virtualServiceNamePrefix)
+	if err != nil {
+		return "", err
+	}
+	defer unlockService()
 
+	lbPoolName := fmt.Sprintf("%s-%s", lbPoolNamePrefix, portSuffix)
+	virtualServiceName := fmt.Sprintf("%s-%s", virtualServiceNamePrefix, portSuffix)
 	lbPoolRef, err := gm.UpdateLoadBalancerPool(ctx, lbPoolName, ips, internalPort, protocol)
 	if err != nil {
 		if lbPoolBusyErr, ok := err.(*LoadBalancerPoolBusyError); ok {
 			klog.Errorf("update loadbalancer pool failed; loadbalancer pool [%s] is busy: [%v]", lbPoolName, err)
 			return "", lbPoolBusyErr
 		}
-		return "", fmt.Errorf("unable to update load balancer pool [%s]: [%v]", lbPoolName, err)
+		return "", fmt.Errorf("unable to update load balancer pool [%s]: [%w]", lbPoolName, err)
 	}
 	resourcesAllocated.Insert(VcdResourceLoadBalancerPool, lbPoolRef)
 	vsRef, err := gm.UpdateVirtualService(ctx, virtualServiceName, externalIP, externalPort, oneArm != nil)
@@ -1929,7 +2162,7 @@ func (gm *GatewayManager) UpdateLoadBalancer(ctx context.Context, lbPoolName str
 			klog.Errorf("update virtual service failed; virtual service [%s] is busy: [%v]", virtualServiceName, err)
 			return "", vsBusyErr
 		}
-		return "", fmt.Errorf("unable to update virtual service [%s] with port [%d]: [%v]", virtualServiceName, externalPort, err)
+		return "", fmt.Errorf("unable to update virtual service [%s] with port [%d]: [%w]", virtualServiceName, externalPort, err)
 	}
 	// The condition enableVirtualServiceSharedIP == nil, oneArm == nil is not a valid configuration.
 	// ValidateCloudConfig returns an error if CPI has the above configuration
@@ -1957,13 +2190,13 @@ func (gm *GatewayManager) UpdateLoadBalancer(ctx context.Context, lbPoolName str
 			klog.V(4).Infof("application port profile with the name [%s] is not found. skipping updating the application port profile", appPortProfileName)
 		} else {
 			// err != nil && err != govcd.ErrorEntityNotFound
-			return "", fmt.Errorf("unable to update application port profile [%s] with external port [%d]: [%v]", appPortProfileName, externalPort, err)
+			return "", fmt.Errorf("unable to update application port profile [%s] with external port [%d]: [%w]", appPortProfileName, externalPort, err)
 		}
 
 		// update DNAT rule
 		dnatRuleRef, err := gm.GetNATRuleRef(ctx, dnatRuleName)
 		if err != nil {
-			return "", fmt.Errorf("unable to retrieve created dnat rule [%s]: [%v]", dnatRuleName, err)
+			return "", fmt.Errorf("unable to retrieve created dnat rule [%s]: [%w]", dnatRuleName, err)
 		}
 		dnatExternalIP := externalIP
 		if dnatExternalIP == "" {
@@ -1971,7 +2204,7 @@ func (gm *GatewayManager) UpdateLoadBalancer(ctx context.Context, lbPoolName str
 		}
 		updatedDnatRule, err := gm.UpdateDNATRule(ctx, dnatRuleName, dnatExternalIP, dnatRuleRef.InternalIP, externalPort)
 		if err != nil {
-			return "", fmt.Errorf("unable to update DNAT rule [%s]: [%v]", dnatRuleName, err)
+			return "", fmt.Errorf("unable to update DNAT rule [%s]: [%w]", dnatRuleName, err)
 		}
 		resourcesAllocated.Insert(VcdResourceDNATRule, &swaggerClient.EntityReference{
 			Name: dnatRuleName,
@@ -1991,35 +2224,29 @@ func (gm *GatewayManager) UpdateLoadBalancer(ctx context.Context, lbPoolName str
 // FetchIpSpacesBackingGateway Fetch list of Ip Spaces (Id) accessible to the gateway
 // If gateway is not using Ip Spaces, error would be generated that will contain the underlying VCD 403 error.
 func (gm *GatewayManager) FetchIpSpacesBackingGateway(ctx context.Context) ([]string, error) {
-	ipSpaceService := gm.Client.APIClient.IpSpacesApi
+	ctx, span := startSpan(ctx, "GatewayManager.FetchIpSpacesBackingGateway")
+	defer span.End()
+
+	session := gm.Client.Session()
+	ipSpaceService := session.APIClient.IpSpacesApi
 
 	filterString := fmt.Sprintf("gatewayId==%s", gm.GatewayRef.Id)
 	options := swaggerClient.IpSpacesApiGetFloatingIpSuggestionsOpts{Filter: optional.NewString(filterString), SortAsc: optional.NewString("ipSpaceRef.name"), SortDesc: optional.EmptyString()}
-	var pageSize int32 = 10
-	var pageNum int32 = 1
-	var resultTotal int32 = math.MaxInt32
+	suggestions, err := listAllPages(pageNumberPagination, 10,
+		func(page pageRequest) ([]swaggerClient.IpSpaceFloatingIpSuggestion, int32, *http.Response, error) {
+			suggestions, resp, err := ipSpaceService.GetFloatingIpSuggestions(ctx, page.PageNum, page.PageSize, &options)
+			return suggestions.Values, suggestions.PageCount, resp, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get floating IP suggestion for gateway [%s] : [%w]", gm.GatewayRef.Name, err)
+	}
 
 	ipSpaceIds := make([]string, 0)
-	for int32(math.Ceil(float64(resultTotal)/float64(pageSize))) >= pageNum {
-		suggestions, _, err := ipSpaceService.GetFloatingIpSuggestions(ctx, pageNum, pageSize, &options)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get floating IP suggestion for gateway [%s] : [%v]", gm.GatewayRef.Name, err)
+	for _, element := range suggestions {
+		ipSpaceRef := element.IpSpaceRef
+		if ipSpaceRef != nil {
+			ipSpaceIds = append(ipSpaceIds, ipSpaceRef.Id)
 		}
-		resultTotal = suggestions.ResultTotal
-
-		// The way swagger client works, it is guaranteed that suggestions.Values will never be nil,
-		// at worst it will be [], in which case we should bail out and not try to fetch more pages.
-		if len(suggestions.Values) == 0 {
-			break
-		}
-		for _, element := range suggestions.Values {
-			ipSpaceRef := element.IpSpaceRef
-			if ipSpaceRef != nil {
-				ipSpaceIds = append(ipSpaceIds, ipSpaceRef.Id)
-			}
-		}
-
-		pageNum += 1
 	}
 
 	return ipSpaceIds, nil
@@ -2028,6 +2255,7 @@ func (gm *GatewayManager) FetchIpSpacesBackingGateway(ctx context.Context) ([]st
 // FilterIpSpacesByType Takes in a list of Ip Space Ids and returns a list of govcd.IpSpace pointers that match the
 // filter value of "type". Valid value for "type" = ["PUBLIC" (types.IpSpacePublic), "PRIVATE" (types.IpSpacePrivate)]
 func (gm *GatewayManager) FilterIpSpacesByType(ipSpaceIds []string, ipSpaceType string) ([]*govcd.IpSpace, error) {
+	session := gm.Client.Session()
 	if (ipSpaceType != types.IpSpacePublic) && (ipSpaceType != types.IpSpacePrivate) {
 		return nil, fmt.Errorf("invalid type [%s] specified, expecting value from [PUBLIC, PRIVATE]", ipSpaceType)
 	}
@@ -2037,9 +2265,9 @@ func (gm *GatewayManager) FilterIpSpacesByType(ipSpaceIds []string, ipSpaceType
 
 	filteredIpSpaces := make([]*govcd.IpSpace, 0)
 	for _, ipSpaceId := range ipSpaceIds {
-		ipSpace, err := gm.Client.VCDClient.GetIpSpaceById(ipSpaceId)
+		ipSpace, err := session.VCDClient.GetIpSpaceById(ipSpaceId)
 		if err != nil {
-			return nil, fmt.Errorf("unable to fetch details of Ip Space with id [%s], error [%v]", ipSpaceId, err)
+			return nil, fmt.Errorf("unable to fetch details of Ip Space with id [%s], error [%w]", ipSpaceId, err)
 		}
 		if ipSpace.IpSpace.Type == ipSpaceType {
 			filteredIpSpaces = append(filteredIpSpaces, ipSpace)
@@ -2058,9 +2286,9 @@ func (gm *GatewayManager) AllocateIpFromIpSpace(ipSpace *govcd.IpSpace) (string,
 		return "", "", fmt.Errorf("unable to allocate floating Ip from Ip Space [nil]")
 	}
 
-	org, err := gm.Client.VCDClient.GetOrgByName(gm.Client.ClusterOrgName)
+	org, err := gm.Client.GetOrgByName(gm.Client.ClusterOrgName)
 	if err != nil {
-		return "", "", fmt.Errorf("unable to allocate floating Ip from Ip Space [%s]. error [%v]", ipSpace.IpSpace.Name, err)
+		return "", "", fmt.Errorf("unable to allocate floating Ip from Ip Space [%s]. error [%w]", ipSpace.IpSpace.Name, err)
 	}
 
 	val := 1
@@ -2071,7 +2299,7 @@ func (gm *GatewayManager) AllocateIpFromIpSpace(ipSpace *govcd.IpSpace) (string,
 	result, err := org.IpSpaceAllocateIp(ipSpace.IpSpace.ID, &request)
 
 	if err != nil {
-		return "", "", fmt.Errorf("unable to allocate floating IP from Ip Space [%s]. error [%v]", ipSpace.IpSpace.Name, err)
+		return "", "", fmt.Errorf("unable to allocate floating IP from Ip Space [%s]. error [%w]", ipSpace.IpSpace.Name, err)
 	}
 
 	if result == nil || len(result) != 1 {
@@ -2092,7 +2320,7 @@ func (gm *GatewayManager) FindIpAllocationByIp(ipSpace *govcd.IpSpace, allocated
 
 	allocations, err := ipSpace.GetAllIpSpaceAllocations(types.IpSpaceIpAllocationTypeFloatingIp, queryParams)
 	if err != nil {
-		return nil, fmt.Errorf("unable to find allocation in Ip Space [%s], correspnding to Ip [%s]. error [%v]", ipSpace.IpSpace.Name, allocatedIp, err)
+		return nil, fmt.Errorf("unable to find allocation in Ip Space [%s], correspnding to Ip [%s]. error [%w]", ipSpace.IpSpace.Name, allocatedIp, err)
 	}
 
 	// If no allocations were made on the Ip Space with the specified Ip, it is valid to return no result with no error
@@ -2121,7 +2349,7 @@ func (gm *GatewayManager) FindIpAllocationByMarker(ipSpace *govcd.IpSpace, marke
 
 	allocations, err := ipSpace.GetAllIpSpaceAllocations(types.IpSpaceIpAllocationTypeFloatingIp, queryParams)
 	if err != nil {
-		return nil, fmt.Errorf("unable to find allocation in Ip Space [%s], correspnding to marker [%s]. error [%v]", ipSpace.IpSpace.Name, marker, err)
+		return nil, fmt.Errorf("unable to find allocation in Ip Space [%s], correspnding to marker [%s]. error [%w]", ipSpace.IpSpace.Name, marker, err)
 	}
 
 	// If no allocations were made on the Ip Space, it is valid to return no result without error
@@ -2181,20 +2409,23 @@ func (gm *GatewayManager) ReleaseIp(ipSpaceAllocation *govcd.IpSpaceIpAllocation
 // description will be updated to mark a claim. The allocated Ip will be returned. If all Ip Spaces reject the
 // allocation request, this method will return an error
 func (gm *GatewayManager) ReserveIpForLoadBalancer(ctx context.Context, claimMarker string) (string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.ReserveIpForLoadBalancer")
+	defer span.End()
+
 	ipSpaceIds, err := gm.FetchIpSpacesBackingGateway(ctx)
 	if err != nil {
-		return "", fmt.Errorf("unable to reserve IP from Ip Space. error [%v]", err)
+		return "", fmt.Errorf("unable to reserve IP from Ip Space. error [%w]", err)
 	}
 
 	publicIpSpaces, err := gm.FilterIpSpacesByType(ipSpaceIds, types.IpSpacePublic)
 	if err != nil {
-		return "", fmt.Errorf("unable to reserve IP from Ip Space. error [%v]", err)
+		return "", fmt.Errorf("unable to reserve IP from Ip Space. error [%w]", err)
 	}
 
 	for _, ipSpace := range publicIpSpaces {
 		ipSpaceAllocation, err := gm.FindIpAllocationByMarker(ipSpace, claimMarker)
 		if err != nil {
-			return "", fmt.Errorf("unable to reserve IP from Ip Space [%s]. error [%v]", ipSpace.IpSpace.Name, err)
+			return "", fmt.Errorf("unable to reserve IP from Ip Space [%s]. error [%w]", ipSpace.IpSpace.Name, err)
 		}
 		// Found an existing allocation for this particular service
 		if ipSpaceAllocation != nil {
@@ -2225,13 +2456,50 @@ func (gm *GatewayManager) ReserveIpForLoadBalancer(ctx context.Context, claimMar
 		_, err = gm.MarkIpAsUsed(ipSpaceAllocation, claimMarker)
 		if err != nil {
 			klog.Infof("leaked IP [%s] from Ip Space [%s]. Unable to mark allocated IP as used.", allocatedIp, ipSpace.IpSpace.Name)
-			return "", fmt.Errorf("unable to reserve IP from Ip Space [%s]. error [%v]", ipSpace.IpSpace.Name, err)
+			return "", fmt.Errorf("unable to reserve IP from Ip Space [%s]. error [%w]", ipSpace.IpSpace.Name, err)
 		}
+		observeIPPoolExhaustion(gm.GatewayRef.Name, ipPoolExternal, false)
 		return ipSpaceAllocation.IpSpaceIpAllocation.Value, nil
 	}
 
 	// Was unable to reserve an Ip on any of the available Ip Spaces
-	return "", fmt.Errorf("unable to reserve Ip from any available Ip spaces")
+	observeIPPoolExhaustion(gm.GatewayRef.Name, ipPoolExternal, true)
+	return "", NewVCDError(VCDErrorQuotaExceeded, fmt.Errorf("unable to reserve Ip from any available Ip spaces"))
+}
+
+// getClaimedIpOfLoadBalancer returns the IP allocated from a public IP space of the gateway that is claimed with
+// claimMarker. It returns an empty string if the gateway does not use IP spaces or if no IP is claimed with the marker.
+func (gm *GatewayManager) getClaimedIpOfLoadBalancer(ctx context.Context, claimMarker string) (string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.getClaimedIpOfLoadBalancer")
+	defer span.End()
+
+	isGatewayUsingIpSpaces, err := gm.IsUsingIpSpaces()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine if gateway [%s] is using IP spaces: [%w]", gm.GatewayRef.Name, err)
+	}
+	if !isGatewayUsingIpSpaces {
+		return "", nil
+	}
+
+	ipSpaceIds, err := gm.FetchIpSpacesBackingGateway(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to get IP spaces of gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
+	publicIpSpaces, err := gm.FilterIpSpacesByType(ipSpaceIds, types.IpSpacePublic)
+	if err != nil {
+		return "", fmt.Errorf("unable to get public IP spaces of gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
+	for _, ipSpace := range publicIpSpaces {
+		ipSpaceAllocation, err := gm.FindIpAllocationByMarker(ipSpace, claimMarker)
+		if err != nil {
+			return "", fmt.Errorf("unable to find IP claimed with marker [%s] in IP space [%s]: [%w]", claimMarker,
+				ipSpace.IpSpace.Name, err)
+		}
+		if ipSpaceAllocation != nil {
+			return ipSpaceAllocation.IpSpaceIpAllocation.Value, nil
+		}
+	}
+	return "", nil
 }
 
 // ReleaseIpFromLoadBalancer will scan through all Ip Spaces available to the gateway for an existing allocation
@@ -2239,37 +2507,42 @@ func (gm *GatewayManager) ReserveIpForLoadBalancer(ctx context.Context, claimMar
 // retrieved, the method will return without raising any error. It should be assumed that the allocation was removed in
 // a previous attempt. If an allocation is found, it will be deleted, thereby releasing the IP from the load balancer as well
 // as tenant context. It should be noted that if the cluster was created with user provider external IP, then the allocation
-// will not be present on any of the IP Spaces, and hence we will not try to release the IP.
+// will not be present on any of the IP Spaces, and hence we will not try to release the IP. If rdeVIP is empty, the
+// allocation with claimMarker is released whatever its IP.
 func (gm *GatewayManager) ReleaseIpFromLoadBalancer(ctx context.Context, rdeVIP string, claimMarker string) error {
+	ctx, span := startSpan(ctx, "GatewayManager.ReleaseIpFromLoadBalancer")
+	defer span.End()
+
 	ipSpaceIds, err := gm.FetchIpSpacesBackingGateway(ctx)
 	if err != nil {
-		return fmt.Errorf("unable to release IP [%s] from load balancer. error [%v]", rdeVIP, err)
+		return fmt.Errorf("unable to release IP [%s] from load balancer. error [%w]", rdeVIP, err)
 	}
 
 	publicIpSpaces, err := gm.FilterIpSpacesByType(ipSpaceIds, types.IpSpacePublic)
 	if err != nil {
-		return fmt.Errorf("unable to release IP [%s] from load balancer. error [%v]", rdeVIP, err)
+		return fmt.Errorf("unable to release IP [%s] from load balancer. error [%w]", rdeVIP, err)
 	}
 
 	for _, ipSpace := range publicIpSpaces {
 		ipSpaceAllocation, err := gm.FindIpAllocationByMarker(ipSpace, claimMarker)
 		if err != nil {
-			return fmt.Errorf("unable to release IP [%s] from Ip Space [%s]. error [%v]", rdeVIP, ipSpace.IpSpace.Name, err)
+			return fmt.Errorf("unable to release IP [%s] from Ip Space [%s]. error [%w]", rdeVIP, ipSpace.IpSpace.Name, err)
 		}
 		// In case an allocation is not found on this Ip Space, we will have ipSpaceAllocation = nil, err = nil
 		if ipSpaceAllocation != nil {
 			// Found an existing allocation for this particular service
 			allocatedIp := ipSpaceAllocation.IpSpaceIpAllocation.Value
-			if allocatedIp != rdeVIP {
+			// if the VIP is not known, e.g. since the load balancer was partially deleted, the marker identifies the IP
+			if rdeVIP != "" && allocatedIp != rdeVIP {
 				return fmt.Errorf("RDE VIP [%s] doesn't match allocated IP [%s] in IP Space [%s] for marker [%s]", rdeVIP, allocatedIp, ipSpace.IpSpace.Name, claimMarker)
 			}
 			updatedIpSpaceAllocation, err := gm.MarkIpAsUnused(ipSpaceAllocation)
 			if err != nil {
-				return fmt.Errorf("unable to mark IP [%s] from IP Space [%s] as unused. error [%v]", allocatedIp, ipSpace.IpSpace.Name, err)
+				return fmt.Errorf("unable to mark IP [%s] from IP Space [%s] as unused. error [%w]", allocatedIp, ipSpace.IpSpace.Name, err)
 			}
 			err = gm.ReleaseIp(updatedIpSpaceAllocation)
 			if err != nil {
-				return fmt.Errorf("unable to release IP [%s] from IP Space [%s]. error [%v]", allocatedIp, ipSpace.IpSpace.Name, err)
+				return fmt.Errorf("unable to release IP [%s] from IP Space [%s]. error [%w]", allocatedIp, ipSpace.IpSpace.Name, err)
 			}
 			// No need to process any more IP spaces, it is safe to return from this function
 			return nil
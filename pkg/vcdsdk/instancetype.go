@@ -0,0 +1,60 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+const (
+	// SystemDefaultComputePolicyName is the sizing policy VCD assigns to VMs that were not given one
+	SystemDefaultComputePolicyName = "System Default"
+)
+
+// GetSynthesizedInstanceType returns an instance type of the form <vcpu>c-<memGiB>g, e.g. 4c-16g or 2c-1.5g,
+// for VMs that have no sizing policy.
+func GetSynthesizedInstanceType(numCpus int, memoryMB int64) string {
+	memoryGiB := strconv.FormatFloat(float64(memoryMB)/1024, 'f', -1, 64)
+	return fmt.Sprintf("%dc-%sg", numCpus, memoryGiB)
+}
+
+// GetComputePolicyRefID returns the URN of the compute policy referred to by computePolicyRef.
+func GetComputePolicyRefID(computePolicyRef *types.Reference) string {
+	if computePolicyRef == nil {
+		return ""
+	}
+	if computePolicyRef.ID != "" {
+		return computePolicyRef.ID
+	}
+	// the href ends in the URN of the policy
+	if idx := strings.LastIndex(computePolicyRef.HREF, "/"); idx >= 0 {
+		return computePolicyRef.HREF[idx+1:]
+	}
+	return computePolicyRef.HREF
+}
+
+// GetVMSizingPolicyID returns the URN of the sizing policy of vm, or an empty string if it has none.
+func GetVMSizingPolicyID(vm *types.Vm) string {
+	if vm == nil || vm.ComputePolicy == nil {
+		return ""
+	}
+	return GetComputePolicyRefID(vm.ComputePolicy.VmSizingPolicy)
+}
+
+// GetVMSynthesizedInstanceType returns the synthesized instance type of vm from its VM spec, or an empty
+// string if the spec is not available.
+func GetVMSynthesizedInstanceType(vm *types.Vm) string {
+	if vm == nil || vm.VmSpecSection == nil || vm.VmSpecSection.NumCpus == nil ||
+		vm.VmSpecSection.MemoryResourceMb == nil {
+		return ""
+	}
+
+	return GetSynthesizedInstanceType(*vm.VmSpecSection.NumCpus, vm.VmSpecSection.MemoryResourceMb.Configured)
+}
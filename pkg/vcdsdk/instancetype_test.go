@@ -0,0 +1,57 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+func TestGetVMSynthesizedInstanceType(t *testing.T) {
+
+	numCpus := 4
+	vm := &types.Vm{
+		VmSpecSection: &types.VmSpecSection{
+			NumCpus: &numCpus,
+			MemoryResourceMb: &types.MemoryResourceMb{
+				Configured: 16384,
+			},
+		},
+	}
+	assert.Equal(t, "4c-16g", GetVMSynthesizedInstanceType(vm), "unexpected instance type for whole GiB")
+
+	vm.VmSpecSection.MemoryResourceMb.Configured = 1536
+	assert.Equal(t, "4c-1.5g", GetVMSynthesizedInstanceType(vm), "unexpected instance type for fractional GiB")
+
+	assert.Equal(t, "", GetVMSynthesizedInstanceType(&types.Vm{}), "VM without spec should have no instance type")
+
+	return
+}
+
+func TestGetVMSizingPolicyID(t *testing.T) {
+
+	policyURN := "urn:vcloud:vdcComputePolicy:2a3b8d8c-5d6e-4f1a-9a3b-1c2d3e4f5a6b"
+
+	assert.Equal(t, "", GetVMSizingPolicyID(&types.Vm{}), "VM without compute policy should have no sizing policy")
+
+	vm := &types.Vm{
+		ComputePolicy: &types.ComputePolicy{
+			VmSizingPolicy: &types.Reference{
+				ID: policyURN,
+			},
+		},
+	}
+	assert.Equal(t, policyURN, GetVMSizingPolicyID(vm), "sizing policy should be obtained from the ID")
+
+	vm.ComputePolicy.VmSizingPolicy = &types.Reference{
+		HREF: "https://vcd.example.com/cloudapi/2.0.0/vdcComputePolicies/" + policyURN,
+	}
+	assert.Equal(t, policyURN, GetVMSizingPolicyID(vm), "sizing policy should be obtained from the href")
+
+	return
+}
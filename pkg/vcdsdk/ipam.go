@@ -10,8 +10,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/apparentlymart/go-cidr/cidr"
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
 	"k8s.io/klog"
 	"net"
+	"net/http"
+	"sync"
 )
 
 type IPRange struct {
@@ -19,11 +22,97 @@ type IPRange struct {
 	EndIP   string
 }
 
+// pendingIPs are the IPs of gateways that were allocated to load balancers by operations that are in progress, and
+// that may not be used by a NAT rule or virtual service of VCD yet.
+type pendingIPs struct {
+	lock sync.Mutex
+	ips  map[string]map[string]int
+}
+
+func (p *pendingIPs) add(gatewayID string, ip string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.ips == nil {
+		p.ips = make(map[string]map[string]int)
+	}
+	if p.ips[gatewayID] == nil {
+		p.ips[gatewayID] = make(map[string]int)
+	}
+	p.ips[gatewayID][ip]++
+}
+
+func (p *pendingIPs) remove(gatewayID string, ip string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.ips[gatewayID][ip] <= 1 {
+		delete(p.ips[gatewayID], ip)
+	} else {
+		p.ips[gatewayID][ip]--
+	}
+	if len(p.ips[gatewayID]) == 0 {
+		delete(p.ips, gatewayID)
+	}
+}
+
+// addTo marks the pending IPs of the gateway with gatewayID as used in usedIPAddresses.
+func (p *pendingIPs) addTo(gatewayID string, usedIPAddresses map[string]bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for ip := range p.ips[gatewayID] {
+		usedIPAddresses[ip] = true
+	}
+}
+
+// ipClaims are the IPs that an operation on a load balancer allocated from the pools of a gateway. They are pending
+// until the operation ends, by which time they are used by the NAT rules and virtual services that it created.
+type ipClaims struct {
+	gm  *GatewayManager
+	ips []string
+}
+
+func (gm *GatewayManager) newIPClaims() *ipClaims {
+	return &ipClaims{
+		gm: gm,
+	}
+}
+
+// allocate runs allocateFunc in the critical section of the IP allocations on the gateway, and holds the IP that it
+// returns as pending until release is called, so that concurrent allocations on the gateway do not return it again.
+func (claims *ipClaims) allocate(ctx context.Context, allocateFunc func(ctx context.Context) (string, error)) (string, error) {
+	gm := claims.gm
+	unlock, err := gm.Client.ipAllocationLocks.acquire(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return "", fmt.Errorf("gave up waiting for IP allocations on gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
+	defer unlock()
+
+	ip, err := allocateFunc(ctx)
+	if err != nil {
+		return "", err
+	}
+	gm.Client.pendingIPs.add(gm.GatewayRef.Id, ip)
+	claims.ips = append(claims.ips, ip)
+	return ip, nil
+}
+
+// release ends the pending state of the allocated IPs. It should be called when the operation ends, whether the IPs
+// were used or not.
+func (claims *ipClaims) release() {
+	for _, ip := range claims.ips {
+		claims.gm.Client.pendingIPs.remove(claims.gm.GatewayRef.Id, ip)
+	}
+	claims.ips = nil
+}
+
 // GetUnusedExternalIPAddress returns the first unused IP address in the gateway from an ipamSubnet
-// There are races here since there is no 'acquisition' of an IP. However, since k8s retries, it will
-// be correct.
+// There is no 'acquisition' of an IP in VCD; IPs that were allocated by load balancer operations of the client that
+// are in progress are skipped, and since k8s retries, races with other clients will be corrected.
 func (gm *GatewayManager) GetUnusedExternalIPAddress(ctx context.Context, allowedIPAMSubnetStr string) (string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.GetUnusedExternalIPAddress")
+	defer span.End()
+
 	client := gm.Client
+	session := client.Session()
 	if gm.GatewayRef == nil {
 		return "", fmt.Errorf("gateway reference should not be nil")
 	}
@@ -41,17 +130,17 @@ func (gm *GatewayManager) GetUnusedExternalIPAddress(ctx context.Context, allowe
 	// 		4. Check if the IP address is unused
 	// Note: This is not the best approach and can be optimized further by skipping ranges.
 
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return "", fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
+		return "", fmt.Errorf("unable to get org for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if clusterOrg == nil || clusterOrg.Org == nil {
 		return "", fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
 	// 1. Get all IP ranges in gateway
-	edgeGW, resp, err := client.APIClient.EdgeGatewayApi.GetEdgeGateway(ctx, gm.GatewayRef.Id, clusterOrg.Org.ID)
+	edgeGW, resp, err := session.APIClient.EdgeGatewayApi.GetEdgeGateway(ctx, gm.GatewayRef.Id, clusterOrg.Org.ID)
 	if err != nil {
-		return "", fmt.Errorf("unable to retrieve edge gateway details for [%s]: resp [%+v]: [%v]",
+		return "", fmt.Errorf("unable to retrieve edge gateway details for [%s]: resp [%+v]: [%w]",
 			gm.GatewayRef.Name, resp, err)
 	}
 
@@ -79,31 +168,27 @@ func (gm *GatewayManager) GetUnusedExternalIPAddress(ctx context.Context, allowe
 	}
 
 	// 2. Get all used IP addresses in gateway
+	gwUsedIPAddresses, err := listAllPages(pageNumberPagination, DefaultPageSize,
+		func(page pageRequest) ([]swaggerClient.GatewayUsedIpAddress, int32, *http.Response, error) {
+			gwUsedIPAddresses, resp, err := session.APIClient.EdgeGatewayApi.GetUsedIpAddresses(ctx, page.PageNum,
+				page.PageSize, gm.GatewayRef.Id, clusterOrg.Org.ID, nil)
+			return gwUsedIPAddresses.Values, gwUsedIPAddresses.PageCount, resp, err
+		})
+	if err != nil {
+		return "", fmt.Errorf("unable to get used IP addresses of gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
 	usedIPAddresses := make(map[string]bool)
-	pageNum := int32(1)
-	for {
-		gwUsedIPAddresses, resp, err := client.APIClient.EdgeGatewayApi.GetUsedIpAddresses(ctx, pageNum, 25,
-			gm.GatewayRef.Id, clusterOrg.Org.ID, nil)
-		if err != nil {
-			return "", fmt.Errorf("unable to get used IP addresses of gateway [%s]: [%+v]: [%v]",
-				gm.GatewayRef.Name, resp, err)
-		}
-		if len(gwUsedIPAddresses.Values) == 0 {
-			break
-		}
-
-		for _, gwUsedIPAddress := range gwUsedIPAddresses.Values {
-			usedIPAddresses[gwUsedIPAddress.IpAddress] = true
-		}
-
-		pageNum++
+	for _, gwUsedIPAddress := range gwUsedIPAddresses {
+		usedIPAddresses[gwUsedIPAddress.IpAddress] = true
 	}
+	// IPs allocated by operations that are in progress may not be used in VCD yet
+	client.pendingIPs.addTo(gm.GatewayRef.Id, usedIPAddresses)
 
 	freeIP := ""
 	if allowedIPAMSubnetStr != "" {
 		_, allowedIPAMSubnet, err := net.ParseCIDR(allowedIPAMSubnetStr)
 		if err != nil {
-			return "", fmt.Errorf("unable to parse CIDR [%s] into a subnet: [%v]", allowedIPAMSubnetStr, err)
+			return "", fmt.Errorf("unable to parse CIDR [%s] into a subnet: [%w]", allowedIPAMSubnetStr, err)
 		}
 		allowedStartIP, allowedEndIP := cidr.AddressRange(allowedIPAMSubnet)
 		// 3. Loop through allowed ip addresses in the user-specified IPAM Subnet
@@ -122,14 +207,15 @@ func (gm *GatewayManager) GetUnusedExternalIPAddress(ctx context.Context, allowe
 		// 3-4 performed in the following function
 		freeIP, err = getUnusedIPAddressInRange(usedIPAddresses, ipRangeList)
 		if err != nil {
-			return "", fmt.Errorf("unable to find unused IP in IP ranges [%v]: [%v]",
+			return "", fmt.Errorf("unable to find unused IP in IP ranges [%v]: [%w]",
 				ipRangeList, err)
 		}
 	}
 
+	observeIPPoolExhaustion(gm.GatewayRef.Name, ipPoolExternal, freeIP == "")
 	if freeIP == "" {
-		return "", fmt.Errorf("unable to obtain free IP from gateway [%s]; all are used",
-			gm.GatewayRef.Name)
+		return "", NewVCDError(VCDErrorQuotaExceeded, fmt.Errorf(
+			"unable to obtain free IP from gateway [%s]; all are used", gm.GatewayRef.Name))
 	}
 	klog.Infof("Using unused IP [%s] on gateway [%v]\n", freeIP, gm.GatewayRef.Name)
 
@@ -137,6 +223,9 @@ func (gm *GatewayManager) GetUnusedExternalIPAddress(ctx context.Context, allowe
 }
 
 func (gm *GatewayManager) GetUnusedInternalIPAddress(ctx context.Context, oneArm *OneArm) (string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.GetUnusedInternalIPAddress")
+	defer span.End()
+
 	if oneArm == nil {
 		return "", fmt.Errorf("unable to get unused internal IP address as oneArm is nil")
 	}
@@ -145,42 +234,42 @@ func (gm *GatewayManager) GetUnusedInternalIPAddress(ctx context.Context, oneArm
 		return "", fmt.Errorf("gateway reference should not be nil")
 	}
 	client := gm.Client
+	session := client.Session()
 
-	clusterOrg, err := client.VCDClient.GetOrgByName(client.ClusterOrgName)
+	clusterOrg, err := client.GetOrgByName(client.ClusterOrgName)
 	if err != nil {
-		return "", fmt.Errorf("unable to get org for org [%s]: [%v]", client.ClusterOrgName, err)
+		return "", fmt.Errorf("unable to get org for org [%s]: [%w]", client.ClusterOrgName, err)
 	}
 	if clusterOrg == nil || clusterOrg.Org == nil {
 		return "", fmt.Errorf("obtained nil org for name [%s]", client.ClusterOrgName)
 	}
 
+	lbVSSummaries, err := listAllPages(pageNumberPagination, DefaultPageSize,
+		func(page pageRequest) ([]swaggerClient.EdgeLoadBalancerVirtualServiceSummary, int32, *http.Response, error) {
+			lbVSSummaries, resp, err := session.APIClient.EdgeGatewayLoadBalancerVirtualServicesApi.GetVirtualServiceSummariesForGateway(
+				ctx, page.PageNum, page.PageSize, gm.GatewayRef.Id, clusterOrg.Org.ID, nil)
+			return lbVSSummaries.Values, lbVSSummaries.PageCount, resp, err
+		})
+	if err != nil {
+		return "", fmt.Errorf("unable to get virtual service summaries for gateway [%s]: [%w]",
+			gm.GatewayRef.Name, err)
+	}
 	usedIPAddresses := make(map[string]bool)
-	pageNum := int32(1)
-	for {
-		lbVSSummaries, resp, err := client.APIClient.EdgeGatewayLoadBalancerVirtualServicesApi.GetVirtualServiceSummariesForGateway(
-			ctx, pageNum, 25, gm.GatewayRef.Id, clusterOrg.Org.ID, nil)
-		if err != nil {
-			return "", fmt.Errorf("unable to get virtual service summaries for gateway [%s]: resp: [%v]: [%v]",
-				gm.GatewayRef.Name, resp, err)
-		}
-		if len(lbVSSummaries.Values) == 0 {
-			break
-		}
-		for _, lbVSSummary := range lbVSSummaries.Values {
-			usedIPAddresses[lbVSSummary.VirtualIpAddress] = true
-		}
-
-		pageNum++
+	for _, lbVSSummary := range lbVSSummaries {
+		usedIPAddresses[lbVSSummary.VirtualIpAddress] = true
 	}
+	// IPs allocated by operations that are in progress may not be used in VCD yet
+	client.pendingIPs.addTo(gm.GatewayRef.Id, usedIPAddresses)
 
 	freeIP, err := getUnusedIPAddressInAllowedRange(oneArm.StartIP, oneArm.EndIP, usedIPAddresses, nil)
 	if err != nil {
-		return "", fmt.Errorf("error in finding unused IP address in range [%s-%s]: [%v]",
+		return "", fmt.Errorf("error in finding unused IP address in range [%s-%s]: [%w]",
 			oneArm.StartIP, oneArm.EndIP, err)
 	}
+	observeIPPoolExhaustion(gm.GatewayRef.Name, ipPoolInternal, freeIP == "")
 	if freeIP == "" {
-		return "", fmt.Errorf("unable to find unused IP address in range [%s-%s]",
-			oneArm.StartIP, oneArm.EndIP)
+		return "", NewVCDError(VCDErrorQuotaExceeded, fmt.Errorf(
+			"unable to find unused IP address in range [%s-%s]", oneArm.StartIP, oneArm.EndIP))
 	}
 
 	return freeIP, nil
@@ -190,12 +279,12 @@ func getUnusedIPAddressInRange(usedIPAddresses map[string]bool, ipRangeList []IP
 	for _, ipRange := range ipRangeList {
 		startIP, _, err := net.ParseCIDR(fmt.Sprintf("%s/32", ipRange.StartIP))
 		if err != nil {
-			return "", fmt.Errorf("unable to parse start IP CIDR of range [%v]: [%v]", ipRange, err)
+			return "", fmt.Errorf("unable to parse start IP CIDR of range [%v]: [%w]", ipRange, err)
 		}
 
 		endIP, _, err := net.ParseCIDR(fmt.Sprintf("%s/32", ipRange.EndIP))
 		if err != nil {
-			return "", fmt.Errorf("unable to parse start IP CIDR of range [%v]: [%v]", ipRange, err)
+			return "", fmt.Errorf("unable to parse start IP CIDR of range [%v]: [%w]", ipRange, err)
 		}
 		endIP = cidr.Inc(endIP)
 
@@ -221,12 +310,12 @@ func getUnusedIPAddressInAllowedRange(startIPAddress string, endIPAddress string
 
 	startIP, _, err := net.ParseCIDR(fmt.Sprintf("%s/32", startIPAddress))
 	if err != nil {
-		return "", fmt.Errorf("unable to parse start IP CIDR [%s]: [%v]", startIPAddress, err)
+		return "", fmt.Errorf("unable to parse start IP CIDR [%s]: [%w]", startIPAddress, err)
 	}
 
 	endIP, _, err := net.ParseCIDR(fmt.Sprintf("%s/32", endIPAddress))
 	if err != nil {
-		return "", fmt.Errorf("unable to parse end IP CIDR [%s]: [%v]", endIPAddress, err)
+		return "", fmt.Errorf("unable to parse end IP CIDR [%s]: [%w]", endIPAddress, err)
 	}
 	endIP = cidr.Inc(endIP)
 
@@ -6,7 +6,10 @@
 package vcdsdk
 
 import (
+	"context"
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
 	"testing"
 )
 
@@ -265,3 +268,40 @@ func TestCheckIfIPIsAvailable(t *testing.T) {
 
 	return
 }
+
+func TestIPClaims(t *testing.T) {
+
+	gm := &GatewayManager{
+		Client:     &Client{},
+		GatewayRef: &swaggerClient.EntityReference{Name: "gateway1", Id: "urn:vcloud:gateway:1"},
+	}
+	ipRangeList := []IPRange{{StartIP: "10.0.0.1", EndIP: "10.0.0.3"}}
+	allocate := func(ctx context.Context) (string, error) {
+		usedIPAddresses := map[string]bool{"10.0.0.1": true}
+		gm.Client.pendingIPs.addTo(gm.GatewayRef.Id, usedIPAddresses)
+		return getUnusedIPAddressInRange(usedIPAddresses, ipRangeList)
+	}
+
+	claims := gm.newIPClaims()
+	ip, err := claims.allocate(context.Background(), allocate)
+	assert.NoError(t, err, "first allocation should succeed")
+	assert.Equal(t, "10.0.0.2", ip, "first unused IP should be allocated")
+
+	otherClaims := gm.newIPClaims()
+	ip, err = otherClaims.allocate(context.Background(), allocate)
+	assert.NoError(t, err, "concurrent allocation should succeed")
+	assert.Equal(t, "10.0.0.3", ip, "IP pending for another operation should be skipped")
+
+	_, err = otherClaims.allocate(context.Background(), func(ctx context.Context) (string, error) {
+		return "", fmt.Errorf("no free IP")
+	})
+	assert.Error(t, err, "error of allocation should be returned")
+
+	claims.release()
+	ip, err = gm.newIPClaims().allocate(context.Background(), allocate)
+	assert.NoError(t, err, "allocation after release should succeed")
+	assert.Equal(t, "10.0.0.2", ip, "released IP should be allocated again")
+
+	otherClaims.release()
+	return
+}
@@ -0,0 +1,118 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/klog"
+)
+
+// keyedLimiter limits the number of operations that run at the same time, per key. The zero value allows one
+// operation per key.
+type keyedLimiter struct {
+	lock       sync.Mutex
+	limit      int
+	semaphores map[string]*keyedSemaphore
+}
+
+// keyedSemaphore is the semaphore of a key with the number of operations that hold or wait for it, so that it can be
+// removed once it is unused.
+type keyedSemaphore struct {
+	slots chan struct{}
+	refs  int
+}
+
+// setLimit sets the number of concurrent operations per key. Keys with operations that are in progress or waiting keep
+// their semaphore and previous limit, so that the operations stay mutually exclusive; the new limit applies to a key
+// once all of its operations ended.
+func (kl *keyedLimiter) setLimit(limit int) {
+	if limit <= 0 {
+		limit = 1
+	}
+
+	kl.lock.Lock()
+	defer kl.lock.Unlock()
+	kl.limit = limit
+}
+
+func (kl *keyedLimiter) getSemaphore(key string) *keyedSemaphore {
+	kl.lock.Lock()
+	defer kl.lock.Unlock()
+	if kl.limit <= 0 {
+		kl.limit = 1
+	}
+	if kl.semaphores == nil {
+		kl.semaphores = make(map[string]*keyedSemaphore)
+	}
+	semaphore, ok := kl.semaphores[key]
+	if !ok {
+		semaphore = &keyedSemaphore{
+			slots: make(chan struct{}, kl.limit),
+		}
+		kl.semaphores[key] = semaphore
+	}
+	semaphore.refs++
+	return semaphore
+}
+
+func (kl *keyedLimiter) putSemaphore(key string, semaphore *keyedSemaphore) {
+	kl.lock.Lock()
+	defer kl.lock.Unlock()
+	semaphore.refs--
+	if semaphore.refs == 0 && kl.semaphores[key] == semaphore {
+		delete(kl.semaphores, key)
+	}
+}
+
+// acquire waits until an operation on key is allowed, and returns the function that ends the operation. It returns
+// an error if ctx is done first.
+func (kl *keyedLimiter) acquire(ctx context.Context, key string) (func(), error) {
+	semaphore := kl.getSemaphore(key)
+	select {
+	case semaphore.slots <- struct{}{}:
+	default:
+		klog.V(3).Infof("waiting for operations on [%s] to complete", key)
+		select {
+		case semaphore.slots <- struct{}{}:
+		case <-ctx.Done():
+			kl.putSemaphore(key, semaphore)
+			return nil, ctx.Err()
+		}
+	}
+
+	var releaseOnce sync.Once
+	return func() {
+		releaseOnce.Do(func() {
+			<-semaphore.slots
+			kl.putSemaphore(key, semaphore)
+		})
+	}, nil
+}
+
+// lockGateway waits until the client may modify the gateway with gatewayID, and returns the function that ends the
+// modification. Modifications of a gateway are limited to the MaxConcurrentGatewayOperations of the transport config,
+// while different gateways are modified independently.
+func (client *Client) lockGateway(ctx context.Context, gatewayID string) (func(), error) {
+	unlock, err := client.gatewayLocks.acquire(ctx, gatewayID)
+	if err != nil {
+		return nil, fmt.Errorf("gave up waiting for operations on gateway [%s]: [%w]", gatewayID, err)
+	}
+	return unlock, nil
+}
+
+// lockService waits until no other operation of the client runs on the load balancer of a Service, which is
+// identified by the prefix of the names of its VCD entities on the gateway with gatewayID. It returns the function that
+// ends the operation. Load balancers of different Services are provisioned concurrently.
+func (client *Client) lockService(ctx context.Context, gatewayID string, servicePrefix string) (func(), error) {
+	unlock, err := client.serviceLocks.acquire(ctx, fmt.Sprintf("%s/%s", gatewayID, servicePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("gave up waiting for operations on load balancer [%s]: [%w]", servicePrefix, err)
+	}
+	return unlock, nil
+}
@@ -0,0 +1,135 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/util"
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
+)
+
+func TestKeyedLimiter(t *testing.T) {
+
+	limiter := &keyedLimiter{}
+	unlockGateway1, err := limiter.acquire(context.Background(), "gateway1")
+	assert.NoError(t, err, "first operation on gateway should not wait")
+	unlockGateway2, err := limiter.acquire(context.Background(), "gateway2")
+	assert.NoError(t, err, "operation on other gateway should not wait")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = limiter.acquire(ctx, "gateway1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "second operation on gateway should wait with the default limit")
+
+	unlockGateway1()
+	unlockGateway1()
+	unlockGateway, err := limiter.acquire(context.Background(), "gateway1")
+	assert.NoError(t, err, "operation on gateway should proceed once the first one ended")
+	unlockGateway()
+	unlockGateway2()
+
+	limiter.setLimit(2)
+	unlockGateway, err = limiter.acquire(context.Background(), "gateway1")
+	assert.NoError(t, err, "first operation on gateway should not wait")
+	unlockSecondGateway, err := limiter.acquire(context.Background(), "gateway1")
+	assert.NoError(t, err, "second operation on gateway should not wait with a limit of 2")
+
+	// the operations in progress keep the previous limit of the gateway
+	limiter.setLimit(1)
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	unlockThirdGateway, err := limiter.acquire(ctx, "gateway1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "third operation on gateway should wait for the previous limit")
+	unlockGateway()
+	unlockThirdGateway, err = limiter.acquire(context.Background(), "gateway1")
+	assert.NoError(t, err, "operation on gateway should proceed within the previous limit")
+	unlockSecondGateway()
+	unlockThirdGateway()
+	assert.Empty(t, limiter.semaphores, "unused semaphores should be removed")
+
+	unlockGateway, err = limiter.acquire(context.Background(), "gateway1")
+	assert.NoError(t, err, "first operation on gateway should not wait")
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = limiter.acquire(ctx, "gateway1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "second operation on gateway should wait with the new limit")
+
+	// reloading the limit while an operation is in progress should not let another one run at the same time
+	limiter.setLimit(2)
+	limiter.setLimit(1)
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = limiter.acquire(ctx, "gateway1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "second operation on gateway should wait after a reload")
+	unlockGateway()
+
+	return
+}
+
+func TestLockService(t *testing.T) {
+
+	client := &Client{}
+	unlockService1, err := client.lockService(context.Background(), "gateway1", "service1")
+	assert.NoError(t, err, "first operation on service should not wait")
+	unlockService2, err := client.lockService(context.Background(), "gateway1", "service2")
+	assert.NoError(t, err, "operation on other service of the gateway should not wait")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.lockService(ctx, "gateway1", "service1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "second operation on service should wait")
+
+	unlockService1()
+	unlockService2()
+	assert.Empty(t, client.serviceLocks.semaphores, "unused locks should be removed")
+
+	return
+}
+
+func TestUpdateLoadBalancerWaitsForServiceLock(t *testing.T) {
+
+	client := &Client{}
+	gm := &GatewayManager{
+		Client: client,
+		GatewayRef: &swaggerClient.EntityReference{
+			Name: "gateway1",
+			Id:   "urn:vcloud:gateway:1",
+		},
+	}
+
+	// an operation such as DeleteLoadBalancer is in progress on the load balancer of the Service
+	unlockService, err := client.lockService(context.Background(), gm.GatewayRef.Id, "ingress-vs-service1")
+	assert.NoError(t, err, "first operation on service should not wait")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updateErr := make(chan error, 1)
+	go func() {
+		_, err := gm.UpdateLoadBalancer(ctx, "ingress-pool-service1", "ingress-vs-service1", "http",
+			[]string{"1.2.3.4"}, "", 30080, 80, nil, false, "HTTP", &util.AllocatedResourcesMap{})
+		updateErr <- err
+	}()
+
+	select {
+	case err = <-updateErr:
+		assert.Fail(t, "update should wait for the operation on the service", "update returned [%v]", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case err = <-updateErr:
+		assert.ErrorIs(t, err, context.Canceled, "update should give up waiting for the service")
+	case <-time.After(time.Second):
+		assert.Fail(t, "update should return once its context is canceled")
+	}
+	unlockService()
+
+	return
+}
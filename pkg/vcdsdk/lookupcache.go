@@ -0,0 +1,170 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"k8s.io/klog"
+)
+
+const (
+	// DefaultLookupCacheTTL is the time for which the lookups of orgs, VDCs, gateways of networks and service engine
+	// group assignments are reused
+	DefaultLookupCacheTTL = 5 * time.Minute
+)
+
+// lookupCache caches the results of lookups of VCD entities that are repeated by every load balancer operation. An
+// entry expires after the TTL of the cache. All entries are dropped when the session of the client is replaced since
+// the cached govcd entities refer to the govcd client of their session.
+type lookupCache struct {
+	lock    sync.Mutex
+	ttl     time.Duration
+	entries map[string]lookupCacheEntry
+	// generation changes when the cache is cleared, so that lookups which started before are not cached
+	generation uint64
+}
+
+type lookupCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+func orgLookupKey(orgName string) string {
+	return fmt.Sprintf("org/%s", orgName)
+}
+
+func vdcLookupKey(orgName string, vdcIdentifier string) string {
+	return fmt.Sprintf("vdc/%s/%s", orgName, vdcIdentifier)
+}
+
+func networkLookupKey(ovdcIdentifier string, networkName string) string {
+	return fmt.Sprintf("network/%s/%s", ovdcIdentifier, networkName)
+}
+
+func segAssignmentsLookupKey(gatewayID string) string {
+	return fmt.Sprintf("segAssignments/%s", gatewayID)
+}
+
+// get returns the value of key and the generation of the cache. The value is not found if it expired.
+func (lc *lookupCache) get(key string) (interface{}, bool, uint64) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+	entry, ok := lc.entries[key]
+	if !ok {
+		return nil, false, lc.generation
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(lc.entries, key)
+		return nil, false, lc.generation
+	}
+	return entry.value, true, lc.generation
+}
+
+// set caches value for key unless the cache was cleared after generation.
+func (lc *lookupCache) set(key string, value interface{}, generation uint64) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+	if generation != lc.generation {
+		return
+	}
+	ttl := lc.ttl
+	if ttl <= 0 {
+		ttl = DefaultLookupCacheTTL
+	}
+	if lc.entries == nil {
+		lc.entries = make(map[string]lookupCacheEntry)
+	}
+	lc.entries[key] = lookupCacheEntry{
+		value:     value,
+		expiresAt: time.Now().Add(ttl),
+	}
+}
+
+// update replaces the value of key, if it is cached, with the result of updateFunc. The expiry of the entry is kept.
+func (lc *lookupCache) update(key string, updateFunc func(value interface{}) interface{}) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+	entry, ok := lc.entries[key]
+	if !ok {
+		return
+	}
+	entry.value = updateFunc(entry.value)
+	lc.entries[key] = entry
+}
+
+func (lc *lookupCache) invalidate(key string) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+	delete(lc.entries, key)
+}
+
+func (lc *lookupCache) clear() {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+	lc.entries = nil
+	lc.generation++
+}
+
+// cachedLookup returns the value of key in lc, or looks it up with lookupFunc and caches the result if the lookup
+// succeeds. Failed lookups, such as of entities that are not found, are not cached.
+func cachedLookup[T any](lc *lookupCache, key string, lookupFunc func() (T, error)) (T, error) {
+	value, ok, generation := lc.get(key)
+	if ok {
+		if typedValue, ok := value.(T); ok {
+			return typedValue, nil
+		}
+	}
+
+	typedValue, err := lookupFunc()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	lc.set(key, typedValue, generation)
+	return typedValue, nil
+}
+
+// GetOrgByName returns the org with orgName. The org is looked up in VCD at most once per DefaultLookupCacheTTL. The
+// returned org is a copy of the cached one that the caller may refresh.
+func (client *Client) GetOrgByName(orgName string) (*govcd.Org, error) {
+	org, err := cachedLookup(&client.lookups, orgLookupKey(orgName), func() (*govcd.Org, error) {
+		// the session is read after the generation of the cache so that orgs of a replaced session are not cached
+		return client.Session().VCDClient.GetOrgByName(orgName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	orgCopy := *org
+	return &orgCopy, nil
+}
+
+// GetVDCByNameOrId returns the VDC with the name or ID vdcIdentifier in the org with orgName. The VDC is looked up in
+// VCD at most once per DefaultLookupCacheTTL. The returned VDC is a copy of the cached one that the caller may refresh.
+func (client *Client) GetVDCByNameOrId(orgName string, vdcIdentifier string) (*govcd.Vdc, error) {
+	vdc, err := cachedLookup(&client.lookups, vdcLookupKey(orgName, vdcIdentifier), func() (*govcd.Vdc, error) {
+		org, err := client.GetOrgByName(orgName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get org from name [%s]: [%w]", orgName, err)
+		}
+		return org.GetVDCByNameOrId(vdcIdentifier, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	vdcCopy := *vdc
+	return &vdcCopy, nil
+}
+
+// InvalidateLookups drops the cached lookups of the client, so that the entities are looked up in VCD again. It should
+// be called when an operation fails because a VCD entity was not found, since a cached entity may have been removed.
+func (client *Client) InvalidateLookups() {
+	klog.V(3).Infof("invalidating cached lookups of VCD entities")
+	client.lookups.clear()
+}
@@ -0,0 +1,105 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+)
+
+func TestLookupCache(t *testing.T) {
+
+	cache := &lookupCache{}
+	numLookups := 0
+	lookupNetwork := func() (string, error) {
+		numLookups++
+		return fmt.Sprintf("gateway%d", numLookups), nil
+	}
+
+	gateway, err := cachedLookup(cache, networkLookupKey("vdc1", "network1"), lookupNetwork)
+	assert.NoError(t, err, "lookup should succeed")
+	assert.Equal(t, "gateway1", gateway, "first lookup should be made in VCD")
+	gateway, err = cachedLookup(cache, networkLookupKey("vdc1", "network1"), lookupNetwork)
+	assert.NoError(t, err, "cached lookup should succeed")
+	assert.Equal(t, "gateway1", gateway, "second lookup should be cached")
+	assert.Equal(t, 1, numLookups, "cached lookup should not be made in VCD")
+
+	_, err = cachedLookup(cache, networkLookupKey("vdc1", "network2"), func() (string, error) {
+		return "", govcd.ErrorEntityNotFound
+	})
+	assert.True(t, IsNotFoundError(err), "not found error of lookup should be returned")
+	_, ok, _ := cache.get(networkLookupKey("vdc1", "network2"))
+	assert.False(t, ok, "failed lookup should not be cached")
+
+	cache.invalidate(networkLookupKey("vdc1", "network1"))
+	gateway, _ = cachedLookup(cache, networkLookupKey("vdc1", "network1"), lookupNetwork)
+	assert.Equal(t, "gateway2", gateway, "invalidated lookup should be made again")
+
+	// a lookup that started before the cache was cleared is not cached
+	_, _, generation := cache.get(networkLookupKey("vdc1", "network3"))
+	cache.clear()
+	cache.set(networkLookupKey("vdc1", "network3"), "gateway3", generation)
+	_, ok, _ = cache.get(networkLookupKey("vdc1", "network3"))
+	assert.False(t, ok, "lookup of previous generation should not be cached")
+
+	cache.ttl = 10 * time.Millisecond
+	gateway, _ = cachedLookup(cache, networkLookupKey("vdc1", "network1"), lookupNetwork)
+	assert.Equal(t, "gateway3", gateway, "lookup should be made again after the cache was cleared")
+	time.Sleep(20 * time.Millisecond)
+	gateway, _ = cachedLookup(cache, networkLookupKey("vdc1", "network1"), lookupNetwork)
+	assert.Equal(t, "gateway4", gateway, "expired lookup should be made again")
+
+	return
+}
+
+func TestGetLoadBalancerSEGFromCache(t *testing.T) {
+
+	ctx := context.Background()
+	client := &Client{}
+	gm := &GatewayManager{
+		Client: client,
+		GatewayRef: &swaggerClient.EntityReference{
+			Name: "gateway1",
+			Id:   "urn:vcloud:gateway:1",
+		},
+	}
+	client.lookups.set(segAssignmentsLookupKey(gm.GatewayRef.Id),
+		[]swaggerClient.LoadBalancerServiceEngineGroupAssignment{
+			{
+				Id:                         "seg-assignment-1",
+				ServiceEngineGroupRef:      &swaggerClient.EntityReference{Name: "seg1", Id: "seg-1"},
+				NumDeployedVirtualServices: 1,
+				MaxVirtualServices:         2,
+			},
+			{
+				Id:                         "seg-assignment-2",
+				ServiceEngineGroupRef:      &swaggerClient.EntityReference{Name: "seg2", Id: "seg-2"},
+				NumDeployedVirtualServices: 0,
+				MaxVirtualServices:         1,
+			},
+		}, 0)
+
+	segRef, err := gm.GetLoadBalancerSEG(ctx)
+	assert.NoError(t, err, "service engine group should be chosen from the cache")
+	assert.Equal(t, "seg1", segRef.Name, "first service engine group with a free slot should be chosen")
+
+	segRef, err = gm.GetLoadBalancerSEG(ctx)
+	assert.NoError(t, err, "service engine group should be chosen from the cache")
+	assert.Equal(t, "seg2", segRef.Name, "slot of chosen service engine group should be counted as used")
+
+	_, err = gm.GetLoadBalancerSEG(ctx)
+	assert.Equal(t, VCDErrorQuotaExceeded, GetVCDErrorKind(err), "no service engine group should have a free slot")
+	_, ok, _ := client.lookups.get(segAssignmentsLookupKey(gm.GatewayRef.Id))
+	assert.False(t, ok, "full service engine group assignments should not stay cached")
+
+	return
+}
@@ -0,0 +1,163 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	metricsNamespace = "cloudprovider"
+	metricsSubsystem = "vcd"
+)
+
+var (
+	apiRequests = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      metricsSubsystem,
+			Name:           "api_requests_total",
+			Help:           "Number of requests to the VCD API by method, endpoint and status code.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"method", "endpoint", "code"},
+	)
+
+	apiRequestDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      metricsSubsystem,
+			Name:           "api_request_duration_seconds",
+			Help:           "Latency of requests to the VCD API by method and endpoint.",
+			Buckets:        metrics.ExponentialBuckets(0.05, 2, 10),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"method", "endpoint"},
+	)
+
+	taskWaitDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      metricsSubsystem,
+			Name:           "task_wait_duration_seconds",
+			Help:           "Time spent waiting for VCD tasks to complete by operation and result.",
+			Buckets:        metrics.ExponentialBuckets(0.5, 2, 10),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"operation", "result"},
+	)
+
+	segUtilization = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      metricsSubsystem,
+			Name:           "service_engine_group_utilization_ratio",
+			Help:           "Ratio of deployed to maximum virtual services of the service engine groups of a gateway.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"gateway", "service_engine_group"},
+	)
+
+	ipPoolExhausted = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      metricsNamespace,
+			Subsystem:      metricsSubsystem,
+			Name:           "ip_pool_exhausted",
+			Help:           "Whether the last IP allocation from the external or internal IP pool of a gateway found no free IP.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"gateway", "pool"},
+	)
+
+	registerMetricsOnce sync.Once
+)
+
+const (
+	ipPoolExternal = "external"
+	ipPoolInternal = "internal"
+)
+
+// idSegmentRegex matches path segments that contain the UUID of a VCD entity, such as URNs and vm-<uuid>
+var idSegmentRegex = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// RegisterMetrics registers the metrics of the VCD clients with the legacy registry of component-base.
+func RegisterMetrics() {
+	registerMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(apiRequests)
+		legacyregistry.MustRegister(apiRequestDuration)
+		legacyregistry.MustRegister(taskWaitDuration)
+		legacyregistry.MustRegister(segUtilization)
+		legacyregistry.MustRegister(ipPoolExhausted)
+	})
+}
+
+// GetEndpointLabel returns the path of a VCD API request with the IDs of entities replaced by "{id}", so that requests
+// to the same endpoint share their metrics.
+func GetEndpointLabel(path string) string {
+	segments := strings.Split(path, "/")
+	for idx, segment := range segments {
+		if idSegmentRegex.MatchString(segment) {
+			segments[idx] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// metricsTransport records the count and latency of the requests to the VCD API.
+type metricsTransport struct {
+	base http.RoundTripper
+}
+
+func (transport *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := GetEndpointLabel(req.URL.Path)
+	startTime := time.Now()
+	resp, err := transport.base.RoundTrip(req)
+	apiRequestDuration.WithLabelValues(req.Method, endpoint).Observe(time.Since(startTime).Seconds())
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	apiRequests.WithLabelValues(req.Method, endpoint, code).Inc()
+	return resp, err
+}
+
+func newMetricsTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &metricsTransport{
+		base: base,
+	}
+}
+
+// observeSEGUtilization records the utilization of a service engine group assigned to a gateway. Dedicated service
+// engine groups have no maximum and are not recorded.
+func observeSEGUtilization(gatewayName string, segName string, numDeployedVirtualServices int32,
+	maxVirtualServices int32) {
+	if maxVirtualServices <= 0 {
+		return
+	}
+	segUtilization.WithLabelValues(gatewayName, segName).Set(
+		float64(numDeployedVirtualServices) / float64(maxVirtualServices))
+}
+
+// observeIPPoolExhaustion records whether an IP allocation from the pool of a gateway found no free IP.
+func observeIPPoolExhaustion(gatewayName string, pool string, exhausted bool) {
+	value := 0.0
+	if exhausted {
+		value = 1.0
+	}
+	ipPoolExhausted.WithLabelValues(gatewayName, pool).Set(value)
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEndpointLabel(t *testing.T) {
+
+	assert.Equal(t, "/cloudapi/1.0.0/edgeGateways/{id}/loadBalancer/virtualServiceSummaries",
+		GetEndpointLabel("/cloudapi/1.0.0/edgeGateways/urn:vcloud:gateway:0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d"+
+			"/loadBalancer/virtualServiceSummaries"), "URN should be replaced")
+	assert.Equal(t, "/api/vApp/{id}/power/action/reboot",
+		GetEndpointLabel("/api/vApp/vm-0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d/power/action/reboot"),
+		"prefixed ID should be replaced")
+	assert.Equal(t, "/api/query", GetEndpointLabel("/api/query"), "path without IDs should be unchanged")
+	assert.Equal(t, "/oauth/tenant/org1/token", GetEndpointLabel("/oauth/tenant/org1/token"),
+		"org name should be unchanged")
+
+	return
+}
+
+func TestMetricsTransport(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{
+		Transport: newMetricsTransport(nil),
+	}
+	resp, err := httpClient.Get(server.URL + "/api/task/0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d")
+	assert.NoError(t, err, "request should succeed")
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "response should be passed to the caller")
+
+	return
+}
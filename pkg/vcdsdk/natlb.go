@@ -0,0 +1,372 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/util"
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"k8s.io/klog"
+)
+
+// The NAT-only load balancer is used on NSX-T gateways that have no NSX Advanced Load Balancer (Avi) service
+// engine groups. Every port of a service is exposed with a DNAT rule that forwards VIP:port to the NodePort of
+// a single backend node. The app port profile of the rule carries the NodePort, and the DNAT rule carries the
+// external port. If the backend node disappears, the rules are moved to another node on the next reconcile.
+
+// GetNATLoadBalancerRuleName returns the name of the DNAT rule used for a port of a NAT-only load balancer. The port is
+// separated from dnatRuleNamePrefix by an underscore, which names of Kubernetes ports cannot contain.
+func GetNATLoadBalancerRuleName(dnatRuleNamePrefix string, portSuffix string) string {
+	return GetDNATRuleName(fmt.Sprintf("%s_%s", dnatRuleNamePrefix, portSuffix))
+}
+
+// GetNATLoadBalancerRules returns the DNAT rules of natRules that belong to the NAT-only load balancer with
+// dnatRuleNamePrefix and the external IP vip, including those of ports that were since removed from the service. A rule
+// belongs to the load balancer only if its name is the rule name of a port of dnatRuleNamePrefix, so rules of load
+// balancers whose prefix starts with dnatRuleNamePrefix are not returned. No rules are returned if vip is empty, since
+// the rules of a load balancer cannot be told apart from those of another one that was given the same name.
+func GetNATLoadBalancerRules(natRules []swaggerClient.EdgeNatRule, dnatRuleNamePrefix string,
+	vip string) []swaggerClient.EdgeNatRule {
+	lbRules := make([]swaggerClient.EdgeNatRule, 0)
+	if vip == "" {
+		return lbRules
+	}
+
+	ruleNamePrefix := GetNATLoadBalancerRuleName(dnatRuleNamePrefix, "")
+	for idx := range natRules {
+		natRule := &natRules[idx]
+		if getNATRuleType(natRule) != string(swaggerClient.DNAT_NatRuleType) ||
+			!strings.HasPrefix(natRule.Name, ruleNamePrefix) ||
+			strings.Contains(strings.TrimPrefix(natRule.Name, ruleNamePrefix), "_") {
+			continue
+		}
+		if natRule.ExternalAddresses != vip {
+			continue
+		}
+		lbRules = append(lbRules, *natRule)
+	}
+	return lbRules
+}
+
+// SelectNATBackendIP returns the node IP to which DNAT rules of a NAT-only load balancer should forward. The
+// current backend is retained as long as it is still a candidate so that connections are not moved needlessly.
+// Otherwise, a node is chosen by hashing the selectionKey so that services are spread across nodes.
+func SelectNATBackendIP(currentIP string, nodeIPs []string, selectionKey string) string {
+	candidates := make([]string, 0, len(nodeIPs))
+	for _, nodeIP := range util.NewSet(nodeIPs).GetElements() {
+		if nodeIP == "" {
+			continue
+		}
+		if nodeIP == currentIP {
+			return currentIP
+		}
+		candidates = append(candidates, nodeIP)
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.Strings(candidates)
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(selectionKey))
+	return candidates[hash.Sum32()%uint32(len(candidates))]
+}
+
+// GetNATLoadBalancer returns the VIP of the NAT-only load balancer along with a map of port name to the
+// external IP of the DNAT rule of the port. The IP of a port is empty if its DNAT rule does not exist.
+func (gm *GatewayManager) GetNATLoadBalancer(ctx context.Context, dnatRuleNamePrefix string,
+	portDetailsList []PortDetails) (string, map[string]string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.GetNATLoadBalancer")
+	defer span.End()
+
+	if gm.GatewayRef == nil {
+		return "", nil, fmt.Errorf("gateway reference should not be nil")
+	}
+
+	vip := ""
+	portNameToIP := make(map[string]string)
+	for _, portDetails := range portDetailsList {
+		if portDetails.InternalPort == 0 {
+			continue
+		}
+
+		dnatRuleName := GetNATLoadBalancerRuleName(dnatRuleNamePrefix, portDetails.PortSuffix)
+		dnatRuleRef, err := gm.GetNATRuleRef(ctx, dnatRuleName)
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to get dnat rule [%s]: [%w]", dnatRuleName, err)
+		}
+		if dnatRuleRef == nil {
+			portNameToIP[portDetails.PortSuffix] = ""
+			continue
+		}
+
+		if vip != "" && vip != dnatRuleRef.ExternalIP {
+			return "", nil, fmt.Errorf("more than one external IP found for dnat rules with prefix [%s]: [%s] and [%s]",
+				dnatRuleNamePrefix, vip, dnatRuleRef.ExternalIP)
+		}
+		vip = dnatRuleRef.ExternalIP
+		portNameToIP[portDetails.PortSuffix] = dnatRuleRef.ExternalIP
+	}
+
+	return vip, portNameToIP, nil
+}
+
+// EnsureNATLoadBalancer creates or updates the DNAT rules and app port profiles of a NAT-only load balancer so that
+// every port forwards VIP:ExternalPort to backendIP:InternalPort, where backendIP is chosen from nodeIPs using
+// SelectNATBackendIP. The VIP of an existing rule is reused; otherwise providedIP is used, and if that is empty an
+// IP is obtained from the gateway. The rules and app port profiles of ports that were removed are deleted. The VIP is
+// returned.
+func (gm *GatewayManager) EnsureNATLoadBalancer(ctx context.Context, dnatRuleNamePrefix string, lbIpClaimMarker string,
+	nodeIPs []string, portDetailsList []PortDetails, providedIP string,
+	resourcesAllocated *util.AllocatedResourcesMap, resourcesDeallocated *util.AllocatedResourcesMap) (string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.EnsureNATLoadBalancer")
+	defer span.End()
+
+	if len(portDetailsList) == 0 {
+		klog.Infof("There is no port specified. Hence nothing to do.")
+		return "", fmt.Errorf("nothing to do since no ports are specified")
+	}
+	if gm.GatewayRef == nil {
+		return "", fmt.Errorf("gateway reference should not be nil")
+	}
+
+	client := gm.Client
+	unlockService, err := client.lockService(ctx, gm.GatewayRef.Id, dnatRuleNamePrefix)
+	if err != nil {
+		return "", err
+	}
+	defer unlockService()
+
+	// an IP allocated below is used by the NAT rules that are created before this returns
+	claims := gm.newIPClaims()
+	defer claims.release()
+
+	// Look up the rules of all ports first so that the VIP and backend of a partially created load balancer are reused.
+	externalIP := ""
+	currentBackendIP := ""
+	dnatRuleRefs := make(map[string]*NatRuleRef)
+	for _, portDetails := range portDetailsList {
+		if portDetails.InternalPort == 0 {
+			continue
+		}
+		if !strings.EqualFold(portDetails.Protocol, "TCP") {
+			return "", fmt.Errorf("protocol [%s] of port [%s] is not supported by the NAT-only load balancer",
+				portDetails.Protocol, portDetails.PortSuffix)
+		}
+
+		dnatRuleName := GetNATLoadBalancerRuleName(dnatRuleNamePrefix, portDetails.PortSuffix)
+		dnatRuleRef, err := gm.GetNATRuleRef(ctx, dnatRuleName)
+		if err != nil {
+			return "", fmt.Errorf("unable to get dnat rule [%s]: [%w]", dnatRuleName, err)
+		}
+		if dnatRuleRef == nil {
+			continue
+		}
+		dnatRuleRefs[dnatRuleName] = dnatRuleRef
+
+		if externalIP != "" && externalIP != dnatRuleRef.ExternalIP {
+			return "", fmt.Errorf("as per dnat there are two external IP rules for the same service: [%s], [%s]",
+				externalIP, dnatRuleRef.ExternalIP)
+		}
+		externalIP = dnatRuleRef.ExternalIP
+		if currentBackendIP == "" {
+			currentBackendIP = dnatRuleRef.InternalIP
+		}
+	}
+
+	if providedIP != "" {
+		externalIP = providedIP
+	}
+	if externalIP == "" {
+		externalIP, err = claims.allocate(ctx, func(ctx context.Context) (string, error) {
+			return gm.getExternalIPForLoadBalancer(ctx, lbIpClaimMarker)
+		})
+		if err != nil {
+			return "", fmt.Errorf("unable to get external IP for NAT load balancer [%s]: [%w]", dnatRuleNamePrefix, err)
+		}
+	}
+	resourcesAllocated.Insert("externalIP", &swaggerClient.EntityReference{
+		Name: externalIP,
+	})
+
+	backendIP := SelectNATBackendIP(currentBackendIP, nodeIPs, dnatRuleNamePrefix)
+	if backendIP == "" {
+		return externalIP, fmt.Errorf("no node IPs available as a backend for NAT load balancer [%s]", dnatRuleNamePrefix)
+	}
+	if currentBackendIP != "" && currentBackendIP != backendIP {
+		klog.Infof("Backend [%s] of NAT load balancer [%s] is no longer available; failing over to [%s]",
+			currentBackendIP, dnatRuleNamePrefix, backendIP)
+	}
+	klog.Infof("Using VIP [%s] and backend [%s] for NAT load balancer [%s]", externalIP, backendIP, dnatRuleNamePrefix)
+
+	dnatRuleNames := make(map[string]bool)
+	for _, portDetails := range portDetailsList {
+		if portDetails.InternalPort == 0 {
+			klog.Infof("No internal port specified for [%s], hence dnat rule not created", portDetails.PortSuffix)
+			continue
+		}
+
+		dnatRuleName := GetNATLoadBalancerRuleName(dnatRuleNamePrefix, portDetails.PortSuffix)
+		dnatRuleNames[dnatRuleName] = true
+		appPortProfileName := GetAppPortProfileName(dnatRuleName)
+
+		// For a DNAT rule, the destination port of the app port profile is the port on the backend.
+		appPortProfile, err := gm.UpdateAppPortProfile(appPortProfileName, portDetails.InternalPort)
+		if err == govcd.ErrorEntityNotFound {
+			appPortProfile, err = gm.CreateAppPortProfile(appPortProfileName, portDetails.InternalPort)
+		}
+		if err != nil {
+			return externalIP, fmt.Errorf("unable to create or update app port profile [%s]: [%w]", appPortProfileName, err)
+		}
+		if appPortProfile == nil || appPortProfile.NsxtAppPortProfile == nil {
+			return externalIP, fmt.Errorf("app port profile [%s] is empty", appPortProfileName)
+		}
+		resourcesAllocated.Insert(VcdResourceAppPortProfile, &swaggerClient.EntityReference{
+			Name: appPortProfile.NsxtAppPortProfile.Name,
+			Id:   appPortProfile.NsxtAppPortProfile.ID,
+		})
+
+		if dnatRuleRef, ok := dnatRuleRefs[dnatRuleName]; ok {
+			dnatRuleRef, err = gm.UpdateDNATRule(ctx, dnatRuleName, externalIP, backendIP, portDetails.ExternalPort)
+			if err != nil {
+				return externalIP, fmt.Errorf("unable to update dnat rule [%s] to [%s:%d]=>[%s:%d]: [%w]", dnatRuleName,
+					externalIP, portDetails.ExternalPort, backendIP, portDetails.InternalPort, err)
+			}
+			resourcesAllocated.Insert(VcdResourceDNATRule, &swaggerClient.EntityReference{
+				Name: dnatRuleRef.Name,
+				Id:   dnatRuleRef.ID,
+			})
+			continue
+		}
+
+		if err = gm.CreateDNATRule(ctx, dnatRuleName, externalIP, backendIP,
+			portDetails.ExternalPort, portDetails.InternalPort, appPortProfile); err != nil {
+			return externalIP, fmt.Errorf("unable to create dnat rule [%s:%d]=>[%s:%d] with profile [%s]: [%w]",
+				externalIP, portDetails.ExternalPort, backendIP, portDetails.InternalPort, appPortProfileName, err)
+		}
+		dnatRuleRef, err := gm.GetNATRuleRef(ctx, dnatRuleName)
+		if err != nil {
+			return externalIP, fmt.Errorf("unable to retrieve created dnat rule [%s]: [%w]", dnatRuleName, err)
+		}
+		if dnatRuleRef == nil {
+			return externalIP, fmt.Errorf("retrieved dnat rule ref is nil")
+		}
+		resourcesAllocated.Insert(VcdResourceDNATRule, &swaggerClient.EntityReference{
+			Name: dnatRuleRef.Name,
+			Id:   dnatRuleRef.ID,
+		})
+	}
+
+	natRules, err := gm.listNATRules(ctx)
+	if err != nil {
+		return externalIP, fmt.Errorf("unable to list dnat rules of NAT load balancer [%s]: [%w]", dnatRuleNamePrefix, err)
+	}
+	for _, natRule := range GetNATLoadBalancerRules(natRules, dnatRuleNamePrefix, externalIP) {
+		if dnatRuleNames[natRule.Name] {
+			continue
+		}
+		klog.Infof("Deleting dnat rule [%s] of a port that was removed from NAT load balancer [%s]", natRule.Name,
+			dnatRuleNamePrefix)
+		if err = gm.deleteNATLoadBalancerRule(ctx, natRule.Name, resourcesDeallocated); err != nil {
+			return externalIP, err
+		}
+	}
+
+	return externalIP, nil
+}
+
+// deleteNATLoadBalancerRule deletes the DNAT rule dnatRuleName of a NAT-only load balancer and its app port profile.
+// Missing entities are not treated as errors.
+func (gm *GatewayManager) deleteNATLoadBalancerRule(ctx context.Context, dnatRuleName string,
+	resourcesDeallocated *util.AllocatedResourcesMap) error {
+	if err := gm.DeleteDNATRule(ctx, dnatRuleName, false); err != nil {
+		return fmt.Errorf("unable to delete dnat rule [%s]: [%w]", dnatRuleName, err)
+	}
+	resourcesDeallocated.Insert(VcdResourceDNATRule, &swaggerClient.EntityReference{
+		Name: dnatRuleName,
+	})
+
+	appPortProfileName := GetAppPortProfileName(dnatRuleName)
+	if err := gm.DeleteAppPortProfile(appPortProfileName, false); err != nil {
+		return fmt.Errorf("unable to delete app port profile [%s]: [%w]", appPortProfileName, err)
+	}
+	resourcesDeallocated.Insert(VcdResourceAppPortProfile, &swaggerClient.EntityReference{
+		Name: appPortProfileName,
+	})
+	return nil
+}
+
+// DeleteNATLoadBalancer deletes the DNAT rules and app port profiles of a NAT-only load balancer, including those of
+// ports that were removed from the service, and releases the VIP if it was reserved from an IP space. Missing entities
+// are not treated as errors. The VIP is taken from the rules of the current ports, or else from the IP claimed with
+// lbIpClaimMarker; rules of removed ports are only deleted if they use that VIP. The VIP is returned; it is empty if
+// it is not known, in which case the IP reserved with lbIpClaimMarker is released.
+func (gm *GatewayManager) DeleteNATLoadBalancer(ctx context.Context, dnatRuleNamePrefix string, lbIpClaimMarker string,
+	portDetailsList []PortDetails, resourcesDeallocated *util.AllocatedResourcesMap) (string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.DeleteNATLoadBalancer")
+	defer span.End()
+
+	if gm.GatewayRef == nil {
+		return "", fmt.Errorf("gateway reference should not be nil")
+	}
+
+	client := gm.Client
+	unlockService, err := client.lockService(ctx, gm.GatewayRef.Id, dnatRuleNamePrefix)
+	if err != nil {
+		return "", err
+	}
+	defer unlockService()
+
+	natRules, err := gm.listNATRules(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to list dnat rules of NAT load balancer [%s]: [%w]", dnatRuleNamePrefix, err)
+	}
+	natRuleIPs := make(map[string]string)
+	for idx := range natRules {
+		if natRule := &natRules[idx]; getNATRuleType(natRule) == string(swaggerClient.DNAT_NatRuleType) {
+			natRuleIPs[natRule.Name] = natRule.ExternalAddresses
+		}
+	}
+
+	rdeVIP := ""
+	for _, portDetails := range portDetailsList {
+		if portDetails.InternalPort == 0 {
+			continue
+		}
+		if ip, ok := natRuleIPs[GetNATLoadBalancerRuleName(dnatRuleNamePrefix, portDetails.PortSuffix)]; ok {
+			rdeVIP = ip
+			break
+		}
+	}
+	if rdeVIP == "" {
+		// the rules of the current ports are gone already, so the VIP can only be known from its claim
+		if rdeVIP, err = gm.getClaimedIpOfLoadBalancer(ctx, lbIpClaimMarker); err != nil {
+			return "", fmt.Errorf("unable to get VIP of NAT load balancer [%s]: [%w]", dnatRuleNamePrefix, err)
+		}
+	}
+	if rdeVIP == "" {
+		klog.Infof("VIP of NAT load balancer [%s] is not known; only the IP claimed with [%s] is released",
+			dnatRuleNamePrefix, lbIpClaimMarker)
+	}
+
+	for _, natRule := range GetNATLoadBalancerRules(natRules, dnatRuleNamePrefix, rdeVIP) {
+		if err = gm.deleteNATLoadBalancerRule(ctx, natRule.Name, resourcesDeallocated); err != nil {
+			return "", err
+		}
+	}
+
+	if err := gm.releaseExternalIPOfLoadBalancer(ctx, rdeVIP, lbIpClaimMarker); err != nil {
+		return "", err
+	}
+
+	return rdeVIP, nil
+}
@@ -0,0 +1,115 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
+)
+
+func TestSelectNATBackendIP(t *testing.T) {
+
+	type TestCase struct {
+		CurrentIP    string
+		NodeIPs      []string
+		ExpectedIPs  []string
+		ErrorComment string
+	}
+
+	testCaseList := []TestCase{
+		{
+			CurrentIP:    "10.0.0.2",
+			NodeIPs:      []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+			ExpectedIPs:  []string{"10.0.0.2"},
+			ErrorComment: "current backend should be retained while it is a candidate",
+		},
+		{
+			CurrentIP:    "10.0.0.9",
+			NodeIPs:      []string{"10.0.0.1", "10.0.0.2", ""},
+			ExpectedIPs:  []string{"10.0.0.1", "10.0.0.2"},
+			ErrorComment: "backend should fail over to one of the remaining nodes",
+		},
+		{
+			CurrentIP:    "",
+			NodeIPs:      []string{"10.0.0.3"},
+			ExpectedIPs:  []string{"10.0.0.3"},
+			ErrorComment: "only node should be chosen",
+		},
+		{
+			CurrentIP:    "10.0.0.1",
+			NodeIPs:      []string{},
+			ExpectedIPs:  []string{""},
+			ErrorComment: "no backend should be chosen when there are no nodes",
+		},
+	}
+
+	for _, testCase := range testCaseList {
+		backendIP := SelectNATBackendIP(testCase.CurrentIP, testCase.NodeIPs, "dnat-ingress-nat-svc")
+		assert.Contains(t, testCase.ExpectedIPs, backendIP, testCase.ErrorComment)
+	}
+
+	// the choice should be stable irrespective of the order of nodes
+	assert.Equal(t,
+		SelectNATBackendIP("", []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, "key"),
+		SelectNATBackendIP("", []string{"10.0.0.3", "10.0.0.1", "10.0.0.2"}, "key"),
+		"backend selection should not depend on node order")
+
+	return
+}
+
+func TestGetNATLoadBalancerRules(t *testing.T) {
+
+	natRules := []swaggerClient.EdgeNatRule{
+		{
+			Name:              "dnat-ingress-nat-cluster1_ns1_web_http",
+			Type_:             string(swaggerClient.DNAT_NatRuleType),
+			ExternalAddresses: "10.10.0.5",
+		},
+		{
+			Name:              "dnat-ingress-nat-cluster1_ns1_web_removed",
+			Type_:             string(swaggerClient.DNAT_NatRuleType),
+			ExternalAddresses: "10.10.0.5",
+		},
+		{
+			// service web-http in the same namespace
+			Name:              "dnat-ingress-nat-cluster1_ns1_web-http_x",
+			Type_:             string(swaggerClient.DNAT_NatRuleType),
+			ExternalAddresses: "10.10.0.6",
+		},
+		{
+			// service web in another namespace
+			Name:              "dnat-ingress-nat-cluster1_ns2_web_http",
+			Type_:             string(swaggerClient.DNAT_NatRuleType),
+			ExternalAddresses: "10.10.0.7",
+		},
+		{
+			Name:              "dnat-ingress-nat-cluster1_ns1_web_snat",
+			Type_:             string(swaggerClient.SNAT_NatRuleType),
+			ExternalAddresses: "10.10.0.5",
+		},
+	}
+
+	lbRules := GetNATLoadBalancerRules(natRules, "ingress-nat-cluster1_ns1_web", "10.10.0.5")
+	assert.Equal(t, []swaggerClient.EdgeNatRule{natRules[0], natRules[1]}, lbRules,
+		"dnat rules of current and removed ports with the VIP should be returned")
+
+	lbRules = GetNATLoadBalancerRules(natRules, "ingress-nat-cluster1_ns2_web", "10.10.0.7")
+	assert.Equal(t, []swaggerClient.EdgeNatRule{natRules[3]}, lbRules,
+		"dnat rules of the service with the same name in another namespace should not be returned")
+
+	lbRules = GetNATLoadBalancerRules(natRules, "ingress-nat-cluster1_ns1_web", "10.10.0.6")
+	assert.Empty(t, lbRules, "dnat rules of a service whose name starts with the service name should not be returned")
+
+	lbRules = GetNATLoadBalancerRules(natRules, "ingress-nat-cluster1_ns1_web", "10.10.0.7")
+	assert.Empty(t, lbRules, "dnat rules with another VIP should not be returned")
+
+	lbRules = GetNATLoadBalancerRules(natRules, "ingress-nat-cluster1_ns1_web", "")
+	assert.Empty(t, lbRules, "no dnat rules should be returned if the VIP is not known")
+
+	return
+}
@@ -0,0 +1,166 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// NICAddresses are the addresses of a NIC of a VM.
+type NICAddresses struct {
+	Network string
+	Primary bool
+	// IPs are the IPv4 and IPv6 addresses of the NIC
+	IPs []string
+	// ExternalIP is the address of the NIC on the external side of a NAT-routed vApp network, if any
+	ExternalIP string
+}
+
+// GetVMNICAddresses returns the addresses of the NICs of the VM, the primary NIC first. NICs without an IP are
+// skipped.
+func GetVMNICAddresses(vm *types.Vm) []NICAddresses {
+	if vm == nil || vm.NetworkConnectionSection == nil {
+		return nil
+	}
+
+	networkConnections := make([]*types.NetworkConnection, 0, len(vm.NetworkConnectionSection.NetworkConnection))
+	for _, netConn := range vm.NetworkConnectionSection.NetworkConnection {
+		if netConn != nil {
+			networkConnections = append(networkConnections, netConn)
+		}
+	}
+	primaryIndex := vm.NetworkConnectionSection.PrimaryNetworkConnectionIndex
+	sort.SliceStable(networkConnections, func(i, j int) bool {
+		iPrimary := networkConnections[i].NetworkConnectionIndex == primaryIndex
+		jPrimary := networkConnections[j].NetworkConnectionIndex == primaryIndex
+		if iPrimary != jPrimary {
+			return iPrimary
+		}
+		return networkConnections[i].NetworkConnectionIndex < networkConnections[j].NetworkConnectionIndex
+	})
+
+	nicAddressesList := make([]NICAddresses, 0, len(networkConnections))
+	for _, netConn := range networkConnections {
+		ips := make([]string, 0, 2)
+		for _, ip := range []string{netConn.IPAddress, netConn.SecondaryIpAddress} {
+			if net.ParseIP(ip) != nil {
+				ips = append(ips, ip)
+			}
+		}
+		if len(ips) == 0 {
+			continue
+		}
+
+		externalIP := ""
+		if net.ParseIP(netConn.ExternalIPAddress) != nil {
+			externalIP = netConn.ExternalIPAddress
+		}
+		nicAddressesList = append(nicAddressesList, NICAddresses{
+			Network:    netConn.Network,
+			Primary:    netConn.NetworkConnectionIndex == primaryIndex,
+			IPs:        ips,
+			ExternalIP: externalIP,
+		})
+	}
+	return nicAddressesList
+}
+
+// GetVMGuestHostname returns the computer name of the guest OS of the VM, or the VM name if it is not customized.
+func GetVMGuestHostname(vm *types.Vm) string {
+	if vm == nil {
+		return ""
+	}
+	if vm.GuestCustomizationSection != nil && vm.GuestCustomizationSection.ComputerName != "" {
+		return vm.GuestCustomizationSection.ComputerName
+	}
+	return vm.Name
+}
+
+// getNATRuleType returns the type of a NAT rule, which is in ruleType for older API versions.
+func getNATRuleType(natRule *swaggerClient.EdgeNatRule) string {
+	if natRule.Type_ != "" {
+		return natRule.Type_
+	}
+	if natRule.RuleType != nil {
+		return string(*natRule.RuleType)
+	}
+	return ""
+}
+
+// GetNATExternalIPsByInternalIP maps the internal IPs of one-to-one NAT rules to their external IP. DNAT rules that
+// forward specific ports, such as load balancer rules, are ignored. DNAT rules take precedence over SNAT rules.
+func GetNATExternalIPsByInternalIP(natRules []swaggerClient.EdgeNatRule) map[string]string {
+	dnatExternalIPs := make(map[string]string)
+	snatExternalIPs := make(map[string]string)
+	for idx := range natRules {
+		natRule := &natRules[idx]
+		if !natRule.Enabled || net.ParseIP(natRule.InternalAddresses) == nil ||
+			net.ParseIP(natRule.ExternalAddresses) == nil {
+			continue
+		}
+		switch getNATRuleType(natRule) {
+		case string(swaggerClient.DNAT_NatRuleType):
+			if natRule.ApplicationPortProfile != nil || natRule.DnatExternalPort != "" || natRule.InternalPort != "" {
+				continue
+			}
+			dnatExternalIPs[natRule.InternalAddresses] = natRule.ExternalAddresses
+		case string(swaggerClient.SNAT_NatRuleType):
+			if natRule.SnatDestinationAddresses != "" {
+				continue
+			}
+			snatExternalIPs[natRule.InternalAddresses] = natRule.ExternalAddresses
+		}
+	}
+
+	for internalIP, externalIP := range snatExternalIPs {
+		if _, ok := dnatExternalIPs[internalIP]; !ok {
+			dnatExternalIPs[internalIP] = externalIP
+		}
+	}
+	return dnatExternalIPs
+}
+
+// ListNATExternalIPsByInternalIP maps the internal IPs of the one-to-one NAT rules of the gateway to their external
+// IP.
+func (gm *GatewayManager) ListNATExternalIPsByInternalIP(ctx context.Context) (map[string]string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.ListNATExternalIPsByInternalIP")
+	defer span.End()
+
+	if gm.GatewayRef == nil {
+		return nil, fmt.Errorf("gateway reference should not be nil")
+	}
+	client := gm.Client
+	session := client.Session()
+	org, err := client.GetOrgByName(client.ClusterOrgName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+	}
+	if org == nil || org.Org == nil {
+		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
+	}
+
+	natRules, err := listAllPages(cursorPagination, MaxPageSize,
+		func(page pageRequest) ([]swaggerClient.EdgeNatRule, int32, *http.Response, error) {
+			natRules, resp, err := session.APIClient.EdgeGatewayNatRulesApi.GetNatRules(
+				ctx, page.PageSize, gm.GatewayRef.Id, org.Org.ID,
+				&swaggerClient.EdgeGatewayNatRulesApiGetNatRulesOpts{
+					Cursor: page.Cursor,
+				})
+			return natRules.Values, 0, resp, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get nat rules: [%v]", err)
+	}
+
+	return GetNATExternalIPsByInternalIP(natRules), nil
+}
@@ -0,0 +1,121 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+func TestGetVMNICAddresses(t *testing.T) {
+
+	vm := &types.Vm{
+		NetworkConnectionSection: &types.NetworkConnectionSection{
+			PrimaryNetworkConnectionIndex: 1,
+			NetworkConnection: []*types.NetworkConnection{
+				{
+					Network:                "storage-net",
+					NetworkConnectionIndex: 0,
+					IPAddress:              "192.168.10.5",
+				},
+				{
+					Network:                "mgmt-net",
+					NetworkConnectionIndex: 1,
+					IPAddress:              "10.0.0.5",
+					SecondaryIpAddress:     "fd00::5",
+					ExternalIPAddress:      "203.0.113.5",
+				},
+				{
+					Network:                "unused-net",
+					NetworkConnectionIndex: 2,
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []NICAddresses{
+		{
+			Network:    "mgmt-net",
+			Primary:    true,
+			IPs:        []string{"10.0.0.5", "fd00::5"},
+			ExternalIP: "203.0.113.5",
+		},
+		{
+			Network: "storage-net",
+			IPs:     []string{"192.168.10.5"},
+		},
+	}, GetVMNICAddresses(vm), "primary NIC should be first and NICs without IP should be skipped")
+
+	assert.Nil(t, GetVMNICAddresses(&types.Vm{}), "VM without network section should have no NICs")
+
+	return
+}
+
+func TestGetVMGuestHostname(t *testing.T) {
+
+	vm := &types.Vm{Name: "node-1"}
+	assert.Equal(t, "node-1", GetVMGuestHostname(vm), "VM name should be used without guest customization")
+
+	vm.GuestCustomizationSection = &types.GuestCustomizationSection{ComputerName: "node-1-guest"}
+	assert.Equal(t, "node-1-guest", GetVMGuestHostname(vm), "computer name should be used")
+
+	return
+}
+
+func TestGetNATExternalIPsByInternalIP(t *testing.T) {
+
+	dnatRuleType := swaggerClient.DNAT_NatRuleType
+	natRules := []swaggerClient.EdgeNatRule{
+		{
+			Enabled:           true,
+			Type_:             "SNAT",
+			InternalAddresses: "10.0.0.5",
+			ExternalAddresses: "203.0.113.15",
+		},
+		{
+			Enabled:           true,
+			RuleType:          &dnatRuleType,
+			InternalAddresses: "10.0.0.5",
+			ExternalAddresses: "203.0.113.5",
+		},
+		{
+			Enabled:           true,
+			Type_:             "SNAT",
+			InternalAddresses: "10.0.0.6",
+			ExternalAddresses: "203.0.113.6",
+		},
+		{
+			Enabled:           true,
+			Type_:             "DNAT",
+			InternalAddresses: "10.0.0.7",
+			ExternalAddresses: "203.0.113.7",
+			DnatExternalPort:  "443",
+		},
+		{
+			Enabled:           false,
+			Type_:             "DNAT",
+			InternalAddresses: "10.0.0.8",
+			ExternalAddresses: "203.0.113.8",
+		},
+		{
+			Enabled:           true,
+			Type_:             "SNAT",
+			InternalAddresses: "10.0.0.0/24",
+			ExternalAddresses: "203.0.113.1",
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"10.0.0.5": "203.0.113.5",
+		"10.0.0.6": "203.0.113.6",
+	}, GetNATExternalIPsByInternalIP(natRules),
+		"only enabled one-to-one rules should be mapped, with DNAT rules taking precedence")
+
+	return
+}
@@ -30,7 +30,8 @@ func NewOrgManager(client *Client, orgName string) (*OrgManager, error) {
 }
 
 func (orgManager *OrgManager) GetCatalogByName(catalogName string) (*govcd.Catalog, error) {
-	org, err := orgManager.Client.VCDClient.GetOrgByName(orgManager.OrgName)
+	session := orgManager.Client.Session()
+	org, err := session.VCDClient.GetOrgByName(orgManager.OrgName)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get vcd organization [%s]: [%v]", orgManager.OrgName, err)
 	}
@@ -45,7 +46,8 @@ func (orgManager *OrgManager) GetCatalogByName(catalogName string) (*govcd.Catal
 }
 
 func (orgManager *OrgManager) GetComputePolicyDetailsFromName(computePolicyName string) (*types.VdcComputePolicy, error) {
-	org, err := orgManager.Client.VCDClient.GetOrgByName(orgManager.OrgName)
+	session := orgManager.Client.Session()
+	org, err := session.VCDClient.GetOrgByName(orgManager.OrgName)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get org [%s] by name: [%v]", orgManager.OrgName, err)
 	}
@@ -77,7 +79,8 @@ func (orgManager *OrgManager) GetComputePolicyDetailsFromName(computePolicyName
 func (orgManager *OrgManager) SearchVMAcrossVDCs(vmName string, clusterName string, vmId string,
 	isMultiZoneCluster bool) (*govcd.VM, string, error) {
 
-	org, err := orgManager.Client.VCDClient.GetOrgByName(orgManager.OrgName)
+	session := orgManager.Client.Session()
+	org, err := session.VCDClient.GetOrgByName(orgManager.OrgName)
 	if err != nil {
 		return nil, "", fmt.Errorf("unable to get org by name [%s]: [%v]", orgManager.OrgName, err)
 	}
@@ -85,13 +88,13 @@ func (orgManager *OrgManager) SearchVMAcrossVDCs(vmName string, clusterName stri
 	var vmRecordList []*types.QueryResultVMRecordType = nil
 
 	if vmName != "" {
-		vmRecordList, err = govcd.QueryVmList(types.VmQueryFilterOnlyDeployed, &orgManager.Client.VCDClient.Client,
+		vmRecordList, err = govcd.QueryVmList(types.VmQueryFilterOnlyDeployed, &session.VCDClient.Client,
 			map[string]string{"name": vmName})
 		if err != nil {
 			return nil, "", fmt.Errorf("unable to query all VMs using name [%s]: [%v]", vmName, err)
 		}
 	} else if vmId != "" {
-		vmRecordList, err = govcd.QueryVmList(types.VmQueryFilterOnlyDeployed, &orgManager.Client.VCDClient.Client,
+		vmRecordList, err = govcd.QueryVmList(types.VmQueryFilterOnlyDeployed, &session.VCDClient.Client,
 			map[string]string{"id": vmId})
 		if err != nil {
 			return nil, "", fmt.Errorf("unable to query all VMs using ID [%s]: [%v]", vmId, err)
@@ -109,7 +112,7 @@ func (orgManager *OrgManager) SearchVMAcrossVDCs(vmName string, clusterName stri
 					vmName, vmId, vmRecord.VdcHREF, err)
 			}
 
-			vm, err := orgManager.Client.VCDClient.Client.GetVMByHref(vmRecord.HREF)
+			vm, err := session.VCDClient.Client.GetVMByHref(vmRecord.HREF)
 			if err != nil {
 				return nil, "", fmt.Errorf("unable to find VM [%s, %s] by HREF [%s]: [%v]",
 					vmName, vmId, vmRecord.HREF, err)
@@ -142,3 +145,17 @@ func (orgManager *OrgManager) SearchVMAcrossVDCs(vmName string, clusterName stri
 
 	return nil, "", govcd.ErrorEntityNotFound
 }
+
+// GetComputePolicyNameFromID returns the name of the VDC compute policy with the URN computePolicyID.
+func (orgManager *OrgManager) GetComputePolicyNameFromID(computePolicyID string) (string, error) {
+	session := orgManager.Client.Session()
+	computePolicy, err := session.VCDClient.GetVdcComputePolicyV2ById(computePolicyID)
+	if err != nil {
+		return "", fmt.Errorf("unable to get compute policy [%s]: [%v]", computePolicyID, err)
+	}
+	if computePolicy == nil || computePolicy.VdcComputePolicyV2 == nil {
+		return "", fmt.Errorf("obtained nil compute policy for [%s]", computePolicyID)
+	}
+
+	return computePolicy.VdcComputePolicyV2.Name, nil
+}
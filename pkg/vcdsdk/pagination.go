@@ -0,0 +1,165 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/antihax/optional"
+	"github.com/peterhellberg/link"
+)
+
+const (
+	// DefaultPageSize is the number of items that are requested per page of a list of VCD if no page size is given
+	DefaultPageSize = int32(25)
+	// MaxPageSize is the largest number of items per page that the CloudAPI of VCD returns
+	MaxPageSize = int32(128)
+)
+
+// pagination is the way in which the pages of a list of the CloudAPI of VCD are requested.
+type pagination int
+
+const (
+	// pageNumberPagination requests the pages of a list by their number until the page count of the list is reached,
+	// or until a page is empty if the page count is not known
+	pageNumberPagination pagination = iota
+	// cursorPagination requests the next page of a list with the cursor of the nextPage link of the previous page
+	cursorPagination
+)
+
+// pageRequest is a page of a list that is requested from VCD. PageNum is used by lists with page number pagination,
+// and Cursor by lists with cursor pagination. Cursor is empty for the first page.
+type pageRequest struct {
+	PageNum  int32
+	PageSize int32
+	Cursor   optional.String
+}
+
+// getPageFunc requests page of a list from VCD and returns its items, the page count of the list and the response of
+// VCD. The page count is 0 if it is not known, such as for lists with cursor pagination.
+type getPageFunc[T any] func(page pageRequest) ([]T, int32, *http.Response, error)
+
+// pageIterator returns the pages of a list of VCD one at a time, so that the caller can stop once it found an item.
+type pageIterator[T any] struct {
+	pagination pagination
+	getPage    getPageFunc[T]
+	next       pageRequest
+	done       bool
+}
+
+// newPageIterator returns an iterator over the pages of a list that are requested with getPage. The page size is
+// DefaultPageSize if it is not positive, and at most MaxPageSize.
+func newPageIterator[T any](pagination pagination, pageSize int32, getPage getPageFunc[T]) *pageIterator[T] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	return &pageIterator[T]{
+		pagination: pagination,
+		getPage:    getPage,
+		next: pageRequest{
+			PageNum:  1,
+			PageSize: pageSize,
+			Cursor:   optional.EmptyString(),
+		},
+	}
+}
+
+// nextPage returns the items of the next page. It returns false once all pages were returned or a page could not be
+// requested.
+func (iterator *pageIterator[T]) nextPage() ([]T, bool, error) {
+	if iterator.done {
+		return nil, false, nil
+	}
+
+	items, pageCount, resp, err := iterator.getPage(iterator.next)
+	if err != nil {
+		iterator.done = true
+		return nil, false, err
+	}
+	if len(items) == 0 {
+		iterator.done = true
+		return nil, false, nil
+	}
+
+	pageNum := iterator.next.PageNum
+	if iterator.pagination == pageNumberPagination && pageCount > 0 && pageNum >= pageCount {
+		iterator.done = true
+	}
+	iterator.next.PageNum++
+	if iterator.pagination == cursorPagination {
+		cursor, err := getCursor(resp)
+		if err != nil {
+			iterator.done = true
+			return nil, false, fmt.Errorf("unable to get cursor of page [%d]: [%w]", pageNum, err)
+		}
+		if cursor == "" {
+			iterator.done = true
+		}
+		iterator.next.Cursor = optional.NewString(cursor)
+	}
+	return items, true, nil
+}
+
+// listAllPages returns the items of all pages of a list that are requested with getPage.
+func listAllPages[T any](pagination pagination, pageSize int32, getPage getPageFunc[T]) ([]T, error) {
+	iterator := newPageIterator(pagination, pageSize, getPage)
+	allItems := make([]T, 0)
+	for {
+		items, ok, err := iterator.nextPage()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return allItems, nil
+		}
+		allItems = append(allItems, items...)
+	}
+}
+
+// getCursor returns the cursor of the nextPage link of resp, or an empty string if resp is the last page.
+func getCursor(resp *http.Response) (string, error) {
+	if resp == nil {
+		return "", nil
+	}
+
+	cursorURI := ""
+	for _, linklet := range resp.Header["Link"] {
+		for _, l := range link.Parse(linklet) {
+			if l.Rel == "nextPage" {
+				cursorURI = l.URI
+				break
+			}
+		}
+		if cursorURI != "" {
+			break
+		}
+	}
+	if cursorURI == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(cursorURI)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse cursor URI [%s]: [%w]", cursorURI, err)
+	}
+
+	cursorStr := ""
+	keyMap, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse raw query [%s]: [%w]", u.RawQuery, err)
+	}
+
+	if cursorStrList, ok := keyMap["cursor"]; ok {
+		cursorStr = cursorStrList[0]
+	}
+
+	return cursorStr, nil
+}
@@ -0,0 +1,113 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListAllPages(t *testing.T) {
+
+	// 5 items in pages of 2 by page number, followed by an empty page
+	items := []string{"item1", "item2", "item3", "item4", "item5"}
+	var requestedPages []pageRequest
+	returnPageCount := false
+	getPageByNumber := func(page pageRequest) ([]string, int32, *http.Response, error) {
+		requestedPages = append(requestedPages, page)
+		pageCount := int32(0)
+		if returnPageCount {
+			pageCount = (int32(len(items)) + page.PageSize - 1) / page.PageSize
+		}
+		start := int((page.PageNum - 1) * page.PageSize)
+		if start >= len(items) {
+			return []string{}, pageCount, &http.Response{}, nil
+		}
+		end := start + int(page.PageSize)
+		if end > len(items) {
+			end = len(items)
+		}
+		return items[start:end], pageCount, &http.Response{}, nil
+	}
+
+	allItems, err := listAllPages(pageNumberPagination, 2, getPageByNumber)
+	assert.NoError(t, err, "pages should be listed")
+	assert.Equal(t, items, allItems, "items of all pages should be returned")
+	assert.Len(t, requestedPages, 4, "pages should be requested until a page is empty")
+	assert.Equal(t, int32(3), requestedPages[2].PageNum, "pages should be requested by number")
+
+	// the pages after the page count of the list should not be requested
+	returnPageCount = true
+	requestedPages = nil
+	allItems, err = listAllPages(pageNumberPagination, 2, getPageByNumber)
+	assert.NoError(t, err, "pages should be listed")
+	assert.Equal(t, items, allItems, "items of all pages should be returned")
+	assert.Len(t, requestedPages, 3, "pages should be requested until the page count is reached")
+	returnPageCount = false
+
+	requestedPages = nil
+	_, err = listAllPages(pageNumberPagination, 0, getPageByNumber)
+	assert.NoError(t, err, "pages should be listed")
+	assert.Equal(t, DefaultPageSize, requestedPages[0].PageSize, "default page size should be used")
+	requestedPages = nil
+	_, err = listAllPages(pageNumberPagination, 1000, getPageByNumber)
+	assert.NoError(t, err, "pages should be listed")
+	assert.Equal(t, MaxPageSize, requestedPages[0].PageSize, "page size should be at most the maximum")
+
+	// 3 pages that link to the next page by cursor
+	cursorPages := map[string][]string{
+		"":        {"item1", "item2"},
+		"cursor2": {"item3", "item4"},
+		"cursor3": {"item5"},
+	}
+	nextCursors := map[string]string{
+		"":        "cursor2",
+		"cursor2": "cursor3",
+	}
+	getPageByCursor := func(page pageRequest) ([]string, int32, *http.Response, error) {
+		resp := &http.Response{Header: http.Header{}}
+		if nextCursor, ok := nextCursors[page.Cursor.Value()]; ok {
+			resp.Header.Add("Link",
+				fmt.Sprintf(`<https://vcd.example.com/cloudapi/1.0.0/edgeGateways/gw1/nat/rules?cursor=%s>;rel="nextPage";type="application/json"`,
+					nextCursor))
+		}
+		return cursorPages[page.Cursor.Value()], 0, resp, nil
+	}
+
+	allItems, err = listAllPages(cursorPagination, MaxPageSize, getPageByCursor)
+	assert.NoError(t, err, "pages should be listed")
+	assert.Equal(t, items, allItems, "items of all pages should be returned")
+
+	iterator := newPageIterator(cursorPagination, MaxPageSize, getPageByCursor)
+	page, ok, err := iterator.nextPage()
+	assert.NoError(t, err, "first page should be returned")
+	assert.True(t, ok, "first page should be returned")
+	assert.Equal(t, []string{"item1", "item2"}, page, "first page should have its items")
+	page, ok, _ = iterator.nextPage()
+	assert.True(t, ok, "second page should be returned")
+	assert.Equal(t, []string{"item3", "item4"}, page, "second page should be requested by cursor")
+
+	// the error names the page whose cursor is invalid
+	_, err = listAllPages(cursorPagination, MaxPageSize, func(page pageRequest) ([]string, int32, *http.Response, error) {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Add("Link", `<%zz>;rel="nextPage";type="application/json"`)
+		return []string{"item1"}, 0, resp, nil
+	})
+	assert.ErrorContains(t, err, "unable to get cursor of page [1]", "error should name the page that failed")
+
+	_, err = listAllPages(pageNumberPagination, 2, func(page pageRequest) ([]string, int32, *http.Response, error) {
+		if page.PageNum == 2 {
+			return nil, 0, nil, fmt.Errorf("503 Service Unavailable")
+		}
+		return []string{"item1", "item2"}, 0, &http.Response{}, nil
+	})
+	assert.Error(t, err, "error of a page should be returned")
+
+	return
+}
@@ -0,0 +1,90 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// DefaultBurst is the number of requests to VCD that can be made at once above the configured QPS if no burst is
+	// configured. Requests are not rate limited if no QPS is configured.
+	DefaultBurst = 20
+	// DefaultMaxConcurrentGatewayOperations is the number of operations that modify an edge gateway at the same time
+	// if none is configured; operations on a gateway are serialized by default
+	DefaultMaxConcurrentGatewayOperations = 1
+)
+
+// rateLimitTransport delays requests to VCD so that they do not exceed the rate of a token bucket.
+type rateLimitTransport struct {
+	base        http.RoundTripper
+	rateLimiter flowcontrol.RateLimiter
+}
+
+func (transport *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := transport.rateLimiter.Wait(req.Context()); err != nil {
+		return nil, fmt.Errorf("request [%s %s] was not sent within the rate limit: [%w]", req.Method,
+			req.URL.Path, err)
+	}
+	return transport.base.RoundTrip(req)
+}
+
+// newRateLimitTransport returns a transport that sends requests within the rate of rateLimiter. Requests are not rate
+// limited if rateLimiter is nil.
+func newRateLimitTransport(base http.RoundTripper, rateLimiter flowcontrol.RateLimiter) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if rateLimiter == nil {
+		return base
+	}
+	return &rateLimitTransport{
+		base:        base,
+		rateLimiter: rateLimiter,
+	}
+}
+
+// newRateLimiter returns a rate limiter that allows qps requests per second with bursts of burst requests, or
+// DefaultBurst requests if burst is not positive. It returns nil if qps is not positive.
+func newRateLimiter(qps float32, burst int) flowcontrol.RateLimiter {
+	if qps <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = DefaultBurst
+	}
+	return flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+}
+
+// clientRateLimiter is the rate limiter of the requests of a Client to VCD. It is shared by the govcd and swagger
+// clients of all sessions of the Client, so that authenticating again does not reset the rate limit.
+type clientRateLimiter struct {
+	lock        sync.Mutex
+	qps         float32
+	burst       int
+	rateLimiter flowcontrol.RateLimiter
+}
+
+// get returns the rate limiter for qps and burst. The rate limiter is only replaced when qps or burst change. A nil
+// clientRateLimiter, as used by auth configs that do not belong to a Client, returns a new rate limiter.
+func (crl *clientRateLimiter) get(qps float32, burst int) flowcontrol.RateLimiter {
+	if crl == nil {
+		return newRateLimiter(qps, burst)
+	}
+
+	crl.lock.Lock()
+	defer crl.lock.Unlock()
+	if crl.rateLimiter == nil || crl.qps != qps || crl.burst != burst {
+		crl.qps = qps
+		crl.burst = burst
+		crl.rateLimiter = newRateLimiter(qps, burst)
+	}
+	return crl.rateLimiter
+}
@@ -0,0 +1,93 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+// DefaultRetryBackoff is the backoff of the retries of idempotent requests to VCD that failed transiently
+var DefaultRetryBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+	Steps:    4,
+}
+
+// retryTransport retries idempotent requests to VCD with exponential backoff if the connection fails or VCD is
+// temporarily unavailable. Other requests are not retried since VCD may have processed them.
+type retryTransport struct {
+	base    http.RoundTripper
+	backoff wait.Backoff
+}
+
+func isIdempotentRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.Body == nil || req.Body == http.NoBody
+	}
+	return false
+}
+
+// isTransientResponse returns true if the request failed in a way that is expected to resolve on retry.
+func isTransientResponse(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return getVCDErrorKindFromStatus(resp.StatusCode, "", "") == VCDErrorTransient
+}
+
+func (transport *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isIdempotentRequest(req) {
+		return transport.base.RoundTrip(req)
+	}
+
+	backoff := transport.backoff
+	for {
+		resp, err := transport.base.RoundTrip(req)
+		if backoff.Steps <= 1 || !isTransientResponse(req, resp, err) {
+			return resp, err
+		}
+
+		delay := backoff.Step()
+		if err != nil {
+			klog.Infof("request [%s %s] failed; retrying in [%v]: [%v]", req.Method, req.URL.Path, delay, err)
+		} else {
+			klog.Infof("request [%s %s] returned [%s]; retrying in [%v]", req.Method, req.URL.Path, resp.Status,
+				delay)
+			// the connection can be reused once the body is read
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func newRetryTransport(base http.RoundTripper, backoff wait.Backoff) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{
+		base:    base,
+		backoff: backoff,
+	}
+}
@@ -0,0 +1,61 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestRetryTransport(t *testing.T) {
+
+	var numRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&numRequests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{
+		Transport: newRetryTransport(nil, wait.Backoff{
+			Duration: time.Millisecond,
+			Factor:   2.0,
+			Steps:    4,
+		}),
+	}
+
+	resp, err := httpClient.Get(server.URL)
+	assert.NoError(t, err, "GET should succeed after retries")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "GET should be retried until it succeeds")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&numRequests), "GET should be sent three times")
+	_ = resp.Body.Close()
+
+	atomic.StoreInt32(&numRequests, 0)
+	resp, err = httpClient.Post(server.URL, "application/json", strings.NewReader("{}"))
+	assert.NoError(t, err, "POST should not fail")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "POST should not be retried")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&numRequests), "POST should be sent once")
+	_ = resp.Body.Close()
+
+	atomic.StoreInt32(&numRequests, -10)
+	resp, err = httpClient.Get(server.URL)
+	assert.NoError(t, err, "GET should return the last response when retries are exhausted")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "GET should stop after the backoff steps")
+	assert.Equal(t, int32(-6), atomic.LoadInt32(&numRequests), "GET should be sent once per backoff step")
+	_ = resp.Body.Close()
+
+	return
+}
@@ -0,0 +1,417 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"k8s.io/klog"
+)
+
+const (
+	PrefixListActionPermit = "PERMIT"
+)
+
+// RouteAdvertisement describes how load balancer VIPs are advertised by the edge gateway.
+type RouteAdvertisement struct {
+	// Subnets are advertised as a whole while they contain at least one VIP. A VIP outside all of them is
+	// advertised as a host prefix.
+	Subnets []string
+	// BGPPrefixList is the name of an edge gateway prefix list that is kept in sync with the advertised
+	// prefixes so that BGP route filters can refer to it. It is not used if empty.
+	BGPPrefixList string
+}
+
+// normalizeCIDR returns the network definition of cidr, e.g. 10.0.0.5/24 becomes 10.0.0.0/24, which is
+// also how VCD stores advertised subnets.
+func normalizeCIDR(cidr string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse CIDR [%s]: [%w]", cidr, err)
+	}
+	return ipNet.String(), nil
+}
+
+// GetVIPAdvertisedPrefix returns the prefix that is advertised for vip. This is the first of subnets that
+// contains vip, or a host prefix of vip if there is none.
+func GetVIPAdvertisedPrefix(vip string, subnets []string) (string, error) {
+	ip := net.ParseIP(vip)
+	if ip == nil {
+		return "", fmt.Errorf("unable to parse VIP [%s]", vip)
+	}
+	for _, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse route advertisement subnet [%s]: [%w]", subnet, err)
+		}
+		if ipNet.Contains(ip) {
+			return ipNet.String(), nil
+		}
+	}
+	if ip.To4() != nil {
+		return fmt.Sprintf("%s/32", ip.String()), nil
+	}
+	return fmt.Sprintf("%s/128", ip.String()), nil
+}
+
+// isPrefixInUse returns true if any of vips is advertised through prefix.
+func isPrefixInUse(prefix string, vips []string, subnets []string) (bool, error) {
+	for _, vip := range vips {
+		if vip == "" {
+			continue
+		}
+		vipPrefix, err := GetVIPAdvertisedPrefix(vip, subnets)
+		if err != nil {
+			return false, fmt.Errorf("unable to get advertised prefix of VIP [%s]: [%w]", vip, err)
+		}
+		if vipPrefix == prefix {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// updatePrefixes adds prefixToAdd to and removes prefixToRemove from currentPrefixes. Prefixes are compared
+// by their network definition. The second return value is false if nothing needed to change.
+func updatePrefixes(currentPrefixes []string, prefixToAdd string, prefixToRemove string) ([]string, bool) {
+	updatedPrefixes := make([]string, 0, len(currentPrefixes)+1)
+	found := false
+	changed := false
+	for _, prefix := range currentPrefixes {
+		normalizedPrefix, err := normalizeCIDR(prefix)
+		if err != nil {
+			// keep whatever VCD returned as is; it is not one of ours
+			normalizedPrefix = prefix
+		}
+		if prefixToRemove != "" && normalizedPrefix == prefixToRemove {
+			changed = true
+			continue
+		}
+		if prefixToAdd != "" && normalizedPrefix == prefixToAdd {
+			found = true
+		}
+		updatedPrefixes = append(updatedPrefixes, prefix)
+	}
+	if prefixToAdd != "" && !found {
+		updatedPrefixes = append(updatedPrefixes, prefixToAdd)
+		changed = true
+	}
+	sort.Strings(updatedPrefixes)
+	return updatedPrefixes, changed
+}
+
+// waitForGatewayUpdate waits for the task of an asynchronous edge gateway update to complete.
+func (gm *GatewayManager) waitForGatewayUpdate(ctx context.Context, resp *http.Response, err error, description string) error {
+	session := gm.Client.Session()
+	if err != nil {
+		var responseMessageBytes []byte
+		if gsErr, ok := err.(swaggerClient.GenericSwaggerError); ok {
+			responseMessageBytes = gsErr.Body()
+		}
+		return fmt.Errorf("unable to update %s of gateway [%s]: resp: [%s]: [%v]",
+			description, gm.GatewayRef.Name, string(responseMessageBytes), err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusAccepted {
+		return nil
+	}
+
+	taskURL := resp.Header.Get("Location")
+	task := govcd.NewTask(&session.VCDClient.Client)
+	task.Task.HREF = taskURL
+	if err = waitTaskCompletion(ctx, task); err != nil {
+		return fmt.Errorf("unable to update %s of gateway [%s]; task [%s] did not complete: [%w]",
+			description, gm.GatewayRef.Name, taskURL, err)
+	}
+	return nil
+}
+
+// updateAdvertisedPrefix adds prefixToAdd to and removes prefixToRemove from the route advertisement of the
+// gateway and from the BGP prefix list, if one is configured. It returns true if the route advertisement changed.
+func (gm *GatewayManager) updateAdvertisedPrefix(ctx context.Context, prefixToAdd string, prefixToRemove string,
+	bgpPrefixList string) (bool, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.updateAdvertisedPrefix")
+	defer span.End()
+
+	client := gm.Client
+	session := client.Session()
+	if gm.GatewayRef == nil {
+		return false, fmt.Errorf("gateway reference should not be nil")
+	}
+
+	routeAdvertisement, resp, err := session.APIClient.EdgeGatewayRouteAdvertisementApi.GetRouteAdvertisement(ctx,
+		gm.GatewayRef.Id)
+	if err != nil {
+		return false, fmt.Errorf("unable to get route advertisement of gateway [%s]: resp: [%v]: [%w]",
+			gm.GatewayRef.Name, resp, err)
+	}
+	subnets, changed := updatePrefixes(routeAdvertisement.Subnets, prefixToAdd, prefixToRemove)
+	if changed {
+		routeAdvertisement.Subnets = subnets
+		if len(subnets) > 0 {
+			routeAdvertisement.Enable = true
+		}
+		resp, err = session.APIClient.EdgeGatewayRouteAdvertisementApi.UpdateRouteAdvertisement(ctx,
+			routeAdvertisement, gm.GatewayRef.Id)
+		if err = gm.waitForGatewayUpdate(ctx, resp, err, "route advertisement"); err != nil {
+			return false, err
+		}
+		klog.Infof("updated route advertisement of gateway [%s]: added [%s], removed [%s]",
+			gm.GatewayRef.Name, prefixToAdd, prefixToRemove)
+	}
+
+	if bgpPrefixList == "" {
+		return changed, nil
+	}
+
+	bgpConfig, resp, err := session.APIClient.EdgeGatewayBgpApi.GetBgpConfig(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return changed, fmt.Errorf("unable to get BGP config of gateway [%s]: resp: [%v]: [%w]", gm.GatewayRef.Name, resp, err)
+	}
+	if !bgpConfig.Enabled {
+		klog.Warningf("BGP is not enabled on gateway [%s]; prefix list [%s] will not take effect until it is",
+			gm.GatewayRef.Name, bgpPrefixList)
+	}
+
+	return changed, gm.updateBGPPrefixList(ctx, bgpPrefixList, prefixToAdd, prefixToRemove)
+}
+
+// updateBGPPrefixList keeps the PERMIT entries of the prefix list bgpPrefixList in sync with the advertised
+// prefixes. The prefix list is created if it does not exist.
+func (gm *GatewayManager) updateBGPPrefixList(ctx context.Context, bgpPrefixList string, prefixToAdd string,
+	prefixToRemove string) error {
+	ctx, span := startSpan(ctx, "GatewayManager.updateBGPPrefixList")
+	defer span.End()
+
+	client := gm.Client
+	session := client.Session()
+	prefixLists, resp, err := session.APIClient.EdgeGatewayPrefixListsApi.GetPrefixLists(ctx, gm.GatewayRef.Id, nil)
+	if err != nil {
+		return fmt.Errorf("unable to get prefix lists of gateway [%s]: resp: [%v]: [%w]",
+			gm.GatewayRef.Name, resp, err)
+	}
+
+	var prefixList *swaggerClient.EdgePrefixList
+	for idx := range prefixLists.Values {
+		if prefixLists.Values[idx].Name == bgpPrefixList {
+			prefixList = &prefixLists.Values[idx]
+			break
+		}
+	}
+
+	if prefixList == nil {
+		if prefixToAdd == "" {
+			return nil
+		}
+		newPrefixList := swaggerClient.EdgePrefixList{
+			Name:        bgpPrefixList,
+			Description: "Load balancer VIP prefixes advertised by the cloud provider",
+			Prefixes: []swaggerClient.EdgePrefixListEntry{
+				{
+					Network: prefixToAdd,
+					Action:  PrefixListActionPermit,
+				},
+			},
+		}
+		resp, err = session.APIClient.EdgeGatewayPrefixListsApi.CreatePrefixList(ctx, newPrefixList, gm.GatewayRef.Id)
+		if err = gm.waitForGatewayUpdate(ctx, resp, err, fmt.Sprintf("prefix list [%s]", bgpPrefixList)); err != nil {
+			return err
+		}
+		klog.Infof("created prefix list [%s] on gateway [%s] with prefix [%s]", bgpPrefixList,
+			gm.GatewayRef.Name, prefixToAdd)
+		return nil
+	}
+
+	networks := make([]string, 0, len(prefixList.Prefixes))
+	for _, entry := range prefixList.Prefixes {
+		if entry.Action == PrefixListActionPermit {
+			networks = append(networks, entry.Network)
+		}
+	}
+	if _, changed := updatePrefixes(networks, prefixToAdd, prefixToRemove); !changed {
+		return nil
+	}
+
+	entries := make([]swaggerClient.EdgePrefixListEntry, 0, len(prefixList.Prefixes)+1)
+	found := false
+	for _, entry := range prefixList.Prefixes {
+		normalizedNetwork, err := normalizeCIDR(entry.Network)
+		if err != nil {
+			normalizedNetwork = entry.Network
+		}
+		if entry.Action == PrefixListActionPermit && prefixToRemove != "" && normalizedNetwork == prefixToRemove {
+			continue
+		}
+		if entry.Action == PrefixListActionPermit && prefixToAdd != "" && normalizedNetwork == prefixToAdd {
+			found = true
+		}
+		entries = append(entries, entry)
+	}
+	if prefixToAdd != "" && !found {
+		entries = append(entries, swaggerClient.EdgePrefixListEntry{
+			Network: prefixToAdd,
+			Action:  PrefixListActionPermit,
+		})
+	}
+	prefixList.Prefixes = entries
+
+	resp, err = session.APIClient.EdgeGatewayPrefixListApi.UpdatePrefixList(ctx, *prefixList, gm.GatewayRef.Id,
+		prefixList.Id)
+	if err = gm.waitForGatewayUpdate(ctx, resp, err, fmt.Sprintf("prefix list [%s]", bgpPrefixList)); err != nil {
+		return err
+	}
+	klog.Infof("updated prefix list [%s] of gateway [%s]: added [%s], removed [%s]", bgpPrefixList,
+		gm.GatewayRef.Name, prefixToAdd, prefixToRemove)
+	return nil
+}
+
+// GetGatewayExternalIPs returns the IPs that are in use on a gateway by its DNAT rules and virtual services.
+func GetGatewayExternalIPs(natRules []swaggerClient.EdgeNatRule,
+	vsSummaries []swaggerClient.EdgeLoadBalancerVirtualServiceSummary) []string {
+	externalIPs := make([]string, 0, len(natRules)+len(vsSummaries))
+	for idx := range natRules {
+		if getNATRuleType(&natRules[idx]) != string(swaggerClient.DNAT_NatRuleType) {
+			continue
+		}
+		// DNAT rules may use an IP range or CIDR; only single IPs can be in a load balancer prefix
+		if net.ParseIP(natRules[idx].ExternalAddresses) != nil {
+			externalIPs = append(externalIPs, natRules[idx].ExternalAddresses)
+		}
+	}
+	for _, vsSummary := range vsSummaries {
+		if vsSummary.VirtualIpAddress != "" {
+			externalIPs = append(externalIPs, vsSummary.VirtualIpAddress)
+		}
+	}
+	return externalIPs
+}
+
+// listGatewayExternalIPs returns the IPs that are in use on the gateway by the DNAT rules and virtual services of
+// all clusters and of the administrator, and the IPs allocated by load balancer operations that are in progress.
+func (gm *GatewayManager) listGatewayExternalIPs(ctx context.Context) ([]string, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.listGatewayExternalIPs")
+	defer span.End()
+
+	client := gm.Client
+	session := client.Session()
+	natRules, err := gm.listNATRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	org, err := client.GetOrgByName(client.ClusterOrgName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting org by name for org [%s]: [%v]", client.ClusterOrgName, err)
+	}
+	if org == nil || org.Org == nil {
+		return nil, fmt.Errorf("obtained nil org when getting org by name [%s]", client.ClusterOrgName)
+	}
+	vsSummaries, err := listAllPages(pageNumberPagination, MaxPageSize,
+		func(page pageRequest) ([]swaggerClient.EdgeLoadBalancerVirtualServiceSummary, int32, *http.Response, error) {
+			vsSummaries, resp, err := session.APIClient.EdgeGatewayLoadBalancerVirtualServicesApi.GetVirtualServiceSummariesForGateway(
+				ctx, page.PageNum, page.PageSize, gm.GatewayRef.Id, org.Org.ID, nil)
+			return vsSummaries.Values, vsSummaries.PageCount, resp, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get virtual service summaries of gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
+
+	externalIPs := GetGatewayExternalIPs(natRules, vsSummaries)
+	pendingIPs := make(map[string]bool)
+	client.pendingIPs.addTo(gm.GatewayRef.Id, pendingIPs)
+	for ip := range pendingIPs {
+		externalIPs = append(externalIPs, ip)
+	}
+	return externalIPs, nil
+}
+
+// AdvertiseLoadBalancerVIP makes sure that the prefix containing vip is advertised by the gateway. It returns the
+// prefix and whether it was added to the route advertisement by this call rather than already advertised, e.g. by
+// another cluster or the administrator.
+func (gm *GatewayManager) AdvertiseLoadBalancerVIP(ctx context.Context, vip string,
+	routeAdvertisement *RouteAdvertisement) (string, bool, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.AdvertiseLoadBalancerVIP")
+	defer span.End()
+
+	if routeAdvertisement == nil || vip == "" {
+		return "", false, nil
+	}
+
+	prefix, err := GetVIPAdvertisedPrefix(vip, routeAdvertisement.Subnets)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to get prefix to advertise for VIP [%s]: [%w]", vip, err)
+	}
+
+	if gm.GatewayRef == nil {
+		return "", false, fmt.Errorf("gateway reference should not be nil")
+	}
+
+	client := gm.Client
+	unlockGateway, err := client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to lock gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
+	defer unlockGateway()
+
+	added, err := gm.updateAdvertisedPrefix(ctx, prefix, "", routeAdvertisement.BGPPrefixList)
+	if err != nil {
+		return prefix, added, fmt.Errorf("unable to advertise prefix [%s] of VIP [%s]: [%w]", prefix, vip, err)
+	}
+	return prefix, added, nil
+}
+
+// WithdrawLoadBalancerVIP stops advertising the prefix that contained vip if no IP in use on the gateway is in it.
+// The IPs of the DNAT rules and virtual services of the whole gateway are checked since the prefix may be shared
+// with other clusters. It returns true if the prefix was withdrawn. The caller should only withdraw prefixes that
+// it added itself.
+func (gm *GatewayManager) WithdrawLoadBalancerVIP(ctx context.Context, vip string,
+	routeAdvertisement *RouteAdvertisement) (bool, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.WithdrawLoadBalancerVIP")
+	defer span.End()
+
+	if routeAdvertisement == nil || vip == "" {
+		return false, nil
+	}
+
+	prefix, err := GetVIPAdvertisedPrefix(vip, routeAdvertisement.Subnets)
+	if err != nil {
+		return false, fmt.Errorf("unable to get advertised prefix of VIP [%s]: [%w]", vip, err)
+	}
+
+	if gm.GatewayRef == nil {
+		return false, fmt.Errorf("gateway reference should not be nil")
+	}
+
+	client := gm.Client
+	unlockGateway, err := client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return false, fmt.Errorf("unable to lock gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
+	defer unlockGateway()
+
+	externalIPs, err := gm.listGatewayExternalIPs(ctx)
+	if err != nil {
+		return false, fmt.Errorf("unable to get IPs in use on gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
+	inUse, err := isPrefixInUse(prefix, externalIPs, routeAdvertisement.Subnets)
+	if err != nil {
+		return false, fmt.Errorf("unable to check if prefix [%s] is in use: [%w]", prefix, err)
+	}
+	if inUse {
+		klog.Infof("prefix [%s] still contains IPs in use on gateway [%s]; not withdrawing it", prefix,
+			gm.GatewayRef.Name)
+		return false, nil
+	}
+
+	if _, err = gm.updateAdvertisedPrefix(ctx, "", prefix, routeAdvertisement.BGPPrefixList); err != nil {
+		return false, fmt.Errorf("unable to withdraw prefix [%s] of VIP [%s]: [%w]", prefix, vip, err)
+	}
+	return true, nil
+}
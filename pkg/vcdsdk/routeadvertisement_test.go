@@ -0,0 +1,116 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
+)
+
+func TestGetVIPAdvertisedPrefix(t *testing.T) {
+
+	subnets := []string{"10.10.0.0/24", "192.168.8.5/28"}
+
+	prefix, err := GetVIPAdvertisedPrefix("10.10.0.20", subnets)
+	assert.NoError(t, err, "there should be no error for a VIP in a subnet")
+	assert.Equal(t, "10.10.0.0/24", prefix, "VIP in a subnet should be advertised through the subnet")
+
+	prefix, err = GetVIPAdvertisedPrefix("192.168.8.3", subnets)
+	assert.NoError(t, err, "there should be no error for a VIP in a subnet")
+	assert.Equal(t, "192.168.8.0/28", prefix, "subnet should be advertised through its network definition")
+
+	prefix, err = GetVIPAdvertisedPrefix("172.16.0.1", subnets)
+	assert.NoError(t, err, "there should be no error for a VIP outside all subnets")
+	assert.Equal(t, "172.16.0.1/32", prefix, "VIP outside all subnets should be advertised as a host prefix")
+
+	_, err = GetVIPAdvertisedPrefix("not-an-ip", subnets)
+	assert.Error(t, err, "invalid VIP should return an error")
+
+	_, err = GetVIPAdvertisedPrefix("10.10.0.20", []string{"10.10.0.0"})
+	assert.Error(t, err, "invalid subnet should return an error")
+
+	return
+}
+
+func TestIsPrefixInUse(t *testing.T) {
+
+	subnets := []string{"10.10.0.0/24"}
+
+	inUse, err := isPrefixInUse("10.10.0.0/24", []string{"172.16.0.1", "10.10.0.7"}, subnets)
+	assert.NoError(t, err, "there should be no error checking prefix usage")
+	assert.True(t, inUse, "subnet with a remaining VIP should be in use")
+
+	inUse, err = isPrefixInUse("10.10.0.0/24", []string{"172.16.0.1", ""}, subnets)
+	assert.NoError(t, err, "there should be no error checking prefix usage")
+	assert.False(t, inUse, "subnet without remaining VIPs should not be in use")
+
+	inUse, err = isPrefixInUse("172.16.0.1/32", []string{"172.16.0.1"}, subnets)
+	assert.NoError(t, err, "there should be no error checking prefix usage")
+	assert.True(t, inUse, "host prefix of a remaining VIP should be in use")
+
+	return
+}
+
+func TestUpdatePrefixes(t *testing.T) {
+
+	prefixes, changed := updatePrefixes([]string{"10.10.0.0/24"}, "172.16.0.1/32", "")
+	assert.True(t, changed, "adding a new prefix should change the list")
+	assert.Equal(t, []string{"10.10.0.0/24", "172.16.0.1/32"}, prefixes, "new prefix should be added")
+
+	prefixes, changed = updatePrefixes([]string{"10.10.0.1/24"}, "10.10.0.0/24", "")
+	assert.False(t, changed, "adding an existing prefix should not change the list")
+	assert.Equal(t, []string{"10.10.0.1/24"}, prefixes, "existing prefix should be retained as is")
+
+	prefixes, changed = updatePrefixes([]string{"10.10.0.0/24", "172.16.0.1/32"}, "", "10.10.0.0/24")
+	assert.True(t, changed, "removing a prefix should change the list")
+	assert.Equal(t, []string{"172.16.0.1/32"}, prefixes, "prefix should be removed")
+
+	prefixes, changed = updatePrefixes(nil, "", "10.10.0.0/24")
+	assert.False(t, changed, "removing an absent prefix should not change the list")
+	assert.Empty(t, prefixes, "list should remain empty")
+
+	return
+}
+
+func TestGetGatewayExternalIPs(t *testing.T) {
+
+	natRules := []swaggerClient.EdgeNatRule{
+		{
+			Name:              "cluster1-dnat-http",
+			Type_:             string(swaggerClient.DNAT_NatRuleType),
+			ExternalAddresses: "10.10.0.5",
+			InternalAddresses: "192.168.1.5",
+		},
+		{
+			Name:              "admin-dnat-range",
+			Type_:             string(swaggerClient.DNAT_NatRuleType),
+			ExternalAddresses: "10.10.1.0/24",
+			InternalAddresses: "192.168.2.0/24",
+		},
+		{
+			Name:              "snat",
+			Type_:             string(swaggerClient.SNAT_NatRuleType),
+			ExternalAddresses: "10.10.0.6",
+			InternalAddresses: "192.168.1.0/24",
+		},
+	}
+	vsSummaries := []swaggerClient.EdgeLoadBalancerVirtualServiceSummary{
+		{Name: "cluster2-vs", VirtualIpAddress: "10.10.0.7"},
+		{Name: "pending-vs"},
+	}
+
+	externalIPs := GetGatewayExternalIPs(natRules, vsSummaries)
+	assert.ElementsMatch(t, []string{"10.10.0.5", "10.10.0.7"}, externalIPs,
+		"external IPs of DNAT rules and VIPs of virtual services should be returned")
+
+	inUse, err := isPrefixInUse("10.10.0.0/24", externalIPs, []string{"10.10.0.0/24"})
+	assert.NoError(t, err, "there should be no error checking prefix usage")
+	assert.True(t, inUse, "subnet with IPs of other clusters should be in use")
+
+	return
+}
@@ -0,0 +1,71 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"k8s.io/klog"
+)
+
+// serviceAccountTokenAPIVersion is the API version of the OAuth token endpoint; it is not an OpenAPI endpoint
+const serviceAccountTokenAPIVersion = "37.0"
+
+// GetServiceAccountTokenURL returns the OAuth token endpoint of the org of a service account of the VCD site at
+// vcdHREF. Service accounts of the system org use the provider endpoint.
+func GetServiceAccountTokenURL(vcdHREF url.URL, org string) string {
+	tenant := fmt.Sprintf("tenant/%s", org)
+	if strings.EqualFold(org, "system") {
+		tenant = "provider"
+	}
+	return fmt.Sprintf("%s://%s/oauth/%s/token", vcdHREF.Scheme, vcdHREF.Host, tenant)
+}
+
+// authenticateServiceAccount authenticates vcdClient as the service account with the client ID and refresh token of
+// the config. Refresh tokens of service accounts can only be used once; the refresh token returned by VCD replaces
+// the one of the config and is passed to OnRefreshTokenRotated to be persisted.
+func (config *VCDAuthConfig) authenticateServiceAccount(vcdClient *govcd.VCDClient) error {
+	tokenURL := GetServiceAccountTokenURL(vcdClient.Client.VCDHREF, config.UserOrg)
+	urlRef, err := url.ParseRequestURI(tokenURL)
+	if err != nil {
+		return fmt.Errorf("unable to parse url [%s]: [%v]", tokenURL, err)
+	}
+
+	tokenRefresh := &types.ApiTokenRefresh{}
+	if err = vcdClient.Client.OpenApiPostUrlEncoded(serviceAccountTokenAPIVersion, urlRef, nil, map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": config.RefreshToken,
+		"client_id":     config.ServiceAccountClientID,
+	}, tokenRefresh, nil); err != nil {
+		return fmt.Errorf("unable to get access token of service account [%s] in org [%s]: [%v]",
+			config.ServiceAccountClientID, config.UserOrg, err)
+	}
+	// the refresh token that was used cannot be used again, even if the access token is not usable
+	if tokenRefresh.RefreshToken != "" && tokenRefresh.RefreshToken != config.RefreshToken {
+		config.RefreshToken = tokenRefresh.RefreshToken
+		klog.Infof("Refresh token of service account [%s] was rotated", config.ServiceAccountClientID)
+		if config.OnRefreshTokenRotated != nil {
+			if err = config.OnRefreshTokenRotated(tokenRefresh.RefreshToken); err != nil {
+				klog.Errorf("unable to persist rotated refresh token of service account [%s]; authentication "+
+					"will fail after a restart: [%v]", config.ServiceAccountClientID, err)
+			}
+		}
+	}
+	if tokenRefresh.AccessToken == "" {
+		return fmt.Errorf("no access token returned for service account [%s] in org [%s]",
+			config.ServiceAccountClientID, config.UserOrg)
+	}
+	if err = vcdClient.SetToken(config.UserOrg, govcd.BearerTokenHeader, tokenRefresh.AccessToken); err != nil {
+		return fmt.Errorf("unable to authenticate service account [%s] in org [%s]: [%v]",
+			config.ServiceAccountClientID, config.UserOrg, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,307 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	swaggerClient37 "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"k8s.io/klog"
+)
+
+const (
+	// defaultSessionLifetime is the lifetime assumed for sessions whose token does not carry an expiry
+	defaultSessionLifetime = 20 * time.Minute
+	// sessionRefreshMargin is the time before the expiry of a session at which it is refreshed
+	sessionRefreshMargin = 2 * time.Minute
+)
+
+// Session is a snapshot of the authenticated clients of a Client. A Session is not modified once it is published, so
+// an operation that takes a snapshot with Client.Session uses the same credentials, clients and VDC throughout, even if
+// the session of the Client is replaced concurrently by a token refresh or a rotation of credentials.
+type Session struct {
+	VCDAuthConfig *VCDAuthConfig
+	VCDClient     *govcd.VCDClient
+	APIClient     *swaggerClient37.APIClient
+	VDC           *govcd.Vdc
+
+	validity *sessionValidity
+}
+
+// isValid returns true if the session does not need to be refreshed at now.
+func (session *Session) isValid(now time.Time) bool {
+	if session == nil {
+		return false
+	}
+	return session.validity.isValid(now)
+}
+
+// sessionValidity tracks the expiry of the bearer token shared by the govcd and swagger clients of a Session.
+type sessionValidity struct {
+	// expiry is the expiry of the token in Unix nanoseconds; 0 marks a session that VCD no longer accepts
+	expiry atomic.Int64
+	// published is set once the session is the session of its Client; only then are unauthorized requests retried
+	published atomic.Bool
+}
+
+// isValid returns true if the session does not need to be refreshed at now.
+func (validity *sessionValidity) isValid(now time.Time) bool {
+	if validity == nil {
+		return false
+	}
+	return now.Add(sessionRefreshMargin).UnixNano() < validity.expiry.Load()
+}
+
+func (validity *sessionValidity) invalidate() {
+	validity.expiry.Store(0)
+}
+
+// GetTokenExpiry returns the expiry in the exp claim of a JWT bearer token, and false if the token has no expiry.
+func GetTokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	if err = json.Unmarshal(payload, &claims); err != nil || claims.Exp <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
+
+// sessionTransport invalidates its session when VCD rejects a request as unauthorized, so that the session is
+// authenticated again instead of waiting for the expiry of the token. Once the session is published, the rejected
+// request is retried once with the token of the refreshed session.
+type sessionTransport struct {
+	base     http.RoundTripper
+	validity *sessionValidity
+	// token is the token of the session in the authorization headers of the requests
+	token string
+	// refreshSession returns the current session of the client after authenticating again if needed
+	refreshSession func() (*Session, error)
+}
+
+func (transport *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := transport.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	klog.Infof("request [%s %s] was unauthorized; the vcd session will be refreshed", req.Method, req.URL.Path)
+	transport.validity.invalidate()
+	if transport.refreshSession == nil || !transport.validity.published.Load() {
+		return resp, nil
+	}
+	retryReq, ok := transport.newRetryRequest(req)
+	if !ok {
+		return resp, nil
+	}
+	session, err := transport.refreshSession()
+	if err != nil {
+		klog.Errorf("unable to refresh vcd session to retry request [%s %s]: [%v]", req.Method, req.URL.Path, err)
+		return resp, nil
+	}
+	setAuthorizationToken(retryReq, transport.token, session.VCDClient.Client.VCDToken)
+	drainAndCloseBody(resp)
+
+	retryResp, err := transport.base.RoundTrip(retryReq)
+	if err == nil && retryResp.StatusCode == http.StatusUnauthorized {
+		klog.Errorf("request [%s %s] was unauthorized with a refreshed vcd session", req.Method, req.URL.Path)
+		session.validity.invalidate()
+	}
+	return retryResp, err
+}
+
+// newRetryRequest returns a copy of req with a new body, and false if the body of req cannot be sent again.
+func (transport *sessionTransport) newRetryRequest(req *http.Request) (*http.Request, bool) {
+	retryReq := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return retryReq, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	retryReq.Body = body
+	return retryReq, true
+}
+
+// setAuthorizationToken replaces oldToken with newToken in the authorization headers of req. govcd sends the token in
+// the X-Vcloud-Authorization or access token headers and as a bearer token, and the swagger client as a bearer token.
+func setAuthorizationToken(req *http.Request, oldToken string, newToken string) {
+	if oldToken == "" {
+		return
+	}
+	for key, values := range req.Header {
+		for i, value := range values {
+			if strings.Contains(value, oldToken) {
+				req.Header[key][i] = strings.ReplaceAll(value, oldToken, newToken)
+			}
+		}
+	}
+}
+
+func drainAndCloseBody(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if err := resp.Body.Close(); err != nil {
+		klog.V(3).Infof("unable to close body of unauthorized response: [%v]", err)
+	}
+}
+
+func newSessionTransport(base http.RoundTripper, validity *sessionValidity, token string,
+	refreshSession func() (*Session, error)) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &sessionTransport{
+		base:           base,
+		validity:       validity,
+		token:          token,
+		refreshSession: refreshSession,
+	}
+}
+
+// authenticate returns a new govcd client authenticated as the service account, or with the refresh token or user and
+// password of the config.
+// Unlike GetBearerToken, it does not probe whether the user is a system administrator; the result of the first
+// authentication is reused.
+func (config *VCDAuthConfig) authenticate() (*govcd.VCDClient, error) {
+	href := fmt.Sprintf("%s/api", config.Host)
+	vcdClient, err := config.newVCDClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ServiceAccountClientID != "" {
+		if err = config.authenticateServiceAccount(vcdClient); err != nil {
+			return nil, err
+		}
+	} else if config.RefreshToken != "" {
+		userOrg := config.UserOrg
+		if config.IsSysAdmin {
+			userOrg = "system"
+		}
+		if err = vcdClient.SetToken(userOrg, govcd.ApiTokenHeader, config.RefreshToken); err != nil {
+			return nil, fmt.Errorf("failed to refresh VCD client with the refresh token: [%v]", err)
+		}
+	} else if config.User != "" && config.Password != "" {
+		resp, err := vcdClient.GetAuthResponse(config.User, config.Password, config.UserOrg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to authenticate [%s/%s] for url [%s]: [%+v] : [%v]",
+				config.UserOrg, config.User, href, resp, err)
+		}
+	} else {
+		return nil, fmt.Errorf(
+			"unable to find refresh token or secret to refresh vcd client for user [%s/%s] and url [%s]",
+			config.UserOrg, config.User, href)
+	}
+
+	return vcdClient, nil
+}
+
+// setSession makes the authenticated vcdClient the session of the client. The org and VDC of the cluster are
+// fetched with the new session if getVdcClient is set, and the client is left unchanged if they cannot be fetched.
+// A new swagger client is created with the token of the session, and the lookups cached with the previous session are
+// dropped. The caller should hold the session lock.
+func (client *Client) setSession(authConfig *VCDAuthConfig, vcdClient *govcd.VCDClient, getVdcClient bool) error {
+	validity := &sessionValidity{}
+	token := vcdClient.Client.VCDToken
+	// the govcd and swagger clients share the connections to the VCD site
+	transport := vcdClient.Client.Http.Transport
+	vcdClient.Client.Http.Transport = newSessionTransport(transport, validity, token, client.refreshSession)
+
+	var vdc *govcd.Vdc = nil
+	if getVdcClient {
+		org, err := vcdClient.GetOrgByNameOrId(client.ClusterOrgName)
+		if err != nil {
+			return fmt.Errorf("unable to get vcd organization [%s]: [%v]", client.ClusterOrgName, err)
+		}
+		vdc, err = org.GetVDCByNameOrId(client.ClusterOVDCIdentifier, true)
+		if err != nil {
+			return fmt.Errorf("unable to get VDC from org [%s], VDC [%s]: [%v]",
+				client.ClusterOrgName, client.ClusterOVDCIdentifier, err)
+		}
+	} else if previous := client.Session(); previous != nil {
+		vdc = previous.VDC
+	}
+
+	swaggerConfig37 := swaggerClient37.NewConfiguration()
+	swaggerConfig37.BasePath = fmt.Sprintf("%s/cloudapi", authConfig.Host)
+	swaggerConfig37.AddDefaultHeader("Authorization", fmt.Sprintf("Bearer %s", token))
+	swaggerConfig37.HTTPClient = &http.Client{
+		Transport: newSessionTransport(transport, validity, token, client.refreshSession),
+	}
+
+	expiry, ok := GetTokenExpiry(token)
+	if !ok {
+		expiry = time.Now().Add(defaultSessionLifetime)
+	}
+	validity.expiry.Store(expiry.UnixNano())
+
+	client.publishSession(&Session{
+		VCDAuthConfig: authConfig,
+		VCDClient:     vcdClient,
+		APIClient:     swaggerClient37.NewAPIClient(swaggerConfig37),
+		VDC:           vdc,
+		validity:      validity,
+	})
+	validity.published.Store(true)
+	client.lookups.clear()
+	maxConcurrentGatewayOperations := DefaultMaxConcurrentGatewayOperations
+	if authConfig.Transport != nil && authConfig.Transport.MaxConcurrentGatewayOperations > 0 {
+		maxConcurrentGatewayOperations = authConfig.Transport.MaxConcurrentGatewayOperations
+	}
+	client.gatewayLocks.setLimit(maxConcurrentGatewayOperations)
+
+	klog.Infof("vcd session of user [%s/%s] is valid until [%v]", authConfig.UserOrg, authConfig.User, expiry)
+	return nil
+}
+
+// setRefreshToken publishes a copy of the current session of the client whose auth config has the refresh token of
+// the service account with clientID. It is used when an authentication rotated the refresh token but its session
+// could not be used, since the previous refresh token cannot be used again. The caller should hold the session lock.
+func (client *Client) setRefreshToken(clientID string, refreshToken string) {
+	current := client.Session()
+	if current == nil || current.VCDAuthConfig.ServiceAccountClientID != clientID ||
+		current.VCDAuthConfig.RefreshToken == refreshToken {
+		return
+	}
+	authConfig := *current.VCDAuthConfig
+	authConfig.RefreshToken = refreshToken
+	session := *current
+	session.VCDAuthConfig = &authConfig
+	client.publishSession(&session)
+}
+
+// publishSession makes session the session of the client. The deprecated session fields of the client are updated
+// for callers that still read them.
+func (client *Client) publishSession(session *Session) {
+	client.session.Store(session)
+	client.VCDAuthConfig = session.VCDAuthConfig
+	client.VCDClient = session.VCDClient
+	client.VDC = session.VDC
+	client.APIClient = session.APIClient
+}
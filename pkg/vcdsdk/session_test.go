@@ -0,0 +1,197 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+)
+
+func TestGetTokenExpiry(t *testing.T) {
+
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	header := encode(`{"alg":"RS256","typ":"JWT"}`)
+
+	expiry, ok := GetTokenExpiry(header + "." + encode(`{"sub":"user1","exp":1700000000}`) + ".signature")
+	assert.True(t, ok, "token with exp claim should have an expiry")
+	assert.Equal(t, time.Unix(1700000000, 0), expiry, "expiry should be the exp claim")
+
+	_, ok = GetTokenExpiry(header + "." + encode(`{"sub":"user1"}`) + ".signature")
+	assert.False(t, ok, "token without exp claim should not have an expiry")
+
+	_, ok = GetTokenExpiry("c2Vzc2lvbi10b2tlbg==")
+	assert.False(t, ok, "opaque token should not have an expiry")
+
+	_, ok = GetTokenExpiry(header + ".not-json.signature")
+	assert.False(t, ok, "token with invalid claims should not have an expiry")
+
+	return
+}
+
+func TestVCDSessionValidity(t *testing.T) {
+
+	now := time.Now()
+	var nilSession *sessionValidity = nil
+	assert.False(t, nilSession.isValid(now), "missing session should not be valid")
+
+	session := &sessionValidity{}
+	session.expiry.Store(now.Add(time.Hour).UnixNano())
+	assert.True(t, session.isValid(now), "session far from expiry should be valid")
+
+	session.expiry.Store(now.Add(sessionRefreshMargin / 2).UnixNano())
+	assert.False(t, session.isValid(now), "session about to expire should be refreshed")
+
+	session.expiry.Store(now.Add(time.Hour).UnixNano())
+	session.invalidate()
+	assert.False(t, session.isValid(now), "invalidated session should not be valid")
+
+	return
+}
+
+func TestSessionTransport(t *testing.T) {
+
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	session := &sessionValidity{}
+	session.expiry.Store(time.Now().Add(time.Hour).UnixNano())
+	httpClient := &http.Client{
+		Transport: newSessionTransport(nil, session, "", nil),
+	}
+
+	resp, err := httpClient.Get(server.URL)
+	assert.NoError(t, err, "request should succeed")
+	assert.NoError(t, resp.Body.Close())
+	assert.True(t, session.isValid(time.Now()), "session should stay valid after an authorized request")
+
+	status = http.StatusUnauthorized
+	resp, err = httpClient.Get(server.URL)
+	assert.NoError(t, err, "request should succeed")
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "response should be passed to the caller")
+	assert.False(t, session.isValid(time.Now()), "session should be invalidated after an unauthorized request")
+
+	return
+}
+
+func TestSessionTransportRetry(t *testing.T) {
+
+	numRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numRequests++
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err, "body should be read")
+		assert.Equal(t, "rule1", string(body), "body of request should be sent")
+		if r.Header.Get("Authorization") != "Bearer token2" || r.Header.Get(govcd.AuthorizationHeader) != "token2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	refreshedSession := &Session{
+		VCDClient: &govcd.VCDClient{
+			Client: govcd.Client{
+				VCDToken: "token2",
+			},
+		},
+		validity: &sessionValidity{},
+	}
+	refreshedSession.validity.expiry.Store(time.Now().Add(time.Hour).UnixNano())
+	numRefreshes := 0
+	refreshSession := func() (*Session, error) {
+		numRefreshes++
+		return refreshedSession, nil
+	}
+
+	validity := &sessionValidity{}
+	validity.expiry.Store(time.Now().Add(time.Hour).UnixNano())
+	httpClient := &http.Client{
+		Transport: newSessionTransport(nil, validity, "token1", refreshSession),
+	}
+	newRequest := func() *http.Request {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("rule1"))
+		assert.NoError(t, err, "request should be created")
+		req.Header.Add("Authorization", "Bearer token1")
+		req.Header.Add(govcd.AuthorizationHeader, "token1")
+		return req
+	}
+
+	// requests of a session that is not yet published are not retried
+	resp, err := httpClient.Do(newRequest())
+	assert.NoError(t, err, "request should succeed")
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "request should not be retried")
+	assert.Equal(t, 0, numRefreshes, "session should not be refreshed before it is published")
+
+	validity.published.Store(true)
+	numRequests = 0
+	resp, err = httpClient.Do(newRequest())
+	assert.NoError(t, err, "request should succeed")
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "request should be retried with the refreshed token")
+	assert.Equal(t, 2, numRequests, "unauthorized request should be retried once")
+	assert.Equal(t, 1, numRefreshes, "session should be refreshed")
+	assert.False(t, validity.isValid(time.Now()), "unauthorized session should be invalidated")
+
+	refreshedSession.VCDClient.Client.VCDToken = "token3"
+	numRequests = 0
+	resp, err = httpClient.Do(newRequest())
+	assert.NoError(t, err, "request should succeed")
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "unauthorized retry should be returned")
+	assert.Equal(t, 2, numRequests, "unauthorized request should be retried only once")
+	assert.False(t, refreshedSession.isValid(time.Now()), "refreshed session should be invalidated")
+
+	return
+}
+
+func TestPublishSessionUpdatesDeprecatedFields(t *testing.T) {
+
+	client := &Client{}
+	client.publishSession(&Session{
+		VCDAuthConfig: &VCDAuthConfig{ServiceAccountClientID: "client1", RefreshToken: "token1"},
+		VCDClient:     &govcd.VCDClient{},
+		VDC:           &govcd.Vdc{},
+	})
+	assert.Same(t, client.Session().VCDClient, client.VCDClient, "govcd client of the session should be set")
+	assert.Same(t, client.Session().VDC, client.VDC, "VDC of the session should be set")
+
+	client.setRefreshToken("client1", "token2")
+	assert.Equal(t, "token2", client.Session().VCDAuthConfig.RefreshToken, "rotated refresh token should be set")
+	assert.Same(t, client.Session().VCDAuthConfig, client.VCDAuthConfig,
+		"auth config of the current session should be set")
+
+	return
+}
+
+func TestGetServiceAccountTokenURL(t *testing.T) {
+
+	vcdHREF, err := url.Parse("https://vcd.example.com/api")
+	assert.NoError(t, err, "unable to parse url")
+
+	assert.Equal(t, "https://vcd.example.com/oauth/tenant/org1/token", GetServiceAccountTokenURL(*vcdHREF, "org1"),
+		"tenant service account should use the tenant endpoint")
+	assert.Equal(t, "https://vcd.example.com/oauth/provider/token", GetServiceAccountTokenURL(*vcdHREF, "System"),
+		"system service account should use the provider endpoint")
+
+	return
+}
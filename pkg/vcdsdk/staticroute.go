@@ -0,0 +1,224 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	swaggerClient "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdswaggerclient_37_2"
+	"k8s.io/klog"
+)
+
+const (
+	staticRouteClusterTag = "cluster="
+	staticRouteNodeTag    = "node="
+)
+
+// StaticRouteRef is a static route of the edge gateway that forwards a pod CIDR to a node of a cluster.
+type StaticRouteRef struct {
+	ID          string
+	Name        string
+	NetworkCidr string
+	NextHopIP   string
+	NodeName    string
+}
+
+// GetStaticRouteName returns the name of the static route of a node. The name is only informational; routes are
+// identified through the cluster and node tags in their description.
+func GetStaticRouteName(trimmedClusterID string, nodeName string) string {
+	return fmt.Sprintf("route-%s-%s", nodeName, trimmedClusterID)
+}
+
+// getStaticRouteDescription tags a static route with the cluster and node it belongs to so that several
+// clusters can share a gateway.
+func getStaticRouteDescription(clusterID string, nodeName string) string {
+	return fmt.Sprintf("%s%s %s%s", staticRouteClusterTag, clusterID, staticRouteNodeTag, nodeName)
+}
+
+// parseStaticRouteDescription returns the cluster and node tags of a static route description, and false if the
+// route is not tagged.
+func parseStaticRouteDescription(description string) (string, string, bool) {
+	clusterID, nodeName := "", ""
+	for _, field := range strings.Fields(description) {
+		if strings.HasPrefix(field, staticRouteClusterTag) {
+			clusterID = strings.TrimPrefix(field, staticRouteClusterTag)
+		} else if strings.HasPrefix(field, staticRouteNodeTag) {
+			nodeName = strings.TrimPrefix(field, staticRouteNodeTag)
+		}
+	}
+	if clusterID == "" || nodeName == "" {
+		return "", "", false
+	}
+	return clusterID, nodeName, true
+}
+
+// ListClusterStaticRoutes returns the static routes of the gateway that are tagged with clusterID.
+func (gm *GatewayManager) ListClusterStaticRoutes(ctx context.Context, clusterID string) ([]*StaticRouteRef, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.ListClusterStaticRoutes")
+	defer span.End()
+
+	if gm.GatewayRef == nil {
+		return nil, fmt.Errorf("gateway reference should not be nil")
+	}
+	client := gm.Client
+	session := client.Session()
+
+	staticRoutes, err := listAllPages(cursorPagination, MaxPageSize,
+		func(page pageRequest) ([]swaggerClient.EdgeStaticRoute, int32, *http.Response, error) {
+			staticRoutes, resp, err := session.APIClient.EdgeGatewayStaticRoutesApi.GetStaticRoutes(ctx, page.PageSize,
+				gm.GatewayRef.Id, &swaggerClient.EdgeGatewayStaticRoutesApiGetStaticRoutesOpts{
+					Cursor: page.Cursor,
+				})
+			return staticRoutes.Values, 0, resp, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get static routes of gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
+
+	staticRouteRefs := make([]*StaticRouteRef, 0)
+	for _, staticRoute := range staticRoutes {
+		routeClusterID, nodeName, ok := parseStaticRouteDescription(staticRoute.Description)
+		if !ok || routeClusterID != clusterID {
+			continue
+		}
+		nextHopIP := ""
+		if len(staticRoute.NextHops) > 0 {
+			nextHopIP = staticRoute.NextHops[0].IpAddress
+		}
+		staticRouteRefs = append(staticRouteRefs, &StaticRouteRef{
+			ID:          staticRoute.Id,
+			Name:        staticRoute.Name,
+			NetworkCidr: staticRoute.NetworkCidr,
+			NextHopIP:   nextHopIP,
+			NodeName:    nodeName,
+		})
+	}
+
+	return staticRouteRefs, nil
+}
+
+// getClusterStaticRoute returns nil if there is no static route of the node for destinationCIDR.
+func (gm *GatewayManager) getClusterStaticRoute(ctx context.Context, clusterID string, nodeName string,
+	destinationCIDR string) (*StaticRouteRef, error) {
+	ctx, span := startSpan(ctx, "GatewayManager.getClusterStaticRoute")
+	defer span.End()
+
+	staticRouteRefs, err := gm.ListClusterStaticRoutes(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, staticRouteRef := range staticRouteRefs {
+		if staticRouteRef.NodeName == nodeName && staticRouteRef.NetworkCidr == destinationCIDR {
+			return staticRouteRef, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateClusterStaticRoute creates a static route forwarding destinationCIDR to nextHopIP. If the route of the node
+// already exists, its next hop is updated if needed.
+func (gm *GatewayManager) CreateClusterStaticRoute(ctx context.Context, clusterID string, trimmedClusterID string,
+	nodeName string, destinationCIDR string, nextHopIP string) error {
+	ctx, span := startSpan(ctx, "GatewayManager.CreateClusterStaticRoute")
+	defer span.End()
+
+	if gm.GatewayRef == nil {
+		return fmt.Errorf("gateway reference should not be nil")
+	}
+	client := gm.Client
+	session := client.Session()
+	unlockGateway, err := client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return fmt.Errorf("unable to lock gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
+	defer unlockGateway()
+
+	staticRouteRef, err := gm.getClusterStaticRoute(ctx, clusterID, nodeName, destinationCIDR)
+	if err != nil {
+		return fmt.Errorf("unable to get static route of node [%s] for [%s]: [%w]", nodeName, destinationCIDR, err)
+	}
+
+	staticRoute := swaggerClient.EdgeStaticRoute{
+		Name:        GetStaticRouteName(trimmedClusterID, nodeName),
+		Description: getStaticRouteDescription(clusterID, nodeName),
+		NetworkCidr: destinationCIDR,
+		NextHops: []swaggerClient.EdgeStaticRouteNextHop{
+			{
+				IpAddress: nextHopIP,
+			},
+		},
+	}
+
+	if staticRouteRef != nil {
+		if staticRouteRef.NextHopIP == nextHopIP {
+			klog.Infof("static route [%s] for [%s] via [%s] already exists", staticRouteRef.Name, destinationCIDR,
+				nextHopIP)
+			return nil
+		}
+
+		existingStaticRoute, resp, err := session.APIClient.EdgeGatewayStaticRoutesApi.GetStaticRoute(ctx,
+			gm.GatewayRef.Id, staticRouteRef.ID)
+		if err != nil {
+			return fmt.Errorf("unable to get static route [%s]: resp: [%+v]: [%w]", staticRouteRef.Name, resp, err)
+		}
+		staticRoute.Id = existingStaticRoute.Id
+		staticRoute.Version = existingStaticRoute.Version
+		resp, err = session.APIClient.EdgeGatewayStaticRoutesApi.UpdateStaticRoute(ctx, staticRoute,
+			gm.GatewayRef.Id, staticRouteRef.ID)
+		if err = gm.waitForGatewayUpdate(ctx, resp, err, fmt.Sprintf("static route [%s]", staticRoute.Name)); err != nil {
+			return err
+		}
+		klog.Infof("updated static route [%s] for [%s] to next hop [%s]", staticRoute.Name, destinationCIDR,
+			nextHopIP)
+		return nil
+	}
+
+	resp, err := session.APIClient.EdgeGatewayStaticRoutesApi.CreateStaticRoute(ctx, staticRoute, gm.GatewayRef.Id)
+	if err = gm.waitForGatewayUpdate(ctx, resp, err, fmt.Sprintf("static route [%s]", staticRoute.Name)); err != nil {
+		return err
+	}
+	klog.Infof("created static route [%s] for [%s] via [%s]", staticRoute.Name, destinationCIDR, nextHopIP)
+	return nil
+}
+
+// DeleteClusterStaticRoute deletes the static route of the node for destinationCIDR. It is not an error if the
+// route does not exist.
+func (gm *GatewayManager) DeleteClusterStaticRoute(ctx context.Context, clusterID string, nodeName string,
+	destinationCIDR string) error {
+	ctx, span := startSpan(ctx, "GatewayManager.DeleteClusterStaticRoute")
+	defer span.End()
+
+	if gm.GatewayRef == nil {
+		return fmt.Errorf("gateway reference should not be nil")
+	}
+	client := gm.Client
+	session := client.Session()
+	unlockGateway, err := client.lockGateway(ctx, gm.GatewayRef.Id)
+	if err != nil {
+		return fmt.Errorf("unable to lock gateway [%s]: [%w]", gm.GatewayRef.Name, err)
+	}
+	defer unlockGateway()
+
+	staticRouteRef, err := gm.getClusterStaticRoute(ctx, clusterID, nodeName, destinationCIDR)
+	if err != nil {
+		return fmt.Errorf("unable to get static route of node [%s] for [%s]: [%w]", nodeName, destinationCIDR, err)
+	}
+	if staticRouteRef == nil {
+		klog.Infof("static route of node [%s] for [%s] does not exist", nodeName, destinationCIDR)
+		return nil
+	}
+
+	resp, err := session.APIClient.EdgeGatewayStaticRoutesApi.DeleteStaticRoute(ctx, gm.GatewayRef.Id,
+		staticRouteRef.ID)
+	if err = gm.waitForGatewayUpdate(ctx, resp, err, fmt.Sprintf("static route [%s]", staticRouteRef.Name)); err != nil {
+		return err
+	}
+	klog.Infof("deleted static route [%s] for [%s]", staticRouteRef.Name, destinationCIDR)
+	return nil
+}
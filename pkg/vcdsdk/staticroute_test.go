@@ -0,0 +1,29 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticRouteDescription(t *testing.T) {
+
+	description := getStaticRouteDescription("urn:vcloud:entity:vmware:capvcdCluster:1234", "worker-0.example.com")
+	clusterID, nodeName, ok := parseStaticRouteDescription(description)
+	assert.True(t, ok, "description of a cluster route should be parsed")
+	assert.Equal(t, "urn:vcloud:entity:vmware:capvcdCluster:1234", clusterID, "cluster tag should be parsed")
+	assert.Equal(t, "worker-0.example.com", nodeName, "node tag should be parsed")
+
+	_, _, ok = parseStaticRouteDescription("route added by the tenant")
+	assert.False(t, ok, "untagged route should not be parsed")
+
+	_, _, ok = parseStaticRouteDescription("cluster=abc")
+	assert.False(t, ok, "route without node tag should not be parsed")
+
+	return
+}
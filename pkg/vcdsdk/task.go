@@ -0,0 +1,123 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+const (
+	// DefaultTaskTimeout is the time to wait for a task of VCD to complete if the context of the caller has no deadline
+	DefaultTaskTimeout = 15 * time.Minute
+)
+
+// taskPollBackoff is the backoff between polls of the status of a task. Once the cap is reached, the task is polled at
+// the cap until it completes or the context is done.
+var taskPollBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   1.5,
+	Jitter:   0.1,
+	Steps:    10,
+	Cap:      10 * time.Second,
+}
+
+// waitTaskCompletion waits for task to complete and records the wait duration by the operation of the task. The task
+// is polled with backoff until it completes, fails or ctx is done. If ctx has no deadline, DefaultTaskTimeout is used.
+// A *TaskTimeoutError is returned if the deadline passes before the task completes.
+func waitTaskCompletion(ctx context.Context, task *govcd.Task) error {
+	ctx, span := startSpan(ctx, "WaitTaskCompletion")
+	startTime := time.Now()
+	err := pollTask(ctx, span, task, startTime)
+
+	// the task is refreshed while waiting, so its operation is known even if only its HREF was set
+	operation := taskOperation(task)
+	result := "success"
+	var taskTimeoutError *TaskTimeoutError
+	if errors.As(err, &taskTimeoutError) {
+		result = "timeout"
+	} else if err != nil {
+		result = "error"
+	}
+	taskWaitDuration.WithLabelValues(operation, result).Observe(time.Since(startTime).Seconds())
+	span.SetAttributes(attribute.String("vcd.task.operation", operation))
+	endSpan(span, err)
+	return err
+}
+
+func pollTask(ctx context.Context, span trace.Span, task *govcd.Task, startTime time.Time) error {
+	if task == nil || task.Task == nil {
+		return fmt.Errorf("unable to wait for task since the task is empty")
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultTaskTimeout)
+		defer cancel()
+	}
+
+	backoff := taskPollBackoff
+	progress := -1
+	for {
+		if err := task.Refresh(); err != nil {
+			return fmt.Errorf("unable to refresh task [%s]: [%w]", task.Task.HREF, err)
+		}
+
+		switch task.Task.Status {
+		case "success", "aborted":
+			return nil
+		case "error":
+			message := "unknown error"
+			if task.Task.Error != nil {
+				message = task.Task.Error.Message
+			}
+			return fmt.Errorf("task [%s] of operation [%s] did not complete successfully: [%s]", task.Task.HREF,
+				taskOperation(task), message)
+		}
+
+		if task.Task.Progress != progress {
+			progress = task.Task.Progress
+			klog.V(3).Infof("task [%s] of operation [%s] is [%s] at progress [%d%%] after [%v]", task.Task.HREF,
+				taskOperation(task), task.Task.Status, progress, time.Since(startTime).Round(time.Second))
+			span.AddEvent("TaskProgress", trace.WithAttributes(
+				attribute.String("vcd.task.status", task.Task.Status),
+				attribute.Int("vcd.task.progress", progress),
+			))
+		}
+
+		timer := time.NewTimer(backoff.Step())
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return &TaskTimeoutError{
+					TaskHREF:  task.Task.HREF,
+					Operation: taskOperation(task),
+					Progress:  task.Task.Progress,
+					Elapsed:   time.Since(startTime),
+					Err:       ctx.Err(),
+				}
+			}
+			return fmt.Errorf("gave up waiting for task [%s] of operation [%s]: [%w]", task.Task.HREF,
+				taskOperation(task), ctx.Err())
+		}
+	}
+}
+
+func taskOperation(task *govcd.Task) string {
+	if task == nil || task.Task == nil || task.Task.OperationName == "" {
+		return "unknown"
+	}
+	return task.Task.OperationName
+}
@@ -0,0 +1,92 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestWaitTaskCompletion(t *testing.T) {
+
+	savedBackoff := taskPollBackoff
+	taskPollBackoff = wait.Backoff{
+		Duration: time.Millisecond,
+		Factor:   2.0,
+		Steps:    4,
+		Cap:      5 * time.Millisecond,
+	}
+	defer func() { taskPollBackoff = savedBackoff }()
+
+	// the task of each path runs for the number of polls in the path, and then ends with the status of the path
+	var numPolls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		poll := atomic.AddInt32(&numPolls, 1)
+		status, body := "running", ""
+		switch r.URL.Path {
+		case "/task/success":
+			if poll >= 3 {
+				status = "success"
+			}
+		case "/task/error":
+			status = "error"
+			body = `<Error xmlns="http://www.vmware.com/vcloud/v1.5" message="no capacity" majorErrorCode="500"/>`
+		}
+		w.Header().Set("Content-Type", "application/vnd.vmware.vcloud.task+xml")
+		_, _ = fmt.Fprintf(w, `<Task xmlns="http://www.vmware.com/vcloud/v1.5" href="%s" status="%s" operationName="editGateway">%s<Progress>%d</Progress></Task>`,
+			"http://"+r.Host+r.URL.Path, status, body, poll*10)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL + "/api")
+	assert.NoError(t, err, "test server URL should be valid")
+	vcdClient := govcd.NewVCDClient(*serverURL, true)
+	newTask := func(path string) *govcd.Task {
+		atomic.StoreInt32(&numPolls, 0)
+		task := govcd.NewTask(&vcdClient.Client)
+		task.Task.HREF = server.URL + path
+		return task
+	}
+
+	err = waitTaskCompletion(context.Background(), newTask("/task/success"))
+	assert.NoError(t, err, "task should complete successfully")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&numPolls), "task should be polled until it completes")
+
+	err = waitTaskCompletion(context.Background(), newTask("/task/error"))
+	assert.Error(t, err, "failed task should return an error")
+	assert.Contains(t, err.Error(), "no capacity", "error should have the message of the task")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = waitTaskCompletion(ctx, newTask("/task/stuck"))
+	var taskTimeoutError *TaskTimeoutError
+	if assert.True(t, errors.As(err, &taskTimeoutError), "stuck task should return a task timeout error") {
+		assert.Equal(t, "editGateway", taskTimeoutError.Operation, "timeout error should have the operation of the task")
+		assert.Greater(t, taskTimeoutError.Progress, 0, "timeout error should have the progress of the task")
+	}
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "timeout error should wrap the context error")
+	assert.Equal(t, VCDErrorTimeout, GetVCDErrorKind(err), "timeout error should be of kind timeout")
+	assert.False(t, IsRetriableError(err), "timeout error should not be retriable")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = waitTaskCompletion(ctx, newTask("/task/stuck"))
+	assert.True(t, errors.Is(err, context.Canceled), "cancelled wait should return the context error")
+	assert.False(t, errors.As(err, &taskTimeoutError), "cancelled wait should not be a timeout")
+
+	return
+}
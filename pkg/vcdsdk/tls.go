@@ -0,0 +1,36 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// NewCertPoolFromPEM returns a pool of the certificates in the PEM bundle caBundle.
+func NewCertPoolFromPEM(caBundle []byte) (*x509.CertPool, error) {
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caBundle) {
+		return nil, fmt.Errorf("no valid PEM certificate found in CA bundle")
+	}
+	return certPool, nil
+}
+
+// newTLSConfig returns the TLS config that is used by the govcd and swagger clients to connect to the VCD site. The
+// certificate of the site is verified against the RootCAs of the transport, or the system CAs if there are none,
+// unless Insecure is set.
+func (config *VCDAuthConfig) newTLSConfig() *tls.Config {
+	var rootCAs *x509.CertPool = nil
+	if config.Transport != nil {
+		rootCAs = config.Transport.RootCAs
+	}
+	// #nosec G402 -- InsecureSkipVerify is only set if explicitly configured
+	return &tls.Config{
+		InsecureSkipVerify: config.Insecure,
+		RootCAs:            rootCAs,
+	}
+}
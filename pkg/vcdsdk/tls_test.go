@@ -0,0 +1,56 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCertPoolFromPEM(t *testing.T) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err, "unable to generate key")
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "vcd-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err, "unable to create certificate")
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+
+	certPool, err := NewCertPoolFromPEM(caBundle)
+	assert.NoError(t, err, "CA bundle should be parsed")
+	assert.NotNil(t, certPool, "pool should contain the CA")
+
+	_, err = NewCertPoolFromPEM([]byte("not a certificate"))
+	assert.Error(t, err, "bundle without certificates should be rejected")
+
+	authConfig := NewVCDAuthConfigFromSecrets("https://vcd.example.com", "user1", "password1", "", "org1", false)
+	authConfig.Transport = &TransportConfig{RootCAs: certPool}
+	tlsConfig := authConfig.newTLSConfig()
+	assert.False(t, tlsConfig.InsecureSkipVerify, "certificate should be verified")
+	assert.Equal(t, certPool, tlsConfig.RootCAs, "configured CAs should be trusted")
+
+	vcdClient, err := authConfig.newVCDClient()
+	assert.NoError(t, err, "unable to create govcd client")
+	assert.Equal(t, VCloudApiVersion_37_2, vcdClient.Client.APIVersion, "govcd client should use API version 37.2")
+
+	return
+}
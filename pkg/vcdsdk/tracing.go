@@ -0,0 +1,83 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "github.com/vmware/cloud-provider-for-cloud-director/pkg/vcdsdk"
+
+	// ClientRequestIDHeader is the header of the correlation ID that VCD logs with a request
+	ClientRequestIDHeader = "X-VMWARE-VCLOUD-CLIENT-REQUEST-ID"
+)
+
+// startSpan starts a span of an operation of the VCD clients as a child of the span in ctx. Spans are not recorded
+// unless a tracer provider is set up with otel.SetTracerProvider.
+func startSpan(ctx context.Context, spanName string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, spanName, trace.WithAttributes(attributes...))
+}
+
+// endSpan ends span and records err as its status.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// GetClientRequestID returns the correlation ID of the span in ctx as "<trace id>-<span id>", or an empty string if
+// ctx has no span.
+func GetClientRequestID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s", spanContext.TraceID().String(), spanContext.SpanID().String())
+}
+
+// requestIDTransport sets the correlation ID of the span of a request in the ClientRequestIDHeader, so that the traces
+// of the CCM can be matched with the logs of VCD.
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+func (transport *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if requestID := GetClientRequestID(req.Context()); requestID != "" && req.Header.Get(ClientRequestIDHeader) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(ClientRequestIDHeader, requestID)
+	}
+	return transport.base.RoundTrip(req)
+}
+
+// newTracingTransport returns a transport that records a span for each request that is made as part of a traced
+// operation. Requests of govcd carry no context and are only traced if they are.
+func newTracingTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(
+		&requestIDTransport{
+			base: base,
+		},
+		otelhttp.WithFilter(func(req *http.Request) bool {
+			return trace.SpanContextFromContext(req.Context()).IsValid()
+		}),
+		otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+			return fmt.Sprintf("%s %s", req.Method, GetEndpointLabel(req.URL.Path))
+		}),
+	)
+}
@@ -0,0 +1,56 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestClientRequestID(t *testing.T) {
+
+	requestID := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = r.Header.Get(ClientRequestIDHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{
+		Transport: &requestIDTransport{
+			base: http.DefaultTransport,
+		},
+	}
+
+	assert.Equal(t, "", GetClientRequestID(context.Background()), "context without span should have no request ID")
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+	resp, err := httpClient.Do(req)
+	assert.NoError(t, err, "request should succeed")
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, "", requestID, "request without span should have no request ID")
+
+	tracerProvider := sdktrace.NewTracerProvider()
+	ctx, span := tracerProvider.Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+	expectedRequestID := span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String()
+	assert.Equal(t, expectedRequestID, GetClientRequestID(ctx), "request ID should identify the span")
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+	resp, err = httpClient.Do(req)
+	assert.NoError(t, err, "request should succeed")
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, expectedRequestID, requestID, "request ID should be sent to VCD")
+	assert.Equal(t, "", req.Header.Get(ClientRequestIDHeader), "request of the caller should not be modified")
+
+	return
+}
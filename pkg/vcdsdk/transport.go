@@ -0,0 +1,170 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+)
+
+const (
+	// DefaultDialTimeout is the timeout to establish a connection to the VCD site or proxy if none is configured
+	DefaultDialTimeout = 30 * time.Second
+	// DefaultTLSHandshakeTimeout is the TLS handshake timeout if none is configured, the default of govcd
+	DefaultTLSHandshakeTimeout = 120 * time.Second
+	// DefaultMaxIdleConns is the number of idle connections to the VCD site that are kept if none is configured
+	DefaultMaxIdleConns = 10
+
+	transportKeepAlive       = 30 * time.Second
+	transportIdleConnTimeout = 90 * time.Second
+)
+
+// TransportConfig : contains the config of the HTTP transport shared by the govcd and swagger clients. Zero values
+// use the defaults.
+type TransportConfig struct {
+	// RootCAs are the CAs trusted for the VCD site; the system CAs are used if nil
+	RootCAs *x509.CertPool
+	// ProxyURL is the proxy through which the VCD site is reached; the proxy environment variables are used if empty
+	ProxyURL string
+	// NoProxy are the hosts, domains, IPs and CIDRs that are reached without the proxy of ProxyURL
+	NoProxy []string
+	// DialTimeout is the timeout to establish a connection
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout is the timeout of the TLS handshake
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout is the timeout to receive the response headers after the request was sent; none if 0
+	ResponseHeaderTimeout time.Duration
+	// MaxIdleConns is the number of idle connections to the VCD site that are kept for reuse
+	MaxIdleConns int
+	// QPS is the sustained rate of requests per second to the VCD site
+	QPS float32
+	// Burst is the number of requests that can be made at once above QPS
+	Burst int
+	// MaxConcurrentGatewayOperations is the number of operations that modify an edge gateway at the same time
+	MaxConcurrentGatewayOperations int
+}
+
+// MatchesNoProxy returns true if host is matched by an entry of noProxy. An entry matches the host itself and, unless
+// it is an IP or CIDR, its subdomains; "*" matches every host.
+func MatchesNoProxy(host string, noProxy []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	hostIP := net.ParseIP(host)
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		case strings.Contains(entry, "/"):
+			if _, cidr, err := net.ParseCIDR(entry); err == nil && hostIP != nil && cidr.Contains(hostIP) {
+				return true
+			}
+		case net.ParseIP(entry) != nil:
+			if hostIP != nil && net.ParseIP(entry).Equal(hostIP) {
+				return true
+			}
+		default:
+			domain := strings.TrimPrefix(entry, ".")
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getProxyFunc returns the proxy selection of the transport.
+func (transportConfig *TransportConfig) getProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if transportConfig.ProxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(transportConfig.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse proxy url [%s]: [%v]", transportConfig.ProxyURL, err)
+	}
+	noProxy := transportConfig.NoProxy
+	return func(req *http.Request) (*url.URL, error) {
+		if MatchesNoProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// newHTTPTransport returns the HTTP transport to the VCD site of the config.
+func (config *VCDAuthConfig) newHTTPTransport() (*http.Transport, error) {
+	transportConfig := config.Transport
+	if transportConfig == nil {
+		transportConfig = &TransportConfig{}
+	}
+
+	proxy, err := transportConfig.getProxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	dialTimeout := transportConfig.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = DefaultDialTimeout
+	}
+	tlsHandshakeTimeout := transportConfig.TLSHandshakeTimeout
+	if tlsHandshakeTimeout == 0 {
+		tlsHandshakeTimeout = DefaultTLSHandshakeTimeout
+	}
+	maxIdleConns := transportConfig.MaxIdleConns
+	if maxIdleConns == 0 {
+		maxIdleConns = DefaultMaxIdleConns
+	}
+
+	return &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: transportKeepAlive,
+		}).DialContext,
+		TLSClientConfig:       config.newTLSConfig(),
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ResponseHeaderTimeout: transportConfig.ResponseHeaderTimeout,
+		// all connections are to the VCD site
+		MaxIdleConns:        maxIdleConns,
+		MaxIdleConnsPerHost: maxIdleConns,
+		IdleConnTimeout:     transportIdleConnTimeout,
+	}, nil
+}
+
+// newVCDClient returns an unauthenticated govcd client for the VCD site of the config.
+func (config *VCDAuthConfig) newVCDClient() (*govcd.VCDClient, error) {
+	href := fmt.Sprintf("%s/api", config.Host)
+	u, err := url.ParseRequestURI(href)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse url [%s]: [%v]", href, err)
+	}
+
+	transport, err := config.newHTTPTransport()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create transport to [%s]: [%v]", config.Host, err)
+	}
+	transportConfig := config.Transport
+	if transportConfig == nil {
+		transportConfig = &TransportConfig{}
+	}
+	vcdClient := govcd.NewVCDClient(*u, config.Insecure)
+	// idempotent requests are retried, and every attempt is rate limited, if configured, and recorded
+	vcdClient.Client.Http.Transport = newRetryTransport(
+		newRateLimitTransport(newMetricsTransport(newTracingTransport(transport)),
+			config.rateLimiter.get(transportConfig.QPS, transportConfig.Burst)),
+		DefaultRetryBackoff)
+	vcdClient.Client.APIVersion = VCloudApiVersion_37_2
+	return vcdClient, nil
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+func TestMatchesNoProxy(t *testing.T) {
+
+	noProxy := []string{"vcd.example.com", ".internal.example.com", "10.0.0.0/8", "192.168.1.10", " "}
+
+	assert.True(t, MatchesNoProxy("vcd.example.com", noProxy), "host should match")
+	assert.True(t, MatchesNoProxy("VCD.example.com.", noProxy), "host should match case-insensitively")
+	assert.True(t, MatchesNoProxy("api.vcd.example.com", noProxy), "subdomain of host should match")
+	assert.True(t, MatchesNoProxy("internal.example.com", noProxy), "domain should match")
+	assert.True(t, MatchesNoProxy("vcd.internal.example.com", noProxy), "subdomain of domain should match")
+	assert.True(t, MatchesNoProxy("10.1.2.3", noProxy), "IP in CIDR should match")
+	assert.True(t, MatchesNoProxy("192.168.1.10", noProxy), "IP should match")
+
+	assert.False(t, MatchesNoProxy("example.com", noProxy), "parent domain should not match")
+	assert.False(t, MatchesNoProxy("othervcd.example.com", noProxy), "host with the same suffix should not match")
+	assert.False(t, MatchesNoProxy("192.168.1.11", noProxy), "other IP should not match")
+	assert.False(t, MatchesNoProxy("vcd.example.com", nil), "empty list should not match")
+
+	assert.True(t, MatchesNoProxy("vcd.example.com", []string{"*"}), "wildcard should match every host")
+
+	return
+}
+
+func TestNewHTTPTransport(t *testing.T) {
+
+	authConfig := NewVCDAuthConfigFromSecrets("https://vcd.example.com", "user1", "password1", "", "org1", false)
+	transport, err := authConfig.newHTTPTransport()
+	assert.NoError(t, err, "unable to create default transport")
+	assert.Equal(t, DefaultTLSHandshakeTimeout, transport.TLSHandshakeTimeout, "default timeout should be used")
+	assert.Equal(t, DefaultMaxIdleConns, transport.MaxIdleConnsPerHost, "default idle connections should be kept")
+
+	authConfig.Transport = &TransportConfig{
+		ProxyURL:              "http://proxy.example.com:3128",
+		NoProxy:               []string{"internal.example.com"},
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: time.Minute,
+		MaxIdleConns:          20,
+	}
+	transport, err = authConfig.newHTTPTransport()
+	assert.NoError(t, err, "unable to create transport")
+	assert.Equal(t, 10*time.Second, transport.TLSHandshakeTimeout, "configured timeout should be used")
+	assert.Equal(t, time.Minute, transport.ResponseHeaderTimeout, "configured timeout should be used")
+	assert.Equal(t, 20, transport.MaxIdleConnsPerHost, "configured idle connections should be kept")
+
+	req, err := http.NewRequest(http.MethodGet, "https://vcd.example.com/api", nil)
+	assert.NoError(t, err)
+	proxyURL, err := transport.Proxy(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "proxy.example.com:3128", proxyURL.Host, "VCD site should be reached through the proxy")
+
+	req, err = http.NewRequest(http.MethodGet, "https://vcd.internal.example.com/api", nil)
+	assert.NoError(t, err)
+	proxyURL, err = transport.Proxy(req)
+	assert.NoError(t, err)
+	assert.Nil(t, proxyURL, "host in no-proxy list should be reached directly")
+
+	vcdClient, err := authConfig.newVCDClient()
+	assert.NoError(t, err, "unable to create govcd client")
+	retryingTransport, ok := vcdClient.Client.Http.Transport.(*retryTransport)
+	assert.True(t, ok, "govcd client requests should be retried")
+	recordingTransport, ok := retryingTransport.base.(*metricsTransport)
+	assert.True(t, ok, "govcd client requests should not be rate limited if no QPS is configured")
+	_, ok = recordingTransport.base.(*otelhttp.Transport)
+	assert.True(t, ok, "govcd client requests should be traced")
+
+	authConfig.Transport.QPS = 5
+	vcdClient, err = authConfig.newVCDClient()
+	assert.NoError(t, err, "unable to create govcd client")
+	retryingTransport, ok = vcdClient.Client.Http.Transport.(*retryTransport)
+	assert.True(t, ok, "govcd client requests should be retried")
+	rateLimitingTransport, ok := retryingTransport.base.(*rateLimitTransport)
+	assert.True(t, ok, "govcd client requests should be rate limited")
+	assert.Equal(t, float32(5), rateLimitingTransport.rateLimiter.QPS(), "configured QPS should be used")
+	_, ok = rateLimitingTransport.base.(*metricsTransport)
+	assert.True(t, ok, "govcd client requests should be recorded")
+
+	return
+}
+
+func TestClientRateLimiter(t *testing.T) {
+
+	crl := &clientRateLimiter{}
+	assert.Nil(t, crl.get(0, 0), "requests should not be rate limited if no QPS is configured")
+
+	rateLimiter := crl.get(5, 0)
+	assert.NotNil(t, rateLimiter, "requests should be rate limited if a QPS is configured")
+	assert.Same(t, rateLimiter, crl.get(5, 0), "rate limiter should be reused by every session")
+	assert.NotSame(t, rateLimiter, crl.get(5, 10), "rate limiter should be replaced if the burst changes")
+	assert.Equal(t, float32(10), crl.get(10, 10).QPS(), "rate limiter should be replaced if the QPS changes")
+	assert.Nil(t, crl.get(0, 10), "rate limit should be removed if the QPS is removed")
+
+	var noClientRateLimiter *clientRateLimiter
+	assert.NotNil(t, noClientRateLimiter.get(5, 0), "auth config without client should get its own rate limiter")
+
+	return
+}
@@ -7,6 +7,7 @@ package vcdsdk
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"github.com/vmware/cloud-provider-for-cloud-director/pkg/util"
@@ -156,12 +157,8 @@ func NewVDCManager(client *Client, orgName string, vdcIdentifier string) (*VdcMa
 }
 
 func (vdc *VdcManager) cacheVdcDetails() error {
-	org, err := vdc.Client.VCDClient.GetOrgByName(vdc.OrgName)
-	if err != nil {
-		return fmt.Errorf("unable to get org from name [%s]: [%v]", vdc.OrgName, err)
-	}
-
-	vdc.Vdc, err = org.GetVDCByNameOrId(vdc.VdcIdentifier, true)
+	var err error
+	vdc.Vdc, err = vdc.Client.GetVDCByNameOrId(vdc.OrgName, vdc.VdcIdentifier)
 	if err != nil {
 		return fmt.Errorf("unable to get Vdc [%s] from org [%s]: [%v]", vdc.VdcIdentifier, vdc.OrgName, err)
 	}
@@ -209,14 +206,14 @@ func (vdc *VdcManager) DeleteVApp(VAppName string) error {
 		}
 
 		// Undeploy can fail if the vApp is not running. But VApp will be in a state where it can be deleted
-		err = task.WaitTaskCompletion()
+		err = waitTaskCompletion(context.Background(), &task)
 		if err != nil {
 			return fmt.Errorf("failed to delete vApp [%s]: [%v]", VAppName, err)
 		}
 		// Deletion successful
 		return nil
 	}
-	err = task.WaitTaskCompletion()
+	err = waitTaskCompletion(context.Background(), &task)
 	if err != nil {
 		return fmt.Errorf("error performing undeploy vApp task: %s", err)
 	}
@@ -224,7 +221,7 @@ func (vdc *VdcManager) DeleteVApp(VAppName string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete vApp [%s]: [%v]", VAppName, err)
 	}
-	err = task.WaitTaskCompletion()
+	err = waitTaskCompletion(context.Background(), &task)
 	if err != nil {
 		return fmt.Errorf("error performing delete vApp task: %s", err)
 	}
@@ -379,13 +376,14 @@ func (vdc *VdcManager) IsVmNotAvailable(err error) bool {
 
 // the returned extra configs is part of the returned vm
 func (vdc *VdcManager) getVmExtraConfigs(vm *govcd.VM) ([]*ExtraConfig, *Vm, error) {
+	session := vdc.Client.Session()
 	extraConfigVm := &Vm{}
 
 	if vm.VM.HREF == "" {
 		return nil, nil, fmt.Errorf("cannot refresh, invalid reference url")
 	}
 
-	_, err := vdc.Client.VCDClient.Client.ExecuteRequest(vm.VM.HREF, http.MethodGet,
+	_, err := session.VCDClient.Client.ExecuteRequest(vm.VM.HREF, http.MethodGet,
 		"", "error retrieving virtual hardware: %s", nil, extraConfigVm)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error executing GET request for vm: [%v]", err)
@@ -409,7 +407,8 @@ func (vdc *VdcManager) GetExtraConfigValue(vm *govcd.VM, key string) (string, er
 }
 
 func (vdc *VdcManager) getTaskFromResponse(resp *http.Response) (*govcd.Task, error) {
-	task := govcd.NewTask(&vdc.Client.VCDClient.Client)
+	session := vdc.Client.Session()
+	task := govcd.NewTask(&session.VCDClient.Client)
 	respBody, err := ioutil.ReadAll(resp.Body)
 	defer resp.Body.Close()
 
@@ -424,6 +423,7 @@ func (vdc *VdcManager) getTaskFromResponse(resp *http.Response) (*govcd.Task, er
 
 func (vdc *VdcManager) SetMultiVmExtraConfigKeyValuePairs(vm *govcd.VM, extraConfigMap map[string]string,
 	required bool) (govcd.Task, error) {
+	session := vdc.Client.Session()
 	_, extraConfigVm, err := vdc.getVmExtraConfigs(vm)
 	if err != nil {
 		return govcd.Task{}, fmt.Errorf("error retrieving vm extra configs: [%v]", err)
@@ -503,11 +503,11 @@ func (vdc *VdcManager) SetMultiVmExtraConfigKeyValuePairs(vm *govcd.VM, extraCon
 	if err != nil {
 		return govcd.Task{}, fmt.Errorf("error parsing request uri [%s]: [%v]", vm.VM.HREF+"/action/reconfigureVm", err)
 	}
-	req := vdc.Client.VCDClient.Client.NewRequest(map[string]string{}, http.MethodPost, *parsedUrl, reqBody)
+	req := session.VCDClient.Client.NewRequest(map[string]string{}, http.MethodPost, *parsedUrl, reqBody)
 	req.Header.Add("Content-Type", types.MimeVM)
 
 	// parse response
-	resp, err := vdc.Client.VCDClient.Client.Http.Do(req)
+	resp, err := session.VCDClient.Client.Http.Do(req)
 	if err != nil {
 		return govcd.Task{}, fmt.Errorf("error making request: [%v]", err)
 	}
@@ -562,6 +562,7 @@ EOF
 func (vdc *VdcManager) getTemplateHREFFromName(orgManager *OrgManager,
 	catalogName string, templateName string) (string, error) {
 
+	session := vdc.Client.Session()
 	catalog, err := orgManager.GetCatalogByName(catalogName)
 	if err != nil {
 		return "", fmt.Errorf("unable to find catalog [%s] in org [%s]: [%v]",
@@ -584,8 +585,8 @@ func (vdc *VdcManager) getTemplateHREFFromName(orgManager *OrgManager,
 		return "", fmt.Errorf("unable to get template of name [%s] in catalog [%s]",
 			templateName, catalogName)
 	}
-	vAppTemplate := govcd.NewVAppTemplate(&vdc.Client.VCDClient.Client)
-	resp, err := vdc.Client.VCDClient.Client.ExecuteRequest(queryVAppTemplate.HREF, http.MethodGet,
+	vAppTemplate := govcd.NewVAppTemplate(&session.VCDClient.Client)
+	resp, err := session.VCDClient.Client.ExecuteRequest(queryVAppTemplate.HREF, http.MethodGet,
 		"", "error retrieving vApp template: %s", nil, vAppTemplate.VAppTemplate)
 	if err != nil {
 		return "", fmt.Errorf("unable to issue get for template with HREF [%s]: [%v], resp = [%v]",
@@ -647,6 +648,7 @@ func (vdc *VdcManager) getStorageProfile(storageProfileName string) (*types.Refe
 func (vdc *VdcManager) AddNewVM(vmName string, VAppName string, catalogName string, templateName string,
 	placementPolicyName string, computePolicyName string, storageProfileName string, guestCustScript string) (govcd.Task, error) {
 
+	session := vdc.Client.Session()
 	if vdc.Vdc == nil {
 		return govcd.Task{}, fmt.Errorf("no Vdc created with name [%s]", vdc.VdcIdentifier)
 	}
@@ -744,7 +746,7 @@ func (vdc *VdcManager) AddNewVM(vmName string, VAppName string, catalogName stri
 
 	// execute the task to recomposeVApp
 	klog.V(3).Infof("START to compose VApp [%s] with VMs prefix [%s]", vApp.VApp.Name, vmName)
-	task, err := vdc.Client.VCDClient.Client.ExecuteTaskRequest(apiEndpoint.String(),
+	task, err := session.VCDClient.Client.ExecuteTaskRequest(apiEndpoint.String(),
 		http.MethodPost, types.MimeRecomposeVappParams, "error instantiating a new VM: [%s]",
 		vmDef)
 	if err != nil {
@@ -815,18 +817,19 @@ func (vdc *VdcManager) WaitForGuestScriptCompletion(VAppName, vmName string) err
 }
 
 func (vdc *VdcManager) RebootVm(vm *govcd.VM) error {
+	session := vdc.Client.Session()
 	klog.V(3).Infof("Rebooting VM. [%s]", vm.VM.Name)
 	rebootVmUrl, err := url.Parse(fmt.Sprintf("%s/power/action/reboot", vm.VM.HREF))
 	if err != nil {
 		return fmt.Errorf("failed to parse reboot VM api url for vm [%s]: [%v]", vm.VM.Name, err)
 	}
-	req := vdc.Client.VCDClient.Client.NewRequest(nil, http.MethodPost, *rebootVmUrl, nil)
+	req := session.VCDClient.Client.NewRequest(nil, http.MethodPost, *rebootVmUrl, nil)
 
-	resp, err := vdc.Client.VCDClient.Client.Http.Do(req)
+	resp, err := session.VCDClient.Client.Http.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to reboot VM [%s]: [%v]", vm.VM.Name, err)
 	}
-	vcdTask := govcd.NewTask(&vdc.Client.VCDClient.Client)
+	vcdTask := govcd.NewTask(&session.VCDClient.Client)
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return fmt.Errorf("failed to read response body: [%v]", err)
@@ -838,7 +841,7 @@ func (vdc *VdcManager) RebootVm(vm *govcd.VM) error {
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal the task output for reboot vm [%s]: [%v]", vm.VM.Name, err)
 	}
-	err = vcdTask.WaitTaskCompletion()
+	err = waitTaskCompletion(context.Background(), vcdTask)
 	if err != nil {
 		return fmt.Errorf("failed to reboot vm [%s]: [%v]", vm.VM.Name, err)
 	}
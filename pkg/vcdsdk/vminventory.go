@@ -0,0 +1,165 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/vmware/go-vcloud-director/v2/govcd"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+// getClusterVAppNameFilter returns the vApp name filter that matches the vApps of a cluster. In multi-zone clusters
+// the vApps are named <clusterVAppName>_<vdcUUID>... and are matched with a wildcard.
+func getClusterVAppNameFilter(clusterVAppName string, isMultiZoneCluster bool) string {
+	if isMultiZoneCluster {
+		return fmt.Sprintf("%s_*", clusterVAppName)
+	}
+	return clusterVAppName
+}
+
+// IsClusterVMRecord returns true if the VM of vmRecord is in a vApp of the cluster. In multi-zone clusters the vApp
+// name should be prefixed with the cluster vApp name and the UUID of the VDC of the VM.
+func IsClusterVMRecord(vmRecord *types.QueryResultVMRecordType, clusterVAppName string,
+	isMultiZoneCluster bool) bool {
+	if vmRecord == nil {
+		return false
+	}
+	if !isMultiZoneCluster {
+		return vmRecord.ContainerName == clusterVAppName
+	}
+
+	vdcUUID := path.Base(vmRecord.VdcHREF)
+	return strings.HasPrefix(vmRecord.ContainerName, fmt.Sprintf("%s_%s", clusterVAppName, vdcUUID))
+}
+
+// GetVMRecordFingerprint returns a string that changes when any of the VM record attributes that are reported for
+// nodes change. It is used to avoid fetching VMs whose record has not changed since they were last fetched.
+func GetVMRecordFingerprint(vmRecord *types.QueryResultVMRecordType) string {
+	if vmRecord == nil {
+		return ""
+	}
+	return strings.Join([]string{
+		vmRecord.Name,
+		vmRecord.ContainerName,
+		vmRecord.VdcName,
+		vmRecord.Status,
+		vmRecord.NetworkName,
+		vmRecord.IpAddress,
+		fmt.Sprintf("%d", vmRecord.Cpus),
+		fmt.Sprintf("%d", vmRecord.MemoryMB),
+		vmRecord.VmSizingPolicyId,
+		vmRecord.VmPlacementPolicyId,
+		vmRecord.StorageProfileName,
+	}, "|")
+}
+
+// ListClusterVMRecords lists the deployed VMs of all vApps of the cluster with a single paged query. In multi-zone
+// clusters, the vApps of all VDCs are matched through their name prefix.
+func (orgManager *OrgManager) ListClusterVMRecords(clusterVAppName string,
+	isMultiZoneCluster bool) ([]*types.QueryResultVMRecordType, error) {
+	session := orgManager.Client.Session()
+	if clusterVAppName == "" {
+		return nil, fmt.Errorf("cluster vApp name should not be empty")
+	}
+
+	vmRecordList, err := govcd.QueryVmList(types.VmQueryFilterOnlyDeployed, &session.VCDClient.Client,
+		map[string]string{"containerName": getClusterVAppNameFilter(clusterVAppName, isMultiZoneCluster)})
+	if err != nil {
+		return nil, fmt.Errorf("unable to query VMs of vApp [%s]: [%v]", clusterVAppName, err)
+	}
+
+	clusterVMRecordList := make([]*types.QueryResultVMRecordType, 0, len(vmRecordList))
+	for _, vmRecord := range vmRecordList {
+		if IsClusterVMRecord(vmRecord, clusterVAppName, isMultiZoneCluster) {
+			clusterVMRecordList = append(clusterVMRecordList, vmRecord)
+		}
+	}
+	return clusterVMRecordList, nil
+}
+
+// getVMMetadataFilterParams returns the query filter parameters that match VMs with a string metadata entry.
+func getVMMetadataFilterParams(metadataKey string, metadataValue string) map[string]string {
+	return map[string]string{
+		fmt.Sprintf("metadata:%s", metadataKey): fmt.Sprintf("STRING:%s", metadataValue),
+	}
+}
+
+// MergeVMRecordLists returns the VM records of all lists, without duplicate VMs. The first record of a VM is kept.
+func MergeVMRecordLists(vmRecordLists ...[]*types.QueryResultVMRecordType) []*types.QueryResultVMRecordType {
+	mergedVMRecordList := make([]*types.QueryResultVMRecordType, 0)
+	vmIDs := make(map[string]bool)
+	for _, vmRecordList := range vmRecordLists {
+		for _, vmRecord := range vmRecordList {
+			if vmRecord == nil || vmIDs[vmRecord.ID] {
+				continue
+			}
+			vmIDs[vmRecord.ID] = true
+			mergedVMRecordList = append(mergedVMRecordList, vmRecord)
+		}
+	}
+	return mergedVMRecordList
+}
+
+// ListVMRecordsByMetadata lists the deployed VMs of the org that have a metadata entry with metadataKey and
+// metadataValue, irrespective of their vApp, with a single paged query.
+func (orgManager *OrgManager) ListVMRecordsByMetadata(metadataKey string,
+	metadataValue string) ([]*types.QueryResultVMRecordType, error) {
+	session := orgManager.Client.Session()
+	if metadataKey == "" {
+		return nil, fmt.Errorf("metadata key should not be empty")
+	}
+
+	vmRecordList, err := govcd.QueryVmList(types.VmQueryFilterOnlyDeployed, &session.VCDClient.Client,
+		getVMMetadataFilterParams(metadataKey, metadataValue))
+	if err != nil {
+		return nil, fmt.Errorf("unable to query VMs with metadata [%s=%s]: [%v]", metadataKey, metadataValue, err)
+	}
+	return vmRecordList, nil
+}
+
+// SearchVMByMetadata finds the VM by name or ID among the VMs that have a metadata entry with metadataKey and
+// metadataValue. It returns the VM and the name of its VDC, or govcd.ErrorEntityNotFound.
+func (orgManager *OrgManager) SearchVMByMetadata(vmName string, vmId string, metadataKey string,
+	metadataValue string) (*govcd.VM, string, error) {
+	session := orgManager.Client.Session()
+	if metadataKey == "" {
+		return nil, "", fmt.Errorf("metadata key should not be empty")
+	}
+
+	searchParams := getVMMetadataFilterParams(metadataKey, metadataValue)
+	if vmName != "" {
+		searchParams["name"] = vmName
+	} else if vmId != "" {
+		searchParams["id"] = vmId
+	} else {
+		return nil, "", fmt.Errorf("unable to query VM when name and ID are both not provided")
+	}
+
+	vmRecordList, err := govcd.QueryVmList(types.VmQueryFilterOnlyDeployed, &session.VCDClient.Client,
+		searchParams)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to query VM [%s, %s] with metadata [%s=%s]: [%v]", vmName, vmId,
+			metadataKey, metadataValue, err)
+	}
+
+	for _, vmRecord := range vmRecordList {
+		// there is no need to correlate VM ID since it is unique across VCD
+		if vmName != "" && vmRecord.Name != vmName {
+			continue
+		}
+		vm, err := session.VCDClient.Client.GetVMByHref(vmRecord.HREF)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to find VM [%s, %s] by HREF [%s]: [%v]",
+				vmName, vmId, vmRecord.HREF, err)
+		}
+		return vm, vmRecord.VdcName, nil
+	}
+
+	return nil, "", govcd.ErrorEntityNotFound
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+func TestIsClusterVMRecord(t *testing.T) {
+
+	vdcUUID := "6e8b9f3a-1d2c-4b5a-8e7f-0a1b2c3d4e5f"
+	vmRecord := &types.QueryResultVMRecordType{
+		ContainerName: "cluster1",
+		VdcHREF:       "https://vcd.example.com/api/vdc/" + vdcUUID,
+	}
+
+	assert.True(t, IsClusterVMRecord(vmRecord, "cluster1", false), "VM in the cluster vApp should match")
+	assert.False(t, IsClusterVMRecord(vmRecord, "cluster", false), "VM in another vApp should not match")
+	assert.False(t, IsClusterVMRecord(vmRecord, "cluster1", true),
+		"multi-zone cluster vApp should be prefixed with the VDC UUID")
+
+	vmRecord.ContainerName = "cluster1_" + vdcUUID + "_1"
+	assert.True(t, IsClusterVMRecord(vmRecord, "cluster1", true), "VM in a zone vApp of the cluster should match")
+
+	vmRecord.ContainerName = "cluster1_0a1b2c3d-1d2c-4b5a-8e7f-6e8b9f3a4e5f"
+	assert.False(t, IsClusterVMRecord(vmRecord, "cluster1", true), "VM in a vApp of another VDC should not match")
+
+	assert.False(t, IsClusterVMRecord(nil, "cluster1", false), "nil record should not match")
+
+	return
+}
+
+func TestGetVMRecordFingerprint(t *testing.T) {
+
+	vmRecord := &types.QueryResultVMRecordType{
+		Name:          "node-1",
+		ContainerName: "cluster1",
+		Status:        "POWERED_ON",
+		IpAddress:     "10.0.0.10",
+		Cpus:          2,
+		MemoryMB:      4096,
+	}
+	fingerprint := GetVMRecordFingerprint(vmRecord)
+
+	unchangedRecord := *vmRecord
+	unchangedRecord.HostName = "esx-2"
+	assert.Equal(t, fingerprint, GetVMRecordFingerprint(&unchangedRecord),
+		"fingerprint should not depend on unreported attributes")
+
+	changedRecord := *vmRecord
+	changedRecord.IpAddress = "10.0.0.11"
+	assert.NotEqual(t, fingerprint, GetVMRecordFingerprint(&changedRecord), "fingerprint should change with the IP")
+
+	changedRecord = *vmRecord
+	changedRecord.Status = "POWERED_OFF"
+	assert.NotEqual(t, fingerprint, GetVMRecordFingerprint(&changedRecord),
+		"fingerprint should change with the status")
+
+	return
+}
+
+func TestMergeVMRecordLists(t *testing.T) {
+
+	vm1 := &types.QueryResultVMRecordType{ID: "vm-1", ContainerName: "cluster1"}
+	vm1Duplicate := &types.QueryResultVMRecordType{ID: "vm-1", ContainerName: "other"}
+	vm2 := &types.QueryResultVMRecordType{ID: "vm-2", ContainerName: "other"}
+
+	merged := MergeVMRecordLists([]*types.QueryResultVMRecordType{vm1}, []*types.QueryResultVMRecordType{vm1Duplicate,
+		vm2, nil})
+	assert.Equal(t, []*types.QueryResultVMRecordType{vm1, vm2}, merged,
+		"VMs should not be duplicated and the first record should be kept")
+
+	assert.Empty(t, MergeVMRecordLists(), "merging no lists should return no VMs")
+
+	return
+}
@@ -0,0 +1,43 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+// vmStatusShutdown maps the statuses of a VM to whether the VM is shut down. A VM is shut down only if it is cleanly
+// powered off, so that its disks can be safely detached. Suspended and partially powered VMs keep their guest state,
+// VMs waiting for input or busy with a task are still running, and VMs in an unknown state may be running; none of
+// these are reported as shut down so that the node is handled as a regular unready node.
+var vmStatusShutdown = map[string]bool{
+	"FAILED_CREATION":         true,
+	"UNRESOLVED":              false,
+	"RESOLVED":                true,
+	"DEPLOYED":                true,
+	"SUSPENDED":               false,
+	"POWERED_ON":              false,
+	"WAITING_FOR_INPUT":       false,
+	"UNKNOWN":                 false,
+	"UNRECOGNIZED":            false,
+	"POWERED_OFF":             true,
+	"INCONSISTENT_STATE":      false,
+	"MIXED":                   false,
+	"DESCRIPTOR_PENDING":      false,
+	"COPYING_CONTENTS":        false,
+	"DISK_CONTENTS_PENDING":   false,
+	"QUARANTINED":             false,
+	"QUARANTINE_EXPIRED":      false,
+	"REJECTED":                false,
+	"TRANSFER_TIMEOUT":        false,
+	"VAPP_UNDEPLOYED":         true,
+	"VAPP_PARTIALLY_DEPLOYED": false,
+	"PARTIALLY_POWERED_OFF":   false,
+	"PARTIALLY_SUSPENDED":     false,
+}
+
+// IsVMStatusShutdown returns whether a VM with the status is shut down, and false if the status is not known.
+// Unknown statuses are not reported as shut down.
+func IsVMStatusShutdown(status string) (bool, bool) {
+	shutdown, ok := vmStatusShutdown[status]
+	return shutdown, ok
+}
@@ -0,0 +1,38 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+)
+
+func TestIsVMStatusShutdown(t *testing.T) {
+
+	for _, status := range types.VAppStatuses {
+		_, known := IsVMStatusShutdown(status)
+		assert.True(t, known, "status [%s] should be mapped", status)
+	}
+
+	for _, status := range []string{"POWERED_OFF", "RESOLVED", "DEPLOYED", "VAPP_UNDEPLOYED"} {
+		shutdown, _ := IsVMStatusShutdown(status)
+		assert.True(t, shutdown, "VM with status [%s] should be shut down", status)
+	}
+
+	for _, status := range []string{"POWERED_ON", "SUSPENDED", "PARTIALLY_POWERED_OFF", "PARTIALLY_SUSPENDED",
+		"WAITING_FOR_INPUT", "UNRESOLVED", "INCONSISTENT_STATE"} {
+		shutdown, _ := IsVMStatusShutdown(status)
+		assert.False(t, shutdown, "VM with status [%s] should not be shut down", status)
+	}
+
+	shutdown, known := IsVMStatusShutdown("NEW_STATUS")
+	assert.False(t, known, "unexpected status should not be known")
+	assert.False(t, shutdown, "VM with unknown status should not be shut down")
+
+	return
+}
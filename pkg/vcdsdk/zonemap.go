@@ -2,10 +2,16 @@ package vcdsdk
 
 import (
 	"fmt"
-	"gopkg.in/yaml.v2"
-	"k8s.io/klog/v2"
+	"io"
 	"os"
 	"sync"
+	"time"
+
+	"github.com/vmware/cloud-provider-for-cloud-director/pkg/util"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
 )
 
 const (
@@ -15,7 +21,12 @@ const (
 type ZoneMap struct {
 	rwLock        sync.RWMutex
 	configMapFile string
-	VdcToZoneMap  map[string]string
+	// client and vdcGroupName are set if the zones are the VDCs of a VDC group instead of the config map file
+	client       *Client
+	vdcGroupName string
+	// zoneNames maps the names of VDCs of the VDC group to zone names; VDCs that are not mapped are their own zone
+	zoneNames    map[string]string
+	VdcToZoneMap map[string]string
 }
 
 type Zone struct {
@@ -41,10 +52,50 @@ func NewZoneMap(configMapFile string) (*ZoneMap, error) {
 	return zm, nil
 }
 
-func (zm *ZoneMap) ReloadZoneMap() error {
+// NewZoneMapFromVDCGroup returns a zone map with a zone for each VDC of the VDC group vdcGroupName in the org of the
+// cluster. Zones are named after their VDC unless the VDC is mapped to another name in zoneNames.
+func NewZoneMapFromVDCGroup(client *Client, vdcGroupName string, zoneNames map[string]string) (*ZoneMap, error) {
+	zm := &ZoneMap{
+		client:       client,
+		vdcGroupName: vdcGroupName,
+		zoneNames:    zoneNames,
+		VdcToZoneMap: make(map[string]string),
+	}
+
+	if err := zm.ReloadZoneMap(); err != nil {
+		return nil, fmt.Errorf("unable to load zones from VDC group [%s]: [%v]", vdcGroupName, err)
+	}
+
+	return zm, nil
+}
+
+// parseZoneConfigMap returns the map of VDC names to zones of the zone config map read from configReader. A config
+// map without zones, such as an empty or truncated file that is being rewritten, is rejected so that the zones of
+// all nodes are not lost.
+func parseZoneConfigMap(configReader io.Reader) (map[string]string, error) {
+	zoneConfigMap := &ZoneConfigMap{
+		ZoneType: "",
+	}
+	decoder := yaml.NewDecoder(configReader)
+	decoder.SetStrict(true)
+	if err := decoder.Decode(&zoneConfigMap); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(zoneConfigMap.Zones) == 0 {
+		return nil, fmt.Errorf("zone config map has no zones")
+	}
+
+	vdcToZoneMap := make(map[string]string)
+	for _, zone := range zoneConfigMap.Zones {
+		vdcToZoneMap[zone.OVDCName] = zone.Name
+	}
+	return vdcToZoneMap, nil
+}
+
+func (zm *ZoneMap) loadZoneMapFromFile() (map[string]string, error) {
 	configFileReader, err := os.Open(zm.configMapFile)
 	if err != nil {
-		return fmt.Errorf("unable to open file [%s]: [%v]", zm.configMapFile, err)
+		return nil, fmt.Errorf("unable to open file [%s]: [%v]", zm.configMapFile, err)
 	}
 	defer func() {
 		if err := configFileReader.Close(); err != nil {
@@ -52,21 +103,107 @@ func (zm *ZoneMap) ReloadZoneMap() error {
 		}
 	}()
 
-	zoneConfigMap := &ZoneConfigMap{
-		ZoneType: "",
+	vdcToZoneMap, err := parseZoneConfigMap(configFileReader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode zone configmap [%s]: [%v]", zm.configMapFile, err)
 	}
-	decoder := yaml.NewDecoder(configFileReader)
-	decoder.SetStrict(true)
-	if err = decoder.Decode(&zoneConfigMap); err != nil {
-		return fmt.Errorf("unable to decode zone configmap [%s]: [%v]", zm.configMapFile, err)
+	return vdcToZoneMap, nil
+}
+
+// GetVdcToZoneMapFromVDCGroup returns the map of the names of the VDCs of the VDC group to their zone. A VDC is its
+// own zone unless it is mapped to another name in zoneNames.
+func GetVdcToZoneMapFromVDCGroup(vdcGroup *types.VdcGroup, zoneNames map[string]string) map[string]string {
+	vdcToZoneMap := make(map[string]string)
+	if vdcGroup == nil {
+		return vdcToZoneMap
+	}
+	for _, participatingOrgVdc := range vdcGroup.ParticipatingOrgVdcs {
+		vdcName := participatingOrgVdc.VdcRef.Name
+		if vdcName == "" {
+			continue
+		}
+		if zoneName, ok := zoneNames[vdcName]; ok && zoneName != "" {
+			vdcToZoneMap[vdcName] = zoneName
+		} else {
+			vdcToZoneMap[vdcName] = vdcName
+		}
+	}
+	return vdcToZoneMap
+}
+
+func (zm *ZoneMap) loadZoneMapFromVDCGroup() (map[string]string, error) {
+	if err := zm.client.RefreshBearerToken(); err != nil {
+		return nil, fmt.Errorf("error while obtaining access token: [%v]", err)
+	}
+	session := zm.client.Session()
+	adminOrg, err := session.VCDClient.GetAdminOrgByName(zm.client.ClusterOrgName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get admin org [%s]: [%v]", zm.client.ClusterOrgName, err)
+	}
+	vdcGroup, err := adminOrg.GetVdcGroupByName(zm.vdcGroupName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get VDC group [%s] of org [%s]: [%v]", zm.vdcGroupName,
+			zm.client.ClusterOrgName, err)
+	}
+	return GetVdcToZoneMapFromVDCGroup(vdcGroup.VdcGroup, zm.zoneNames), nil
+}
+
+// ReloadZoneMap replaces the zones with the ones currently in the config map file or VDC group. Zones that were
+// removed are no longer reported. The zones are left unchanged if they cannot be loaded.
+func (zm *ZoneMap) ReloadZoneMap() error {
+	var vdcToZoneMap map[string]string
+	var err error
+	if zm.vdcGroupName != "" {
+		vdcToZoneMap, err = zm.loadZoneMapFromVDCGroup()
+	} else {
+		vdcToZoneMap, err = zm.loadZoneMapFromFile()
+	}
+	if err != nil {
+		return err
 	}
 
 	zm.rwLock.Lock()
 	defer zm.rwLock.Unlock()
 
-	for _, zone := range zoneConfigMap.Zones {
-		zm.VdcToZoneMap[zone.OVDCName] = zone.Name
+	for vdcName, zoneName := range vdcToZoneMap {
+		if existingZoneName, ok := zm.VdcToZoneMap[vdcName]; !ok || existingZoneName != zoneName {
+			klog.Infof("OVDC [%s] is in zone [%s]", vdcName, zoneName)
+		}
 	}
+	for vdcName, zoneName := range zm.VdcToZoneMap {
+		if _, ok := vdcToZoneMap[vdcName]; !ok {
+			klog.Infof("OVDC [%s] of zone [%s] was removed", vdcName, zoneName)
+		}
+	}
+	zm.VdcToZoneMap = vdcToZoneMap
 
 	return nil
 }
+
+// StartZoneMapReloader reloads the zones every reloadInterval until stopCh is closed, so that changes to the
+// mounted config map or to the VDC group are picked up without a restart.
+func (zm *ZoneMap) StartZoneMapReloader(reloadInterval time.Duration, stopCh <-chan struct{}) {
+	klog.Infof("reloading zones every [%v]", reloadInterval)
+	go wait.Until(func() {
+		if err := zm.ReloadZoneMap(); err != nil {
+			klog.Errorf("unable to reload zones; previous zones will be used: [%v]", err)
+		}
+	}, reloadInterval, stopCh)
+}
+
+// GetZoneForOVDC returns the zone of the OVDC ovdcName. The OVDC name itself is used as the zone if it is not
+// part of the zone map or if there is no zone map, so that every VM always has a zone. Since the zone is a label
+// value, the OVDC name is sanitized like the other label values; OVDC names may contain spaces.
+func (zm *ZoneMap) GetZoneForOVDC(ovdcName string) string {
+	if zm == nil {
+		return util.SanitizeLabelValue(ovdcName)
+	}
+
+	zm.rwLock.RLock()
+	defer zm.rwLock.RUnlock()
+
+	if zone, ok := zm.VdcToZoneMap[ovdcName]; ok && zone != "" {
+		return zone
+	}
+	return util.SanitizeLabelValue(ovdcName)
+}
@@ -0,0 +1,109 @@
+/*
+   Copyright 2021 VMware, Inc.
+   SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcdsdk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vcloud-director/v2/types/v56"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestGetZoneForOVDC(t *testing.T) {
+
+	var nilZoneMap *ZoneMap = nil
+	assert.Equal(t, "ovdc1", nilZoneMap.GetZoneForOVDC("ovdc1"),
+		"OVDC name should be the zone when there is no zone map")
+
+	zm := &ZoneMap{
+		VdcToZoneMap: map[string]string{
+			"ovdc1": "zone-a",
+			"ovdc2": "",
+		},
+	}
+	assert.Equal(t, "zone-a", zm.GetZoneForOVDC("ovdc1"), "zone should be looked up from the zone map")
+	assert.Equal(t, "ovdc2", zm.GetZoneForOVDC("ovdc2"), "OVDC name should be the zone for an empty zone")
+	assert.Equal(t, "ovdc3", zm.GetZoneForOVDC("ovdc3"), "OVDC name should be the zone for an unmapped OVDC")
+
+	// the zone is a label value, so OVDC names with spaces and OVDC IDs should be sanitized
+	for ovdcName, expectedZone := range map[string]string{
+		"Tenant OVDC 1": "Tenant-OVDC-1",
+		"urn:vcloud:vdc:09722307-aee0-4623-af95-7f8e577c9ebc": "urn-vcloud-vdc-09722307-aee0-4623-af95-7f8e577c9ebc",
+	} {
+		for _, zoneMap := range []*ZoneMap{nilZoneMap, zm} {
+			zone := zoneMap.GetZoneForOVDC(ovdcName)
+			assert.Equal(t, expectedZone, zone, "OVDC name [%s] should be sanitized", ovdcName)
+			assert.Empty(t, validation.IsValidLabelValue(zone), "zone [%s] should be a valid label value", zone)
+		}
+	}
+
+	return
+}
+
+func TestReloadZoneMapRemovesZones(t *testing.T) {
+
+	zoneFilePath := filepath.Join(t.TempDir(), "vcloud-cse-zones.yaml")
+	err := os.WriteFile(zoneFilePath, []byte(`zoneType: dcgroup
+zones:
+- name: zone-a
+  ovdcName: ovdc1
+- name: zone-b
+  ovdcName: ovdc2
+`), 0644)
+	assert.NoError(t, err, "unable to write zone file")
+
+	zm, err := NewZoneMap(zoneFilePath)
+	assert.NoError(t, err, "unable to load zone file")
+	assert.Equal(t, map[string]string{"ovdc1": "zone-a", "ovdc2": "zone-b"}, zm.VdcToZoneMap, "unexpected zones")
+
+	err = os.WriteFile(zoneFilePath, []byte(`zoneType: dcgroup
+zones:
+- name: zone-c
+  ovdcName: ovdc1
+`), 0644)
+	assert.NoError(t, err, "unable to write zone file")
+	assert.NoError(t, zm.ReloadZoneMap(), "unable to reload zone file")
+	assert.Equal(t, map[string]string{"ovdc1": "zone-c"}, zm.VdcToZoneMap, "removed zone should not be retained")
+	assert.Equal(t, "ovdc2", zm.GetZoneForOVDC("ovdc2"), "OVDC of removed zone should be its own zone")
+
+	err = os.WriteFile(zoneFilePath, []byte("zones: [\n"), 0644)
+	assert.NoError(t, err, "unable to write zone file")
+	assert.Error(t, zm.ReloadZoneMap(), "invalid zone file should fail to reload")
+	assert.Equal(t, map[string]string{"ovdc1": "zone-c"}, zm.VdcToZoneMap,
+		"zones should be retained if the zone file is invalid")
+
+	for _, content := range []string{"", "zoneType: dcgroup\nzones: []\n"} {
+		err = os.WriteFile(zoneFilePath, []byte(content), 0644)
+		assert.NoError(t, err, "unable to write zone file")
+		assert.Error(t, zm.ReloadZoneMap(), "zone file without zones should fail to reload")
+		assert.Equal(t, map[string]string{"ovdc1": "zone-c"}, zm.VdcToZoneMap,
+			"zones should be retained if the zone file has no zones")
+	}
+
+	return
+}
+
+func TestGetVdcToZoneMapFromVDCGroup(t *testing.T) {
+
+	vdcGroup := &types.VdcGroup{
+		Name: "dcgroup1",
+		ParticipatingOrgVdcs: []types.ParticipatingOrgVdcs{
+			{VdcRef: types.OpenApiReference{Name: "ovdc1"}},
+			{VdcRef: types.OpenApiReference{Name: "ovdc2"}},
+			{VdcRef: types.OpenApiReference{}},
+		},
+	}
+
+	assert.Equal(t, map[string]string{"ovdc1": "zone-a", "ovdc2": "ovdc2"},
+		GetVdcToZoneMapFromVDCGroup(vdcGroup, map[string]string{"ovdc1": "zone-a", "ovdc3": "zone-c"}),
+		"VDCs should be their own zone unless mapped")
+	assert.Empty(t, GetVdcToZoneMapFromVDCGroup(nil, nil), "nil VDC group should have no zones")
+
+	return
+}
@@ -60,7 +60,6 @@ var _ = Describe("Node LCM", func() {
 			vdcManager, err = vcdsdk.NewVDCManager(tc.VcdClient, org, vdcName)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(vdcManager).NotTo(BeNil())
-			tc.VcdClient.VDC = vdcManager.Vdc
 
 			machineSetName, ok := workerNode.Annotations[OwnerNameAnnotation]
 			Expect(ok).NotTo(BeFalse())
@@ -73,12 +72,11 @@ var _ = Describe("Node LCM", func() {
 			vdcManager, err = vcdsdk.NewVDCManager(tc.VcdClient, org, ovdcName)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(vdcManager).NotTo(BeNil())
-			tc.VcdClient.VDC = vdcManager.Vdc
 			workerNodeVAppName = tc.ClusterName
 		}
 
 		By("ensuring that vApp exists")
-		clusterVApp, err := tc.VcdClient.VDC.GetVAppByName(workerNodeVAppName, true)
+		clusterVApp, err := vdcManager.Vdc.GetVAppByName(workerNodeVAppName, true)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(clusterVApp).NotTo(BeNil())
 		Expect(clusterVApp.VApp).NotTo(BeNil())
@@ -102,7 +100,7 @@ var _ = Describe("Node LCM", func() {
 	})
 
 	It("should start the worker VM that was powered off in VCD", func() {
-		clusterVApp, err := tc.VcdClient.VDC.GetVAppByName(workerNodeVAppName, true)
+		clusterVApp, err := vdcManager.Vdc.GetVAppByName(workerNodeVAppName, true)
 		By("ensuring cluster vApp is present")
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(clusterVApp).NotTo(BeNil())
@@ -127,7 +125,7 @@ var _ = Describe("Node LCM", func() {
 
 	It("should stop and delete the the worker VM in VCD", func() {
 		By("ensuring cluster vApp is present")
-		clusterVApp, err := tc.VcdClient.VDC.GetVAppByName(workerNodeVAppName, true)
+		clusterVApp, err := vdcManager.Vdc.GetVAppByName(workerNodeVAppName, true)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(clusterVApp).NotTo(BeNil())
 		Expect(clusterVApp.VApp).NotTo(BeNil())
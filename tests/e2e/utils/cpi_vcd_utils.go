@@ -85,7 +85,7 @@ func HasVCDResourcesForApplicationLB(ctx context.Context, testClient *testingsdk
 			}
 
 			appPortProfileName := vcdsdk.GetAppPortProfileName(dnatRuleName)
-			org, err := testClient.VcdClient.VCDClient.GetOrgByName(testClient.VcdClient.ClusterOrgName)
+			org, err := testClient.VcdClient.Session().VCDClient.GetOrgByName(testClient.VcdClient.ClusterOrgName)
 			if err != nil {
 				return false, fmt.Errorf("unable to find org [%s] by name: [%v]", testClient.VcdClient.ClusterOrgName, err)
 			}